@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/_integration_tests/client/app"
+	"github.com/goadesign/goa/_integration_tests/client/app/test"
+	"github.com/goadesign/goa/_integration_tests/client/client"
+	goaclient "github.com/goadesign/goa/client"
+	"github.com/goadesign/goa/middleware"
+)
+
+func TestBoolValues(t *testing.T) {
+	service := goa.New("client")
+	ctrl := test.NewNoteControllerMock(service)
+	var archived, draft *bool
+	ctrl.ShowFunc = func(ctx *app.ShowNoteContext) error {
+		archived, draft = ctx.Archived, ctx.XDraft
+		return ctx.OK(&app.NoteMedia{Title: "title"})
+	}
+	srv := httptest.NewServer(test.NewTestServer(service, test.TestControllers{Note: ctrl}))
+	defer srv.Close()
+	c := client.New(goaclient.HTTPClientDoer(http.DefaultClient))
+	c.Host = strings.TrimPrefix(srv.URL, "http://")
+	yes, no := true, false
+
+	resp, err := c.ShowNote(context.Background(), client.ShowNotePath(), &yes, nil, &no)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("client request: got status %d", resp.StatusCode)
+	}
+	if archived == nil || !*archived || draft == nil || *draft {
+		t.Errorf("client request: got archived %v and draft %v", archived, draft)
+	}
+
+	test.ShowNoteOK(t, nil, service, ctrl, &no, nil, &yes)
+	if archived == nil || *archived || draft == nil || !*draft {
+		t.Errorf("test helper: got archived %v and draft %v", archived, draft)
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	service := goa.New("client")
+	ctrl := test.NewNoteControllerMock(service)
+	var title *string
+	ctrl.ShowFunc = func(ctx *app.ShowNoteContext) error {
+		title = ctx.TitleFilter
+		return ctx.OK(&app.NoteMedia{Title: "title"})
+	}
+	srv := httptest.NewServer(test.NewTestServer(service, test.TestControllers{Note: ctrl}))
+	defer srv.Close()
+	c := client.New(goaclient.HTTPClientDoer(http.DefaultClient))
+	c.Host = strings.TrimPrefix(srv.URL, "http://")
+	first, second := "first", "second"
+
+	resp, err := c.ShowNote(context.Background(), client.ShowNotePath(), nil, &first, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("client request: got status %d", resp.StatusCode)
+	}
+	if title == nil || *title != first {
+		t.Errorf("client request: got title %v", title)
+	}
+
+	test.ShowNoteOK(t, nil, service, ctrl, nil, &second, nil)
+	if title == nil || *title != second {
+		t.Errorf("test helper: got title %v", title)
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	service := goa.New("client")
+	ctrl := test.NewNoteControllerMock(service)
+	var user, pass string
+	ctrl.ArchiveFunc = func(ctx *app.ArchiveNoteContext) error {
+		user, pass = ctx.User, ctx.Pass
+		return ctx.OK(&app.NoteMedia{Title: "title"})
+	}
+	srv := httptest.NewServer(test.NewTestServer(service, test.TestControllers{Note: ctrl}))
+	defer srv.Close()
+	c := client.New(goaclient.HTTPClientDoer(http.DefaultClient))
+	c.Host = strings.TrimPrefix(srv.URL, "http://")
+
+	resp, err := c.ArchiveNote(context.Background(), client.ArchiveNotePath(), "secret", "joe", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("client request: got status %d", resp.StatusCode)
+	}
+	if user != "joe" || pass != "secret" {
+		t.Errorf("client request: got user %q and password %q", user, pass)
+	}
+
+	test.ArchiveNoteOK(t, nil, service, ctrl, "hunter2", nil, "ann")
+	if user != "ann" || pass != "hunter2" {
+		t.Errorf("test helper: got user %q and password %q", user, pass)
+	}
+}
+
+func TestParamSources(t *testing.T) {
+	service := goa.New("client")
+	ctrl := test.NewNoteControllerMock(service)
+	var tenant *string
+	var header string
+	ctrl.ArchiveFunc = func(ctx *app.ArchiveNoteContext) error {
+		tenant, header = ctx.Tenant, ctx.Request.Header.Get("X-Tenant-Id")
+		return ctx.OK(&app.NoteMedia{Title: "title"})
+	}
+	srv := httptest.NewServer(test.NewTestServer(service, test.TestControllers{Note: ctrl}))
+	defer srv.Close()
+	c := client.New(goaclient.HTTPClientDoer(http.DefaultClient))
+	c.Host = strings.TrimPrefix(srv.URL, "http://")
+	acme, initech := "acme", "initech"
+
+	resp, err := c.ArchiveNote(context.Background(), client.ArchiveNotePath(), "secret", "joe", &acme)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("client request: got status %d", resp.StatusCode)
+	}
+	if tenant == nil || *tenant != acme || header != acme {
+		t.Errorf("client request: got tenant %v and header %q", tenant, header)
+	}
+
+	test.ArchiveNoteOK(t, nil, service, ctrl, "hunter2", &initech, "ann")
+	if tenant == nil || *tenant != initech {
+		t.Errorf("test helper: got tenant %v", tenant)
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	service := goa.New("client")
+	service.Use(middleware.ErrorHandler(service, false))
+	ctrl := test.NewNoteControllerMock(service)
+	srv := httptest.NewServer(test.NewTestServer(service, test.TestControllers{Note: ctrl}))
+	defer srv.Close()
+	body := `{"title": "` + strings.Repeat("a", 64) + `"}`
+
+	// The request is chunked as the length of the reader is unknown
+	req, err := http.NewRequest("POST", srv.URL+client.CreateNotePath(), ioutil.NopCloser(strings.NewReader(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d", resp.StatusCode)
+	}
+}
+
+func TestMapServiceError(t *testing.T) {
+	internal := goa.ErrInternal("boom")
+	if status, body := app.MapServiceError(internal); status != http.StatusInternalServerError || body != internal {
+		t.Errorf("internal error: got status %d and body %v", status, body)
+	}
+	bad := goa.ErrBadRequest("bad")
+	if status, body := app.MapServiceError(bad); status != http.StatusBadRequest || body != bad {
+		t.Errorf("bad request error: got status %d and body %v", status, body)
+	}
+	if status, body := app.MapServiceError(errors.New("boom")); status != http.StatusInternalServerError {
+		t.Errorf("non goa error: got status %d and body %v", status, body)
+	} else if _, ok := body.(goa.ServiceError); !ok {
+		t.Errorf("non goa error: got body %#v", body)
+	}
+}
@@ -0,0 +1,102 @@
+package design
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = API("client", func() {
+	Title("An API exercising the generated client helpers")
+	Host("localhost:8080")
+	Scheme("http")
+})
+
+var Note = Type("Note", func() {
+	Attribute("title", String, func() {
+		Description("A string field trimmed and lowercased on decode.")
+		Metadata("transform:trim")
+		Metadata("transform:lower")
+	})
+	Attribute("tags", ArrayOf(String), func() {
+		Description("An array field that also accepts a single string.")
+		Metadata("json:single-value")
+	})
+	Required("title")
+})
+
+var NoteMedia = MediaType("application/vnd.note+json", func() {
+	TypeName("NoteMedia")
+	Reference(Note)
+
+	Attributes(func() {
+		Attribute("title")
+		Attribute("code", String, func() {
+			Description("A string field uppercased on decode.")
+			Metadata("transform:upper")
+		})
+		Required("title")
+	})
+
+	View("default", func() {
+		Attribute("title")
+		Attribute("code")
+	})
+})
+
+var _ = Resource("Note", func() {
+	DefaultMedia(NoteMedia)
+
+	Action("create", func() {
+		Routing(
+			POST("/"))
+		Payload(Note)
+		MaxBodySize(64)
+		Response(OK)
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("show", func() {
+		Routing(
+			GET("/"))
+		Params(func() {
+			Param("titleFilter", String, func() {
+				Description("A param read from the title query string parameter.")
+				Metadata("param:query", "title")
+			})
+			Param("archived", Boolean, func() {
+				Description("A boolean param using custom values.")
+				Metadata("param:bool-values", "yes", "no")
+			})
+		})
+		Headers(func() {
+			Header("X-Draft", Boolean, func() {
+				Description("A boolean header using custom values.")
+				Metadata("param:bool-values", "on", "off")
+			})
+		})
+		Response(OK)
+		Response(BadRequest, ErrorMedia)
+	})
+
+	Action("archive", func() {
+		Routing(
+			POST("/archive"))
+		Params(func() {
+			Param("user", String, func() {
+				Description("A param read from the basic auth username.")
+				Metadata("param:basic-auth", "username")
+			})
+			Param("pass", String, func() {
+				Description("A param read from the basic auth password.")
+				Metadata("param:basic-auth", "password")
+			})
+			Param("tenant", String, func() {
+				Description("A param read from a header or the query string.")
+				Metadata("param:sources", "header:X-Tenant-Id", "query")
+			})
+			Required("user", "pass")
+		})
+		Response(OK)
+		Response(BadRequest, ErrorMedia)
+	})
+})
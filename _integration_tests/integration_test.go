@@ -48,6 +48,20 @@ type CreateGreetingPayload struct {
 	}
 }
 
+func TestClient(t *testing.T) {
+	defer os.RemoveAll("./client/main.go")
+	defer os.RemoveAll("./client/tool")
+	if err := goagen("./client", "bootstrap", "-d", "github.com/goadesign/goa/_integration_tests/client/design"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gobuild("./client"); err != nil {
+		t.Error(err.Error())
+	}
+	if err := gotest("./client"); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestCellar(t *testing.T) {
 	if err := os.MkdirAll("./goa-cellar", 0755); err != nil {
 		t.Error(err.Error())
@@ -92,3 +106,13 @@ func gobuild(dir string) error {
 	}
 	return nil
 }
+
+func gotest(dir string) error {
+	cmd := exec.Command("go", "test", ".")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s\n%s", err.Error(), out)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"context"
+)
+
+// RetryDoer is a Doer that retries idempotent requests that fail with a connection error or a
+// response with a 5xx status code. The delay between two attempts starts at Backoff and doubles
+// after each attempt up to MaxBackoff. Generated clients use it by wrapping the Doer given to
+// their New function:
+//
+//	doer := client.NewRetryDoer(client.HTTPClientDoer(http.DefaultClient), 3)
+//	c := cellar.New(doer)
+//
+// All the requests made by the generated client action methods then go through the RetryDoer,
+// the methods themselves are unchanged. Requests that use other methods than GET, HEAD, OPTIONS,
+// PUT and DELETE are sent only once unless Retryable says otherwise.
+type RetryDoer struct {
+	// Doer sends the requests.
+	Doer Doer
+	// MaxRetries is the maximum number of times a request is retried.
+	MaxRetries int
+	// Backoff is the delay before the first retry, 100ms if zero.
+	Backoff time.Duration
+	// MaxBackoff is the maximum delay between two attempts, 10s if zero.
+	MaxBackoff time.Duration
+	// Retryable returns true if the given request may be sent more than once. The default
+	// only retries requests whose method is idempotent.
+	Retryable func(*http.Request) bool
+}
+
+// NewRetryDoer creates a RetryDoer that wraps d and retries idempotent requests up to maxRetries
+// times with the default backoff.
+func NewRetryDoer(d Doer, maxRetries int) *RetryDoer {
+	return &RetryDoer{Doer: d, MaxRetries: maxRetries}
+}
+
+// Do sends the request and retries it with exponential backoff if it fails. The response of the
+// last attempt is returned when all attempts fail. Do stops retrying and returns the context error
+// as soon as ctx is done.
+func (r *RetryDoer) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	retryable := r.Retryable
+	if retryable == nil {
+		retryable = IdempotentRequest
+	}
+	if r.MaxRetries <= 0 || !retryable(req) {
+		return r.Doer.Do(ctx, req)
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	backoff := r.Backoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	maxBackoff := r.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+	for attempt := 0; ; attempt++ {
+		if body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		resp, err := r.Doer.Do(ctx, req)
+		if attempt == r.MaxRetries || (err == nil && resp.StatusCode < 500) {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain the body so that the connection can be reused
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// IdempotentRequest returns true if the method of the request is GET, HEAD, OPTIONS, PUT or
+// DELETE.
+func IdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
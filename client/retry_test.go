@@ -0,0 +1,96 @@
+package client_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/goadesign/goa/client"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RetryDoer", func() {
+	var failures int
+	var attempts int
+	var bodies []string
+	var server *httptest.Server
+	var doer *client.RetryDoer
+
+	BeforeEach(func() {
+		attempts = 0
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if attempts <= failures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		doer = client.NewRetryDoer(client.HTTPClientDoer(http.DefaultClient), 3)
+		doer.Backoff = time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("with a server failing twice", func() {
+		BeforeEach(func() {
+			failures = 2
+		})
+
+		It("retries idempotent requests until they succeed", func() {
+			req, _ := http.NewRequest("PUT", server.URL, strings.NewReader("body"))
+			resp, err := doer.Do(context.Background(), req)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(resp.StatusCode).Should(Equal(http.StatusOK))
+			Ω(attempts).Should(Equal(3))
+			Ω(bodies).Should(Equal([]string{"body", "body", "body"}))
+		})
+
+		It("does not retry other requests", func() {
+			req, _ := http.NewRequest("POST", server.URL, nil)
+			resp, err := doer.Do(context.Background(), req)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(resp.StatusCode).Should(Equal(http.StatusServiceUnavailable))
+			Ω(attempts).Should(Equal(1))
+		})
+	})
+
+	Context("with a server failing more times than the maximum number of retries", func() {
+		BeforeEach(func() {
+			failures = 10
+		})
+
+		It("returns the response of the last attempt", func() {
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			resp, err := doer.Do(context.Background(), req)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(resp.StatusCode).Should(Equal(http.StatusServiceUnavailable))
+			Ω(attempts).Should(Equal(4))
+		})
+	})
+
+	Context("with a canceled context", func() {
+		BeforeEach(func() {
+			failures = 10
+		})
+
+		It("stops retrying", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req, _ := http.NewRequest("GET", server.URL, nil)
+			_, err := doer.Do(ctx, req)
+			Ω(err).Should(Equal(context.Canceled))
+			Ω(attempts).Should(Equal(1))
+		})
+	})
+})
@@ -19,6 +19,7 @@ const (
 	logContextKey
 	errKey
 	securityScopesKey
+	idempotencyKeyKey
 )
 
 type (
@@ -92,6 +93,12 @@ func WithError(ctx context.Context, err error) context.Context {
 	return context.WithValue(ctx, errKey, err)
 }
 
+// WithIdempotencyKey creates a context with the given idempotency key. The contexts generated for
+// actions that read the "Idempotency-Key" request header store its value using this function.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyKey, key)
+}
+
 // ContextController extracts the controller name from the given context.
 func ContextController(ctx context.Context) string {
 	if c := ctx.Value(ctrlKey); c != nil {
@@ -140,6 +147,16 @@ func ContextError(ctx context.Context) error {
 	return nil
 }
 
+// ContextIdempotencyKey extracts the value of the "Idempotency-Key" request header from the given
+// context. It returns the empty string if the request did not set the header or if the action does
+// not read it.
+func ContextIdempotencyKey(ctx context.Context) string {
+	if k := ctx.Value(idempotencyKeyKey); k != nil {
+		return k.(string)
+	}
+	return ""
+}
+
 // SwitchWriter overrides the underlying response writer. It returns the response
 // writer that was previously set.
 func (r *ResponseData) SwitchWriter(rw http.ResponseWriter) http.ResponseWriter {
@@ -40,3 +40,14 @@ var _ = Describe("ResponseData", func() {
 		})
 	})
 })
+
+var _ = Describe("ContextIdempotencyKey", func() {
+	It("returns the empty string if the context has no idempotency key", func() {
+		Ω(goa.ContextIdempotencyKey(context.Background())).Should(BeEmpty())
+	})
+
+	It("returns the key set with WithIdempotencyKey", func() {
+		ctx := goa.WithIdempotencyKey(context.Background(), "key")
+		Ω(goa.ContextIdempotencyKey(ctx)).Should(Equal("key"))
+	})
+})
@@ -88,31 +88,33 @@ var (
 	// KnownEncoders contains the list of encoding packages and factories known by goa indexed
 	// by MIME type.
 	KnownEncoders = map[string]string{
-		"application/json":      "github.com/goadesign/goa",
-		"application/xml":       "github.com/goadesign/goa",
-		"application/gob":       "github.com/goadesign/goa",
-		"application/x-gob":     "github.com/goadesign/goa",
-		"application/binc":      "github.com/goadesign/goa/encoding/binc",
-		"application/x-binc":    "github.com/goadesign/goa/encoding/binc",
-		"application/cbor":      "github.com/goadesign/goa/encoding/cbor",
-		"application/x-cbor":    "github.com/goadesign/goa/encoding/cbor",
-		"application/msgpack":   "github.com/goadesign/goa/encoding/msgpack",
-		"application/x-msgpack": "github.com/goadesign/goa/encoding/msgpack",
+		"application/json":            "github.com/goadesign/goa",
+		"application/json-patch+json": "github.com/goadesign/goa",
+		"application/xml":             "github.com/goadesign/goa",
+		"application/gob":             "github.com/goadesign/goa",
+		"application/x-gob":           "github.com/goadesign/goa",
+		"application/binc":            "github.com/goadesign/goa/encoding/binc",
+		"application/x-binc":          "github.com/goadesign/goa/encoding/binc",
+		"application/cbor":            "github.com/goadesign/goa/encoding/cbor",
+		"application/x-cbor":          "github.com/goadesign/goa/encoding/cbor",
+		"application/msgpack":         "github.com/goadesign/goa/encoding/msgpack",
+		"application/x-msgpack":       "github.com/goadesign/goa/encoding/msgpack",
 	}
 
 	// KnownEncoderFunctions contains the list of encoding encoder and decoder functions known
 	// by goa indexed by MIME type.
 	KnownEncoderFunctions = map[string][2]string{
-		"application/json":      {"NewJSONEncoder", "NewJSONDecoder"},
-		"application/xml":       {"NewXMLEncoder", "NewXMLDecoder"},
-		"application/gob":       {"NewGobEncoder", "NewGobDecoder"},
-		"application/x-gob":     {"NewGobEncoder", "NewGobDecoder"},
-		"application/binc":      {"NewEncoder", "NewDecoder"},
-		"application/x-binc":    {"NewEncoder", "NewDecoder"},
-		"application/cbor":      {"NewEncoder", "NewDecoder"},
-		"application/x-cbor":    {"NewEncoder", "NewDecoder"},
-		"application/msgpack":   {"NewEncoder", "NewDecoder"},
-		"application/x-msgpack": {"NewEncoder", "NewDecoder"},
+		"application/json":            {"NewJSONEncoder", "NewJSONDecoder"},
+		"application/json-patch+json": {"NewJSONEncoder", "NewJSONDecoder"},
+		"application/xml":             {"NewXMLEncoder", "NewXMLDecoder"},
+		"application/gob":             {"NewGobEncoder", "NewGobDecoder"},
+		"application/x-gob":           {"NewGobEncoder", "NewGobDecoder"},
+		"application/binc":            {"NewEncoder", "NewDecoder"},
+		"application/x-binc":          {"NewEncoder", "NewDecoder"},
+		"application/cbor":            {"NewEncoder", "NewDecoder"},
+		"application/x-cbor":          {"NewEncoder", "NewDecoder"},
+		"application/msgpack":         {"NewEncoder", "NewDecoder"},
+		"application/x-msgpack":       {"NewEncoder", "NewDecoder"},
 	}
 
 	// JSONContentTypes list the Content-Type header values that cause goa to encode or decode
@@ -181,6 +183,55 @@ var (
 		},
 	}
 
+	// JSONPatchContentType is the Content-Type of RFC 6902 JSON Patch documents.
+	JSONPatchContentType = "application/json-patch+json"
+
+	// JSONPatchOperation is the built-in type describing a single RFC 6902 JSON Patch
+	// operation. Actions that accept JSON Patch documents use ArrayOf(JSONPatchOperation)
+	// as payload, the generated payload type is then a slice of JSONPatchOperation
+	// structs that can be applied with goa.ApplyJSONPatch.
+	JSONPatchOperation = &UserTypeDefinition{
+		AttributeDefinition: &AttributeDefinition{
+			Type:        jsonPatchOperationType,
+			Description: "JSON Patch operation as described in RFC 6902",
+			Validation: &dslengine.ValidationDefinition{
+				Required: []string{"op", "path"},
+			},
+			Example: map[string]interface{}{
+				"op":    "replace",
+				"path":  "/name",
+				"value": "Napa Valley",
+			},
+		},
+		TypeName: "JSONPatchOperation",
+	}
+
+	jsonPatchOperationType = Object{
+		"op": &AttributeDefinition{
+			Type:        String,
+			Description: "the operation to perform.",
+			Validation: &dslengine.ValidationDefinition{
+				Values: []interface{}{"add", "remove", "replace", "move", "copy", "test"},
+			},
+			Example: "replace",
+		},
+		"path": &AttributeDefinition{
+			Type:        String,
+			Description: "JSON pointer to the target location.",
+			Example:     "/name",
+		},
+		"from": &AttributeDefinition{
+			Type:        String,
+			Description: "JSON pointer to the source location of move and copy operations.",
+			Example:     "/label",
+		},
+		"value": &AttributeDefinition{
+			Type:        Any,
+			Description: "the value used by add, replace and test operations.",
+			Example:     "Napa Valley",
+		},
+	}
+
 	errorMediaView = &ViewDefinition{
 		AttributeDefinition: &AttributeDefinition{Type: errorMediaType},
 		Name:                "default",
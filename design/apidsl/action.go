@@ -402,6 +402,25 @@ func Params(dsl func()) {
 //		Required("Name")	// definition into the BottlePayload type.
 //	})
 //
+// Actions that accept RFC 6902 JSON Patch documents use an array of the built-in
+// design.JSONPatchOperation type as payload and list design.JSONPatchContentType in the API
+// Consumes DSL. The generated payload is a slice of JSONPatchOperation structs which
+// goa.ApplyJSONPatch applies to the JSON representation of the resource:
+//
+//	Payload(ArrayOf(design.JSONPatchOperation))
+//
+// Validations that are hard to express in the DSL may be written by hand in a Validate() error
+// method of the generated payload type when the design defines no validation for the payload.
+// The generated decoder calls the method once the payload is built and responds with 400 Bad
+// Request if it returns an error:
+//
+//	func (p *CreateBookingPayload) Validate() error {
+//		if !p.End.After(p.Start) {
+//			return errors.New("end must be after start")
+//		}
+//		return nil
+//	}
+//
 func Payload(p interface{}, dsls ...func()) {
 	payload(false, p, dsls...)
 }
@@ -418,6 +437,26 @@ func OptionalPayload(p interface{}, dsls ...func()) {
 	payload(true, p, dsls...)
 }
 
+// MaxBodySize can be used in: Action
+//
+// MaxBodySize sets the maximum length in bytes of the action request body. The generated payload
+// decoder compares the request Content-Length header with the limit and responds with 413
+// Request Entity Too Large before reading the body when it is greater. Requests whose length is
+// not known up front are bounded by the controller MaxRequestBodyLength streaming limit.
+// Example:
+//
+//	Action("create", func() {
+//		Routing(POST(""))
+//		Payload(BottlePayload)
+//		MaxBodySize(1024)
+//	})
+//
+func MaxBodySize(size int64) {
+	if a, ok := actionDefinition(); ok {
+		a.MaxBodySize = size
+	}
+}
+
 func payload(isOptional bool, p interface{}, dsls ...func()) {
 	if len(dsls) > 1 {
 		dslengine.ReportError("too many arguments given to Payload")
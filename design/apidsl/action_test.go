@@ -274,6 +274,56 @@ var _ = Describe("Action", func() {
 	})
 })
 
+var _ = Describe("RequiredOneOf", func() {
+	var names []string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		names = []string{"name", "email"}
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("bar", func() {
+				Routing(GET(""))
+				Params(func() {
+					Param("name")
+					Param("email")
+					RequiredOneOf(names...)
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("sets the params validation", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		params := Design.Resources["foo"].Actions["bar"].Params
+		Ω(params.Validation).ShouldNot(BeNil())
+		Ω(params.Validation.RequiredOneOf).Should(Equal([][]string{names}))
+	})
+
+	Context("with an unknown param", func() {
+		BeforeEach(func() {
+			names = []string{"name", "phone"}
+		})
+
+		It("fails", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a single param", func() {
+		BeforeEach(func() {
+			names = []string{"name"}
+		})
+
+		It("fails", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
 var _ = Describe("Payload", func() {
 	Context("with a payload definition", func() {
 		BeforeEach(func() {
@@ -363,4 +413,35 @@ var _ = Describe("Payload", func() {
 		})
 	})
 
+	Context("with a JSON patch", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			API("test", func() {
+				Consumes(JSONPatchContentType)
+			})
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(PATCH(""))
+					Payload(ArrayOf(JSONPatchOperation))
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("records the built-in JSON patch operation type", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			payload := Design.Resources["foo"].Actions["bar"].Payload
+			Ω(payload).ShouldNot(BeNil())
+			Ω(payload.Type.IsArray()).Should(BeTrue())
+			Ω(payload.Type.ToArray().ElemType.Type).Should(Equal(JSONPatchOperation))
+			Ω(Design.Types).Should(HaveKeyWithValue("JSONPatchOperation", JSONPatchOperation))
+			Ω(Design.Consumes).Should(HaveLen(1))
+			Ω(HasKnownEncoder(Design.Consumes[0].MIMETypes[0])).Should(BeTrue())
+		})
+	})
+
 })
@@ -90,12 +90,32 @@ func API(name string, dsl func()) *design.APIDefinition {
 	return design.Design
 }
 
-// Version can be used in: API
+// Version can be used in: API, Resource
 //
 // Version specifies the API version. One design describes one version.
+//
+// When used in a Resource DSL Version defines a path prefix that is prepended to the paths of
+// all the resource actions and file servers, right after the API base path. This makes it
+// possible to mount multiple versions of the same resource side by side:
+//
+//	Resource("bottle_v1", func() {
+//		Version("v1")		// Actions paths start with /v1/bottles
+//		BasePath("/bottles")
+//	})
+//
+//	Resource("bottle_v2", func() {
+//		Version("v2")		// Actions paths start with /v2/bottles
+//		BasePath("/bottles")
+//	})
+//
 func Version(ver string) {
-	if api, ok := apiDefinition(); ok {
-		api.Version = ver
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.APIDefinition:
+		def.Version = ver
+	case *design.ResourceDefinition:
+		def.Version = ver
+	default:
+		dslengine.IncompatibleDSL()
 	}
 }
 
@@ -267,7 +287,11 @@ func Host(host string) {
 
 // Scheme can be used in: API, Resource, Action
 //
-// Scheme sets the API URL schemes.
+// Scheme sets the API URL schemes. Scheme may list multiple schemes and may be called multiple
+// times, schemes that are already set are ignored. The generated Swagger specification omits the
+// schemes when none is set so that clients use the scheme used to retrieve the specification.
+//
+//	Scheme("http", "https")
 func Scheme(vals ...string) {
 	ok := true
 	for _, v := range vals {
@@ -282,16 +306,33 @@ func Scheme(vals ...string) {
 
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
-		def.Schemes = append(def.Schemes, vals...)
+		def.Schemes = appendSchemes(def.Schemes, vals)
 	case *design.ResourceDefinition:
-		def.Schemes = append(def.Schemes, vals...)
+		def.Schemes = appendSchemes(def.Schemes, vals)
 	case *design.ActionDefinition:
-		def.Schemes = append(def.Schemes, vals...)
+		def.Schemes = appendSchemes(def.Schemes, vals)
 	default:
 		dslengine.IncompatibleDSL()
 	}
 }
 
+// appendSchemes appends the schemes in vals that are not already listed in schemes.
+func appendSchemes(schemes, vals []string) []string {
+	for _, v := range vals {
+		found := false
+		for _, s := range schemes {
+			if s == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			schemes = append(schemes, v)
+		}
+	}
+	return schemes
+}
+
 // Contact can be used in: API
 //
 // Contact sets the API contact information.
@@ -317,9 +358,10 @@ func License(dsl func()) {
 	}
 }
 
-// Docs can be used in: API, Action, Files
+// Docs can be used in: API, Resource, Action, Files
 //
-// Docs provides external documentation pointers.
+// Docs provides external documentation pointers. The Swagger generator uses the resource docs for
+// the tag named after the resource.
 func Docs(dsl func()) {
 	docs := new(design.DocsDefinition)
 	if !dslengine.Execute(dsl, docs) {
@@ -329,6 +371,8 @@ func Docs(dsl func()) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
 		def.Docs = docs
+	case *design.ResourceDefinition:
+		def.Docs = docs
 	case *design.ActionDefinition:
 		def.Docs = docs
 	case *design.FileServerDefinition:
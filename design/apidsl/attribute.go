@@ -311,6 +311,10 @@ func Default(def interface{}) {
 //	})
 //
 // If you do not want an auto-generated example for an attribute, add NoExample() to it.
+//
+// The examples of params and headers are set in the "x-example" extension of the Swagger
+// parameters since Swagger 2.0 does not define an example field for parameters. Params and headers
+// without an Example do not get an auto-generated one.
 func Example(exp interface{}) {
 	if a, ok := attributeDefinition(); ok {
 		if pass := a.SetExample(exp); !pass {
@@ -376,7 +380,9 @@ func Enum(val ...interface{}) {
 // SupportedValidationFormats lists the supported formats for use with the
 // Format DSL.
 var SupportedValidationFormats = []string{
+	"byte",
 	"cidr",
+	"date",
 	"date-time",
 	"email",
 	"hostname",
@@ -397,6 +403,9 @@ var SupportedValidationFormats = []string{
 //
 // "date-time": RFC3339 date time
 //
+// "date": RFC3339 full-date, for example "2006-01-02". The values of params using this format are
+// parsed into time.Time values by the generated code.
+//
 // "email": RFC5322 email address
 //
 // "hostname": RFC1035 internet host name
@@ -412,6 +421,8 @@ var SupportedValidationFormats = []string{
 // "regexp": RE2 regular expression
 //
 // "rfc1123": RFC1123 date time
+//
+// "byte": RFC4648 standard base64 encoded binary data
 func Format(f string) {
 	if a, ok := attributeDefinition(); ok {
 		if a.Type != nil && a.Type.Kind() != design.StringKind {
@@ -459,6 +470,64 @@ func Pattern(p string) {
 	}
 }
 
+// SupportedCharsets lists the supported character classes for use with the Charset DSL.
+var SupportedCharsets = []string{
+	"alpha",
+	"alphanumeric",
+	"ascii",
+	"hex",
+	"numeric",
+	"printable",
+}
+
+// Charset can be used in: Attribute, Header, Param, HashOf, ArrayOf
+//
+// Charset adds a validation that checks that all the characters of the attribute value belong to
+// the given character class. This provides a simpler alternative to Pattern for common cases.
+// The character classes supported by goa are:
+//
+// "alpha": ASCII letters
+//
+// "numeric": ASCII digits
+//
+// "alphanumeric": ASCII letters and digits
+//
+// "hex": hexadecimal digits, lower or upper case
+//
+// "ascii": ASCII characters
+//
+// "printable": printable Unicode characters including the ASCII space
+//
+// Example:
+//
+//        Attribute("handle", String, func() {
+//                Charset("alphanumeric")
+//        })
+func Charset(c string) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.StringKind {
+			incompatibleAttributeType("charset", a.Type.Name(), "a string")
+		} else {
+			supported := false
+			for _, s := range SupportedCharsets {
+				if s == c {
+					supported = true
+					break
+				}
+			}
+			if !supported {
+				dslengine.ReportError("unsupported charset %#v, supported charsets are: %s",
+					c, strings.Join(SupportedCharsets, ", "))
+			} else {
+				if a.Validation == nil {
+					a.Validation = &dslengine.ValidationDefinition{}
+				}
+				a.Validation.Charset = c
+			}
+		}
+	}
+}
+
 // Minimum can be used in: Attribute, Header, Param, HashOf, ArrayOf
 //
 // Minimum adds a "minimum" validation to the attribute.
@@ -525,7 +594,8 @@ func Maximum(val interface{}) {
 
 // MinLength can be used in: Attribute, Header, Param, HashOf, ArrayOf
 //
-// MinLength adds a "minItems" validation to the attribute.
+// MinLength adds a "minItems" validation to array attributes and a "minLength" validation to
+// other attributes.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor45.
 func MinLength(val int) {
 	if a, ok := attributeDefinition(); ok {
@@ -542,7 +612,8 @@ func MinLength(val int) {
 
 // MaxLength can be used in: Attribute, Header, Param, HashOf, ArrayOf
 //
-// MaxLength adds a "maxItems" validation to the attribute.
+// MaxLength adds a "maxItems" validation to array attributes and a "maxLength" validation to
+// other attributes.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor42.
 func MaxLength(val int) {
 	if a, ok := attributeDefinition(); ok {
@@ -557,6 +628,28 @@ func MaxLength(val int) {
 	}
 }
 
+// UniqueBy can be used in: Attribute, Member, Param
+//
+// UniqueBy adds a validation to array attributes whose elements are objects that requires the
+// values of the element attribute with the given name to be unique across the array elements.
+// Elements where the attribute is missing are not taken into account:
+//
+//	Attribute("bottles", ArrayOf(Bottle), func() {
+//		UniqueBy("id")
+//	})
+func UniqueBy(name string) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.ArrayKind {
+			incompatibleAttributeType("unique by", a.Type.Name(), "an array")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.UniqueBy = name
+		}
+	}
+}
+
 // Required can be used in: Attributes, Headers, Payload, Type, Params
 //
 // Required adds a "required" validation to the attribute.
@@ -584,6 +677,147 @@ func Required(names ...string) {
 	}
 }
 
+// RequiredOneOf can be used in: Params
+//
+// RequiredOneOf adds a validation to the action parameters that requires at least one of the
+// given parameters to be present in the request. RequiredOneOf may be called multiple times to
+// define multiple groups:
+//
+//	Params(func() {
+//		Param("name", String)
+//		Param("email", String)
+//		RequiredOneOf("name", "email")
+//	})
+func RequiredOneOf(names ...string) {
+	at, ok := dslengine.CurrentDefinition().(*design.AttributeDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if at.Type != nil && at.Type.Kind() != design.ObjectKind {
+		incompatibleAttributeType("required one of", at.Type.Name(), "an object")
+		return
+	}
+	if len(names) < 2 {
+		dslengine.ReportError("required one of validation must list at least two names")
+		return
+	}
+	if at.Validation == nil {
+		at.Validation = &dslengine.ValidationDefinition{}
+	}
+	at.Validation.RequiredOneOf = append(at.Validation.RequiredOneOf, names)
+}
+
+// ForbiddenWith can be used in: Attribute, Type, MediaType, Payload
+//
+// ForbiddenWith adds a validation to object attributes that forbids setting the attributes with the
+// given other names when the attribute with the given name is set. The generated code only checks
+// the attributes that are set, it does not make the attribute with the given name required. Neither
+// attribute may be required or have a default value. ForbiddenWith may be called multiple times:
+//
+//	Payload(func() {
+//		Member("coupon", String)
+//		Member("discount", Number)
+//		ForbiddenWith("coupon", "discount")
+//	})
+func ForbiddenWith(name string, others ...string) {
+	var at *design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		at = def
+	case *design.MediaTypeDefinition:
+		at = def.AttributeDefinition
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if at.Type != nil && at.Type.Kind() != design.ObjectKind {
+		incompatibleAttributeType("forbidden with", at.Type.Name(), "an object")
+		return
+	}
+	if len(others) == 0 {
+		dslengine.ReportError("forbidden with validation must list at least one other name")
+		return
+	}
+	if at.Validation == nil {
+		at.Validation = &dslengine.ValidationDefinition{}
+	}
+	at.Validation.ForbiddenWith = append(at.Validation.ForbiddenWith, append([]string{name}, others...))
+}
+
+// RequiredWhen can be used in: Attribute, Type, MediaType, Payload
+//
+// RequiredWhen adds a validation to object attributes that makes the attribute with the given name
+// required only when the trigger attribute is set to the given value. The trigger attribute must
+// define an enum validation that lists the value. The attribute is not required when the trigger
+// attribute is not set. RequiredWhen may be called multiple times:
+//
+//	Payload(func() {
+//		Member("deliveryMethod", String, func() {
+//			Enum("ship", "pickup")
+//		})
+//		Member("shippingAddress", String)
+//		RequiredWhen("shippingAddress", "deliveryMethod", "ship")
+//	})
+func RequiredWhen(name, trigger string, value interface{}) {
+	var at *design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		at = def
+	case *design.MediaTypeDefinition:
+		at = def.AttributeDefinition
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if at.Type != nil && at.Type.Kind() != design.ObjectKind {
+		incompatibleAttributeType("required when", at.Type.Name(), "an object")
+		return
+	}
+	if at.Validation == nil {
+		at.Validation = &dslengine.ValidationDefinition{}
+	}
+	at.Validation.RequiredWhen = append(at.Validation.RequiredWhen,
+		&dslengine.RequiredWhenDefinition{Name: name, Trigger: trigger, Value: value})
+}
+
+// EqualLength can be used in: Attribute, Type, MediaType, Payload
+//
+// EqualLength adds a validation to object attributes that requires the array attributes with the
+// given names to have the same number of elements, for example to pair the elements of two arrays.
+// The generated code skips the check when one of the attributes is not set unless both are
+// required. EqualLength may be called multiple times:
+//
+//	Payload(func() {
+//		Member("labels", ArrayOf(String))
+//		Member("values", ArrayOf(Number))
+//		EqualLength("labels", "values")
+//	})
+func EqualLength(names ...string) {
+	var at *design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		at = def
+	case *design.MediaTypeDefinition:
+		at = def.AttributeDefinition
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	if at.Type != nil && at.Type.Kind() != design.ObjectKind {
+		incompatibleAttributeType("equal length", at.Type.Name(), "an object")
+		return
+	}
+	if len(names) < 2 {
+		dslengine.ReportError("equal length validation must list at least two names")
+		return
+	}
+	if at.Validation == nil {
+		at.Validation = &dslengine.ValidationDefinition{}
+	}
+	at.Validation.EqualLength = append(at.Validation.EqualLength, names)
+}
+
 // incompatibleAttributeType reports an error for validations defined on
 // incompatible attributes (e.g. max value on string).
 func incompatibleAttributeType(validation, actual, expected string) {
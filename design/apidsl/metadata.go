@@ -35,6 +35,315 @@ import (
 //        Metadata("struct:tag:json", "myName,omitempty")
 //        Metadata("struct:tag:xml", "myName,attr")
 //
+// Setting the `struct:tag:json` value to "-" also omits the attribute from the generated JSON
+// schema and Swagger specification as the field is never serialized.
+//
+//        Metadata("struct:tag:json", "-")
+//
+// `struct:constructor`: generates a NewValidated<Type> function that creates an instance of the
+// public Go struct and runs the validations defined in the design, returning an error if they
+// fail. The required attributes with no default value become the function arguments in
+// alphabetical order, the primitive attributes with a default value are initialized with it.
+// Applicable to types, media types and payloads.
+//
+//        Type("Bottle", func() {
+//                Metadata("struct:constructor")
+//                Attribute("name", String, func() {
+//                        MinLength(2)
+//                })
+//                Required("name")
+//        })
+//
+// `transform:trim`: removes the leading and trailing white space of the raw value of a parameter
+// or header prior to coercing it to the attribute type and running the validations, so that for
+// example MaxLength applies to the trimmed value. Each value of array parameters and headers is
+// trimmed. Also removes the white space of string fields of request payloads after decoding and
+// prior to validating. Applicable to action parameters, headers and string attributes.
+//
+//        Metadata("transform:trim")
+//
+// `transform:lower` and `transform:upper`: convert the value of a string parameter, header or
+// payload field to lower or upper case after decoding and prior to running the validations. The
+// value is trimmed first if "transform:trim" is also set. Applicable to string attributes.
+//
+//        Attribute("email", String, func() {
+//                Format("email")
+//                Metadata("transform:lower")
+//        })
+//
+// `validation:allow-empty`: skips the format and pattern validations of an optional string
+// attribute when its value is the empty string. The validations still run on non-empty values.
+// Applicable to string attributes, parameters and headers.
+//
+//        Param("email", String, func() {
+//                Format("email")
+//                Metadata("validation:allow-empty")
+//        })
+//
+// `validation:aggregate`: returns the errors of the request body and of the params in a single
+// response. By default the generated controller returns the error of the request body as soon as
+// decoding or validating it fails, before the params are loaded into the action context. The
+// errors of the params are always merged together. Request body errors other than bad requests,
+// such as a body exceeding the maximum size, are returned as is. Applicable to actions.
+//
+//        Metadata("validation:aggregate")
+//
+// `idempotency:key`: reads the value of the "Idempotency-Key" request header and stores it in the
+// request context where goa.ContextIdempotencyKey retrieves it. The value "required" causes the
+// generated code to reject requests that do not set the header with a 400 response. Applicable to
+// actions.
+//
+//        Metadata("idempotency:key", "required")
+//
+// `json:single-value`: accepts a single value in place of an array when decoding the JSON request
+// body. The value is decoded into a slice with one element so that for example "tags": "x" and
+// "tags": ["x"] are equivalent. This is set per field rather than for the whole API so that the
+// other array fields keep rejecting single values. Applicable to array attributes of payloads and
+// user types.
+//
+//        Attribute("tags", ArrayOf(String), func() {
+//                Metadata("json:single-value")
+//        })
+//
+// `validation:func`: validates the value of the param at runtime with the validator registered in
+// the service under the given name. This makes it possible to check values against allowlists
+// that are only known at runtime, for example when they are read from external configuration.
+// The generated context constructors call service.ValidateParam once the param value has been
+// decoded and passed the design validations. The validators must be registered before the
+// controllers are mounted:
+//
+//        service := goa.New("cellar")
+//        service.ParamValidators = map[string]goa.ParamValidator{
+//                "region": func(v interface{}) error { return checkRegion(v.(string)) },
+//        }
+//
+// An error returned by the validator results in a bad request response, an unregistered validator
+// results in an internal error. Applicable to action params.
+//
+//        Param("region", String, func() {
+//                Metadata("validation:func", "region")
+//        })
+//
+// `http:status`: identifies the integer media type attribute that holds the response status code.
+// The generated action contexts include a Respond method that sends the response whose status code
+// matches the value of the attribute. Respond uses the response with the lowest status code if the
+// value does not match any of the action responses.
+// Applicable to media type attributes.
+//
+//        Attribute("status", Integer, func() {
+//                Metadata("http:status")
+//        })
+//
+// `http:link`: identifies the string media type attribute that holds the URL of a pagination link
+// with the given relation type, for example "next", "prev", "first" or "last". The generated
+// response helpers add a `Link: <url>; rel="next"` header (RFC 8288) for each such attribute that
+// is set in the response media type before writing the response.
+// Applicable to media type attributes.
+//
+//        Attribute("next", String, func() {
+//                Metadata("http:link", "next")
+//        })
+//
+// `error:code`: sets the code of the errors sent with a response using the ErrorMedia media type
+// when the error given to the generated response helper is not a goa error. Defaults to the snake
+// case response name, for example "bad_request" for the BadRequest response.
+// Applicable to responses.
+//
+//        Response(Conflict, ErrorMedia, func() {
+//                Metadata("error:code", "already_exists")
+//        })
+//
+// `view:param`: generates an additional response helper for responses whose media type defines
+// multiple views. The helper accepts one argument per view and sends the response using the view
+// selected by the query string parameter with the given name ("view" by default) or by the "view"
+// parameter of the Accept header media type, e.g. "application/json; view=tiny". The default view
+// is used when the request does not select a view.
+// Applicable to responses that do not set a view.
+//
+//        Response(OK, func() {
+//                Media(BottleMedia)
+//                Metadata("view:param", "view")
+//        })
+//
+// `param:query`: sets the name of the query string parameter the value of the param is read from.
+// This makes it possible to define a query string parameter with the same name as a path
+// parameter.
+// Applicable to action params.
+//
+//        Routing(GET("/:id"))
+//        Params(func() {
+//                Param("id", Integer)
+//                Param("idFilter", String, func() {
+//                        Metadata("param:query", "id")
+//                })
+//        })
+//
+// `param:alias`: lists alternate names of the query string parameter the value of the param is read
+// from when the request does not define it. The aliases are tried in order and the first one with
+// a non-empty value is used. Required params are considered present if one of their aliases is.
+// Applicable to action params.
+//
+//        Params(func() {
+//                Param("query", String, func() {
+//                        Metadata("param:alias", "q", "search")
+//                })
+//        })
+//
+// `param:sources`: lists the sources the value of the param is read from, in order. The generated
+// code tries each source until one defines the param, and required params are considered present
+// if any source defines them. The sources are "header", "query" and "path". The header and query
+// sources use the name of the param unless the source is followed by a colon and another name, for
+// example "header:X-Tenant-Id". The path source requires a path parameter with the same name. The
+// Swagger specification describes one parameter per source.
+// Applicable to action params.
+//
+//        Params(func() {
+//                Param("tenant", String, func() {
+//                        Metadata("param:sources", "header:X-Tenant-Id", "query")
+//                })
+//        })
+//
+// `param:matrix`: reads the value of the param from the matrix parameters of the path parameter
+// with the given name, for example "color" in "/items/shoes;color=red". The generated code removes
+// the matrix parameters from the value of the path parameter. The route path (as returned by
+// FullPath) does not include the matrix parameters. The value of the query string parameter with
+// the same name is used when the matrix parameter is absent.
+// Applicable to action params.
+//
+//        Routing(GET("/items/:item"))
+//        Params(func() {
+//                Param("item", String)
+//                Param("color", String, func() {
+//                        Metadata("param:matrix", "item")
+//                })
+//        })
+//
+// `param:json`: accepts the value of an array param written as a JSON array, for example
+// "?ids=[1,2,3]", in addition to comma separated values such as "?ids=1,2,3". The elements are
+// coerced to the array element type in both cases. A value starting with "[" that is not a valid
+// JSON array produces an invalid param type error.
+// Applicable to array action params.
+//
+//        Params(func() {
+//                Param("ids", ArrayOf(Integer), func() {
+//                        Metadata("param:json")
+//                })
+//        })
+//
+// `param:bignum`: parses the value of a string param into a *big.Int, or a *big.Rat if the
+// metadata value is "rat". Applying the metadata to an array of strings param produces a slice of
+// *big.Int or *big.Rat. Values that cannot be parsed produce an invalid param type error. The
+// Swagger specification describes such params as strings with the "big-integer" or
+// "big-rational" format. Parameters using the metadata may not define a default value and their
+// validations are not enforced by the generated code.
+// Applicable to action params.
+//
+//        Params(func() {
+//                Param("amount", String, func() {
+//                        Metadata("param:bignum")
+//                })
+//                Param("ratio", String, func() {
+//                        Metadata("param:bignum", "rat")
+//                })
+//        })
+//
+// `param:enum-map`: maps the values of the param sent on the wire to the internal values loaded
+// into the action context. Each metadata value is of the form "wire=internal". The generated code
+// replaces a wire value with the corresponding internal value before coercing and validating it,
+// other values are coerced as is so that the internal values are accepted too. The Swagger
+// specification describes the param as a string whose values are the wire values followed by the
+// internal values.
+// Applicable to action params of primitive types.
+//
+//        Params(func() {
+//                Param("status", Integer, func() {
+//                        Enum(0, 1)
+//                        Metadata("param:enum-map", "inactive=0", "active=1")
+//                })
+//        })
+//
+// `param:basic-auth`: reads the value of the param from the username or the password of the request
+// HTTP Basic auth credentials rather than from the query string. The metadata value is either
+// "username" or "password". Requests that are missing the credentials of a required param are
+// rejected with an unauthorized error. The generated client sets the credentials from the values of
+// the params and the Swagger specification describes them as a basic security requirement.
+// Applicable to action params of type string other than path params.
+//
+//        Params(func() {
+//                Param("user", String, func() {
+//                        Metadata("param:basic-auth", "username")
+//                })
+//                Param("pass", String, func() {
+//                        Metadata("param:basic-auth", "password")
+//                })
+//                Required("user", "pass")
+//        })
+//
+// `param:flag`: makes a boolean query string param act as a presence flag. The param is true when
+// it appears in the query string with an empty value, for example "?verbose" or "?verbose=", and
+// the Swagger specification sets allowEmptyValue on the param. Non-empty values are coerced as
+// usual, use a default value to get false when the param is absent.
+// Applicable to boolean action params other than path params.
+//
+//        Params(func() {
+//                Param("verbose", Boolean, func() {
+//                        Default(false)
+//                        Metadata("param:flag")
+//                })
+//        })
+//
+// `param:bool-values`: sets the strings that stand for true and false in the values of a boolean
+// param or header in place of the values accepted by strconv.ParseBool. The first metadata value
+// is the true string and the second the false string. Other values are rejected with an invalid
+// param type error. The Swagger specification describes the param as a string whose values are
+// the two strings.
+// Applicable to boolean action params and headers.
+//
+//        Params(func() {
+//                Param("notifications", Boolean, func() {
+//                        Metadata("param:bool-values", "on", "off")
+//                })
+//        })
+//
+// `payload:discriminator` and `payload:variants`: decode the request body into one of several user
+// types selected by the value of a string attribute of the payload. The discriminator metadata
+// names the attribute, which must be required. Each variants metadata value is of the form
+// "value=TypeName" where TypeName is the name of a user type that also defines the attribute. The
+// generated code decodes and validates the payload, then decodes and validates the request body
+// again using the type matching the value of the attribute and stores the result in the Variant
+// field of the payload. Requests with any other value are rejected with an invalid enum value
+// error.
+// Applicable to payloads defined inline with Payload.
+//
+//        Payload(func() {
+//                Metadata("payload:discriminator", "kind")
+//                Metadata("payload:variants", "cat=Cat", "dog=Dog")
+//                Member("kind", String)
+//                Required("kind")
+//        })
+//
+// `payload:presence`: records the names of the fields present in the request body so that fields
+// explicitly set to null can be told apart from absent fields, for example to implement PATCH
+// semantics. The generated payload type exposes an IsSet method that returns true if the field
+// with the given name was present in the request body. The request body is decoded a second time
+// into a map to find the fields, so the API must only consume JSON.
+// Applicable to object payloads defined inline with Payload.
+//
+//        Payload(func() {
+//                Metadata("payload:presence")
+//                Member("name", String)
+//        })
+//
+// `payload:raw`: stores the exact bytes of the request body in the RawBody field of the generated
+// payload type in addition to decoding them. This makes it possible to verify signatures computed
+// over the raw request body, for example in webhook receivers.
+// Applicable to payloads defined inline with Payload.
+//
+//        Payload(func() {
+//                Metadata("payload:raw")
+//                Member("event", String)
+//        })
+//
 // `swagger:generate`: specifies whether Swagger specification should be generated. Defaults to
 // true.
 // Applicable to resources, actions and file servers.
@@ -46,6 +355,23 @@ import (
 //
 //        Metadata("swagger:summary", "Short summary of what action does")
 //
+// `swagger:deprecated`: marks the action as deprecated in the Swagger specification. The generated
+// controller code also sets the `Warning: 299 - "Deprecated"` header on the action responses.
+// Applicable to actions.
+//
+//        Metadata("swagger:deprecated")
+//
+// `swagger:read-only`: marks the attribute as read-only in the Swagger specification and JSON
+// schemas. Read-only attributes are sent in responses but not in requests, so the schema of
+// request bodies whose type requires read-only attributes is generated as a separate definition
+// named after the type with the "Request" suffix that does not require them. The validation of
+// request bodies generated by goagen does not require them either.
+// Applicable to attributes of user types and media types.
+//
+//        Attribute("id", Integer, func() {
+//                Metadata("swagger:read-only")
+//        })
+//
 // `swagger:tag:xxx`: sets the Swagger object field tag xxx.
 // Applicable to resources and actions.
 //
@@ -340,3 +340,62 @@ var _ = Describe("Resource", func() {
 		})
 	})
 })
+
+var _ = Describe("Nested resources", func() {
+	var member *ActionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		Resource("org", func() {
+			BasePath("/orgs")
+			Action("show", func() {
+				Routing(GET("/:orgID"))
+				Params(func() {
+					Param("orgID", Integer)
+				})
+			})
+		})
+		Resource("team", func() {
+			Parent("org")
+			BasePath("/teams")
+			Action("show", func() {
+				Routing(GET("/:teamID"))
+				Params(func() {
+					Param("teamID", Integer)
+				})
+			})
+		})
+		Resource("member", func() {
+			Parent("team")
+			BasePath("/members")
+			Action("show", func() {
+				Routing(GET("/:memberID"))
+				Params(func() {
+					Param("memberID", Integer)
+				})
+			})
+		})
+		dslengine.Run()
+		member = Design.Resources["member"].Actions["show"]
+	})
+
+	It("computes the full path from all the ancestors", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(member.Routes[0].FullPath()).Should(Equal("/orgs/:orgID/teams/:teamID/members/:memberID"))
+	})
+
+	It("lists the path params in the order they appear in the path", func() {
+		Ω(member.Routes[0].Params()).Should(Equal([]string{"orgID", "teamID", "memberID"}))
+	})
+
+	It("inherits the path params of all the ancestors with their types", func() {
+		for _, params := range []*AttributeDefinition{member.Params, member.AllParams(), member.PathParams()} {
+			obj := params.Type.ToObject()
+			Ω(obj).Should(HaveLen(3))
+			for _, n := range []string{"orgID", "teamID", "memberID"} {
+				Ω(obj).Should(HaveKey(n))
+				Ω(obj[n].Type).Should(Equal(Integer))
+			}
+		}
+	})
+})
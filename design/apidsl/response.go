@@ -122,6 +122,21 @@ func Status(status int) {
 	}
 }
 
+// CacheControl can be used in: Response, ResponseTemplate
+//
+// CacheControl sets the value of the Cache-Control header written by the generated response
+// helper. The value is written as is and may list multiple directives:
+//
+//	Response(OK, func() {
+//		Media(BottleMedia)
+//		CacheControl("public, max-age=60")
+//	})
+func CacheControl(directives string) {
+	if r, ok := responseDefinition(); ok {
+		r.CacheControl = directives
+	}
+}
+
 func executeResponseDSL(name string, paramsAndDSL ...interface{}) *design.ResponseDefinition {
 	var params []string
 	var dsl func()
@@ -177,6 +177,24 @@ var _ = Describe("Response", func() {
 		})
 	})
 
+	Context("with a cache policy", func() {
+		const cacheControl = "public, max-age=60"
+
+		BeforeEach(func() {
+			name = "OK"
+			dsl = func() {
+				CacheControl(cacheControl)
+			}
+		})
+
+		It("sets the response Cache-Control header value", func() {
+			Ω(res).ShouldNot(BeNil())
+			Ω(res.Validate()).ShouldNot(HaveOccurred())
+			Ω(res.Status).Should(Equal(200))
+			Ω(res.CacheControl).Should(Equal(cacheControl))
+		})
+	})
+
 	Context("not from the goa default definitions", func() {
 		BeforeEach(func() {
 			name = "foo"
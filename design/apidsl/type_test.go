@@ -65,6 +65,33 @@ var _ = Describe("Type", func() {
 		})
 	})
 
+	Context("with an attribute using the date format", func() {
+		const attName = "att"
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() {
+				Attribute(attName, String, func() {
+					Format("date")
+				})
+			}
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(POST("/"))
+					Payload(name)
+				})
+			})
+		})
+
+		It("generates date examples", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(ut).ShouldNot(BeNil())
+			payload := Design.Resources["res"].Actions["act"].Payload
+			Ω(payload.Example).Should(HaveKey(attName))
+			example := payload.Example.(map[string]interface{})[attName]
+			Ω(example).Should(MatchRegexp(`^\d{4}-\d{2}-\d{2}$`))
+		})
+	})
+
 	Context("with a name and uuid datatype", func() {
 		const attName = "att"
 		BeforeEach(func() {
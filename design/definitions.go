@@ -114,12 +114,16 @@ type (
 		Schemes []string
 		// Common URL prefix to all resource action HTTP requests
 		BasePath string
+		// Version is the path prefix inserted between the API and resource base paths
+		Version string
 		// Path and query string parameters that apply to all actions.
 		Params *AttributeDefinition
 		// Name of parent resource if any
 		ParentName string
 		// Optional description
 		Description string
+		// Docs points to the resource external documentation
+		Docs *DocsDefinition
 		// Default media type, describes the resource attributes
 		MediaType string
 		// Default view name if default media type is MediaTypeDefinition
@@ -197,6 +201,8 @@ type (
 		ViewName string
 		// Response header definitions
 		Headers *AttributeDefinition
+		// CacheControl is the value of the Cache-Control header set on the response if any
+		CacheControl string
 		// Parent action or resource
 		Parent dslengine.Definition
 		// Metadata is a list of key/value pairs
@@ -244,6 +250,9 @@ type (
 		Payload *UserTypeDefinition
 		// PayloadOptional is true if the request payload is optional, false otherwise.
 		PayloadOptional bool
+		// MaxBodySize is the maximum length in bytes of the request body, requests whose
+		// Content-Length exceeds it are rejected before the body is read. 0 means no limit.
+		MaxBodySize int64
 		// Request headers that need to be made available to action
 		Headers *AttributeDefinition
 		// Metadata is a list of key/value pairs
@@ -634,7 +643,7 @@ func (a *APIDefinition) DSL() func() {
 }
 
 // Finalize sets the Consumes and Produces fields to the defaults if empty.
-// Also it records built-in media types that are used by the user design.
+// Also it records built-in media types and types that are used by the user design.
 func (a *APIDefinition) Finalize() {
 	if len(a.Consumes) == 0 {
 		a.Consumes = DefaultDecoders
@@ -664,6 +673,20 @@ func (a *APIDefinition) Finalize() {
 			return nil
 		})
 	})
+	a.IterateResources(func(r *ResourceDefinition) error {
+		return r.IterateActions(func(action *ActionDefinition) error {
+			if action.Payload == nil || !action.Payload.IsArray() {
+				return nil
+			}
+			if elem := action.Payload.ToArray().ElemType; elem != nil && elem.Type == JSONPatchOperation {
+				if a.Types == nil {
+					a.Types = make(map[string]*UserTypeDefinition)
+				}
+				a.Types[JSONPatchOperation.TypeName] = JSONPatchOperation
+			}
+			return nil
+		})
+	})
 }
 
 // NewResourceDefinition creates a resource definition but does not
@@ -776,7 +799,7 @@ func (r *ResourceDefinition) FullPath() string {
 			}
 		}
 	} else {
-		basePath = Design.BasePath
+		basePath = path.Join(Design.BasePath, r.Version)
 	}
 	return httppath.Clean(path.Join(basePath, r.BasePath))
 }
@@ -1313,11 +1336,12 @@ func (r *ResponseDefinition) Finalize() {
 // Dup returns a copy of the response definition.
 func (r *ResponseDefinition) Dup() *ResponseDefinition {
 	res := ResponseDefinition{
-		Name:        r.Name,
-		Status:      r.Status,
-		Description: r.Description,
-		MediaType:   r.MediaType,
-		ViewName:    r.ViewName,
+		Name:         r.Name,
+		Status:       r.Status,
+		Description:  r.Description,
+		MediaType:    r.MediaType,
+		ViewName:     r.ViewName,
+		CacheControl: r.CacheControl,
 	}
 	if r.Headers != nil {
 		res.Headers = DupAtt(r.Headers)
@@ -1343,6 +1367,9 @@ func (r *ResponseDefinition) Merge(other *ResponseDefinition) {
 		r.MediaType = other.MediaType
 		r.ViewName = other.ViewName
 	}
+	if r.CacheControl == "" {
+		r.CacheControl = other.CacheControl
+	}
 	if other.Headers != nil {
 		otherHeaders := other.Headers.Type.ToObject()
 		if len(otherHeaders) > 0 {
@@ -1479,6 +1506,12 @@ func (a *ActionDefinition) WebSocket() bool {
 	return true
 }
 
+// IsDeprecated returns true if the action is marked with the swagger:deprecated metadata.
+func (a *ActionDefinition) IsDeprecated() bool {
+	_, ok := a.Metadata["swagger:deprecated"]
+	return ok
+}
+
 // Finalize inherits security scheme and action responses from parent and top level design.
 func (a *ActionDefinition) Finalize() {
 	// Inherit security scheme
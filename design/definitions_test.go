@@ -165,6 +165,32 @@ var _ = Describe("FullPath", func() {
 			})
 		})
 	})
+
+	Context("Given a versioned resource with an action with a route", func() {
+		var route *design.RouteDefinition
+
+		BeforeEach(func() {
+			design.Design.BasePath = "/api"
+			action := &design.ActionDefinition{}
+			route = &design.RouteDefinition{Path: "/:id", Parent: action}
+			action.Routes = []*design.RouteDefinition{route}
+			action.Parent = &design.ResourceDefinition{
+				Name:     "bottle_v2",
+				Version:  "v2",
+				BasePath: "/bottles",
+				Actions:  map[string]*design.ActionDefinition{"show": action},
+			}
+		})
+
+		AfterEach(func() {
+			design.Design.BasePath = ""
+		})
+
+		It("FullPath inserts the version after the API base path", func() {
+			Ω(route.FullPath()).Should(Equal("/api/v2/bottles/:id"))
+			Ω(route.Params()).Should(Equal([]string{"id"}))
+		})
+	})
 })
 
 var _ = Describe("AllParams", func() {
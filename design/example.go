@@ -1,6 +1,7 @@
 package design
 
 import (
+	"encoding/base64"
 	"fmt"
 	"math"
 	"regexp"
@@ -153,6 +154,7 @@ func (eg *exampleGenerator) generateFormatExample() interface{} {
 		"email":     eg.r.faker.Email(),
 		"hostname":  eg.r.faker.DomainName() + "." + eg.r.faker.DomainSuffix(),
 		"date-time": time.Unix(int64(eg.r.Int())%1454957045, 0).Format(time.RFC3339), // to obtain a "fixed" rand
+		"date":      time.Unix(int64(eg.r.Int())%1454957045, 0).Format("2006-01-02"),
 		"ipv4":      eg.r.faker.IPv4Address().String(),
 		"ipv6":      eg.r.faker.IPv6Address().String(),
 		"ip":        eg.r.faker.IPv4Address().String(),
@@ -167,6 +169,7 @@ func (eg *exampleGenerator) generateFormatExample() interface{} {
 		"cidr":    "192.168.100.14/24",
 		"regexp":  eg.r.faker.Characters(3) + ".*",
 		"rfc1123": time.Unix(int64(eg.r.Int())%1454957045, 0).Format(time.RFC1123), // to obtain a "fixed" rand
+		"byte":    base64.StdEncoding.EncodeToString([]byte(eg.r.faker.Characters(6))),
 	}[format]; ok {
 		return res
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/build"
 	"mime"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -81,6 +82,7 @@ func (a *APIDefinition) Validate() error {
 		verr.Merge(a.Params.Validate("base parameters", a))
 	}
 
+	a.validateHost(verr)
 	a.validateContact(verr)
 	a.validateLicense(verr)
 	a.validateDocs(verr)
@@ -169,6 +171,42 @@ func (a *APIDefinition) Validate() error {
 	return err
 }
 
+// validateHost checks that the API host consists of a hostname and an optional port only and that
+// the well-known HTTP and HTTPS ports are consistent with the API schemes.
+func (a *APIDefinition) validateHost(verr *dslengine.ValidationErrors) {
+	if a.Host == "" {
+		return
+	}
+	u, err := url.Parse("//" + a.Host)
+	if err != nil || u.Host != a.Host || u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		verr.Add(a, `invalid host "%s", host must consist of a hostname and an optional port`, a.Host)
+		return
+	}
+	if len(a.Schemes) == 0 {
+		return
+	}
+	_, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		return
+	}
+	var expected []string
+	switch port {
+	case "80":
+		expected = []string{"http", "ws"}
+	case "443":
+		expected = []string{"https", "wss"}
+	default:
+		return
+	}
+	for _, s := range a.Schemes {
+		if s == expected[0] || s == expected[1] {
+			return
+		}
+	}
+	verr.Add(a, `host "%s" uses port %s which does not match any of the API schemes %s`,
+		a.Host, port, strings.Join(a.Schemes, ", "))
+}
+
 func (a *APIDefinition) validateContact(verr *dslengine.ValidationErrors) {
 	if a.Contact != nil && a.Contact.URL != "" {
 		if _, err := url.ParseRequestURI(a.Contact.URL); err != nil {
@@ -210,6 +248,14 @@ func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
 	if r.ParentName != "" {
 		r.validateParent(verr)
 	}
+	if r.Version != "" {
+		if r.ParentName != "" {
+			verr.Add(r, "Version cannot be used on child resources, their paths are prefixed with the parent resource path")
+		}
+		if strings.ContainsAny(r.Version, "/:*") {
+			verr.Add(r, "invalid version %#v, version must be a single path segment with no wildcard", r.Version)
+		}
+	}
 	for _, resp := range r.Responses {
 		verr.Merge(resp.Validate())
 	}
@@ -320,17 +366,35 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	if len(a.Routes) == 0 {
 		verr.Add(a, "No route defined for action")
 	}
-	for i, r := range a.Responses {
-		for j, r2 := range a.Responses {
-			if i != j && r.Status == r2.Status {
-				verr.Add(r, "Multiple response definitions with status code %d", r.Status)
-			}
+	statuses := make(map[int][]string)
+	var codes []int
+	for n, r := range a.Responses {
+		if _, ok := statuses[r.Status]; !ok {
+			codes = append(codes, r.Status)
 		}
+		statuses[r.Status] = append(statuses[r.Status], n)
 		verr.Merge(r.Validate())
 	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		if names := statuses[code]; len(names) > 1 {
+			sort.Strings(names)
+			verr.Add(a, "Multiple response definitions with status code %d: %s", code, strings.Join(names, ", "))
+		}
+	}
 	verr.Merge(a.ValidateParams())
 	if a.Payload != nil {
 		verr.Merge(a.Payload.Validate("action payload", a))
+		verr.Merge(a.validateDiscriminator())
+		verr.Merge(a.validatePresence())
+	}
+	if v, ok := a.Metadata["idempotency:key"]; ok && len(v) > 0 && v[0] != "required" {
+		verr.Add(a, `"idempotency:key" metadata value must be "required" or empty, got %q`, v[0])
+	}
+	if a.MaxBodySize < 0 {
+		verr.Add(a, "MaxBodySize must be positive, got %d", a.MaxBodySize)
+	} else if a.MaxBodySize > 0 && a.Payload == nil {
+		verr.Add(a, "MaxBodySize requires the action to define a payload")
 	}
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
@@ -377,6 +441,96 @@ func (f *FileServerDefinition) Validate() *dslengine.ValidationErrors {
 	return verr.AsError()
 }
 
+// validateDiscriminator checks the payload:discriminator and payload:variants metadata of the
+// action payload.
+func (a *ActionDefinition) validateDiscriminator() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	disc, ok := a.Payload.Metadata["payload:discriminator"]
+	if !ok {
+		if _, ok := a.Payload.Metadata["payload:variants"]; ok {
+			verr.Add(a, `payload defines the "payload:variants" metadata but not "payload:discriminator"`)
+		}
+		return verr
+	}
+	if len(disc) != 1 {
+		verr.Add(a, `"payload:discriminator" metadata must define exactly one attribute name`)
+		return verr
+	}
+	name := disc[0]
+	obj := a.Payload.ToObject()
+	if obj == nil || obj[name] == nil {
+		verr.Add(a, `discriminator "%s" is not an attribute of the payload`, name)
+		return verr
+	}
+	if obj[name].Type.Kind() != StringKind {
+		verr.Add(a, `discriminator "%s" must be a string`, name)
+	}
+	if !a.Payload.IsRequired(name) {
+		verr.Add(a, `discriminator "%s" must be required`, name)
+	}
+	variants := a.Payload.Metadata["payload:variants"]
+	if len(variants) == 0 {
+		verr.Add(a, `payload defines the "payload:discriminator" metadata but no "payload:variants"`)
+	}
+	for _, v := range variants {
+		elems := strings.SplitN(v, "=", 2)
+		if len(elems) != 2 {
+			verr.Add(a, `invalid "payload:variants" value %#v, must be of the form "value=TypeName"`, v)
+			continue
+		}
+		t, ok := Design.Types[elems[1]]
+		if !ok {
+			verr.Add(a, `payload variant "%s" refers to type "%s" which does not exist`, elems[0], elems[1])
+			continue
+		}
+		if o := t.ToObject(); o == nil || o[name] == nil {
+			verr.Add(a, `payload variant type "%s" does not define the discriminator "%s"`, elems[1], name)
+		}
+	}
+	return verr
+}
+
+// validatePresence checks that the payload:presence metadata is only used on object payloads
+// defined inline by APIs that only consume JSON. The generated code decodes the request body a
+// second time into a map to record the names of the fields it defines, which only works with JSON.
+func (a *ActionDefinition) validatePresence() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if _, ok := a.Payload.Metadata["payload:presence"]; !ok {
+		return verr
+	}
+	if !a.Payload.IsObject() {
+		verr.Add(a, `the "payload:presence" metadata only applies to object payloads`)
+	}
+	for _, t := range Design.Types {
+		if t.TypeName == a.Payload.TypeName {
+			verr.Add(a, `the "payload:presence" metadata only applies to payloads defined inline, %s is a user type`, t.TypeName)
+			break
+		}
+	}
+	decoders := Design.Consumes
+	if len(decoders) == 0 {
+		decoders = DefaultDecoders
+	}
+	for _, dec := range decoders {
+		for _, m := range dec.MIMETypes {
+			if !isJSONMIMEType(m) {
+				verr.Add(a, `the "payload:presence" metadata requires the API to only consume JSON, it consumes %s`, m)
+			}
+		}
+	}
+	return verr
+}
+
+// isJSONMIMEType returns true if the given MIME type is "application/json" or uses the "+json"
+// suffix.
+func isJSONMIMEType(m string) bool {
+	base, _, err := mime.ParseMediaType(m)
+	if err != nil {
+		base = m
+	}
+	return base == "application/json" || strings.HasSuffix(base, "+json")
+}
+
 // ValidateParams checks the action parameters (make sure they have names, members and types).
 func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -416,9 +570,113 @@ func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 		} else if p.Type.Kind() == HashKind {
 			verr.Add(a, `parameter %s cannot be a hash, only action payloads may be of type hash`, n)
 		}
+		if seg, ok := p.Metadata["param:matrix"]; ok && len(seg) > 0 {
+			found := false
+			for _, wc := range wcs {
+				if wc == seg[0] {
+					found = true
+					break
+				}
+			}
+			if !found {
+				verr.Add(a, `matrix parameter %s refers to path parameter "%s" which does not exist`, n, seg[0])
+			}
+		}
+		if _, ok := p.Metadata["param:bignum"]; ok {
+			elem := p
+			if p.Type.IsArray() {
+				elem = p.Type.ToArray().ElemType
+			}
+			if elem.Type.Kind() != StringKind {
+				verr.Add(a, `parameter %s defines the "param:bignum" metadata but is not a string or an array of strings`, n)
+			}
+			if p.DefaultValue != nil {
+				verr.Add(a, `parameter %s defines the "param:bignum" metadata and cannot have a default value`, n)
+			}
+		}
+		if v, ok := p.Metadata["param:basic-auth"]; ok {
+			if len(v) != 1 || (v[0] != "username" && v[0] != "password") {
+				verr.Add(a, `"param:basic-auth" metadata of parameter %s must be "username" or "password"`, n)
+			}
+			if p.Type.Kind() != StringKind {
+				verr.Add(a, `parameter %s defines the "param:basic-auth" metadata but is not a string`, n)
+			}
+			for _, wc := range wcs {
+				if wc == n {
+					verr.Add(a, `path parameter %s cannot define the "param:basic-auth" metadata`, n)
+				}
+			}
+		}
+		for _, src := range p.Metadata["param:sources"] {
+			kind := src
+			if i := strings.Index(src, ":"); i > 0 {
+				kind = src[:i]
+			}
+			switch kind {
+			case "header", "query":
+			case "path":
+				found := false
+				for _, wc := range wcs {
+					if wc == n {
+						found = true
+						break
+					}
+				}
+				if !found || src != "path" {
+					verr.Add(a, `"path" source of parameter %s requires a path parameter with the same name`, n)
+				}
+			default:
+				verr.Add(a, `invalid "param:sources" value %#v of parameter %s, must be "header", "query" or "path"`, src, n)
+			}
+		}
+		if _, ok := p.Metadata["param:flag"]; ok {
+			if p.Type.Kind() != BooleanKind {
+				verr.Add(a, `parameter %s defines the "param:flag" metadata but is not a boolean`, n)
+			}
+			for _, wc := range wcs {
+				if wc == n {
+					verr.Add(a, `path parameter %s cannot define the "param:flag" metadata`, n)
+				}
+			}
+		}
+		if vals, ok := p.Metadata["param:bool-values"]; ok {
+			if p.Type.Kind() != BooleanKind {
+				verr.Add(a, `parameter %s defines the "param:bool-values" metadata but is not a boolean`, n)
+			}
+			if len(vals) != 2 || vals[0] == "" || vals[1] == "" || vals[0] == vals[1] {
+				verr.Add(a, `"param:bool-values" metadata of parameter %s must define two distinct non-empty strings`, n)
+			}
+		}
+		if vals, ok := p.Metadata["param:enum-map"]; ok {
+			if !p.Type.IsPrimitive() {
+				verr.Add(a, `parameter %s defines the "param:enum-map" metadata but is not of a primitive type`, n)
+			}
+			for _, v := range vals {
+				if !strings.Contains(v, "=") {
+					verr.Add(a, `invalid "param:enum-map" value %#v of parameter %s, must be of the form "wire=internal"`, v, n)
+				}
+			}
+		}
+		if elem := p; p.DefaultValue != nil {
+			if p.Type.IsArray() {
+				elem = p.Type.ToArray().ElemType
+			}
+			if elem.Validation != nil && elem.Validation.Format == "date" {
+				verr.Add(a, `parameter %s uses the "date" format and cannot have a default value`, n)
+			}
+		}
 		ctx := fmt.Sprintf("parameter %s", n)
 		verr.Merge(p.Validate(ctx, a))
 	}
+	if a.Params.Validation != nil {
+		for _, group := range a.Params.Validation.RequiredOneOf {
+			for _, n := range group {
+				if _, ok := params[n]; !ok {
+					verr.Add(a, `required one of parameter "%s" does not exist`, n)
+				}
+			}
+		}
+	}
 	for _, resp := range a.Responses {
 		verr.Merge(resp.Validate())
 	}
@@ -460,6 +718,33 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			verr.Add(parent, "%sdefault value %#v is not one of the accepted values: %#v", ctx, a.DefaultValue, a.Validation.Values)
 		}
 	}
+	// Make sure the validations can be satisfied by at least one value.
+	if v := a.Validation; v != nil {
+		if v.Values != nil && len(v.Values) == 0 {
+			verr.Add(parent, "%senum validation must list at least one value", ctx)
+		}
+		if v.Minimum != nil && v.Maximum != nil && *v.Minimum > *v.Maximum {
+			verr.Add(parent, "%sminimum %v is greater than maximum %v", ctx, *v.Minimum, *v.Maximum)
+		}
+		if v.MinLength != nil && v.MaxLength != nil && *v.MinLength > *v.MaxLength {
+			verr.Add(parent, "%sminimum length %d is greater than maximum length %d", ctx, *v.MinLength, *v.MaxLength)
+		}
+	}
+	if v, ok := a.Metadata["validation:func"]; ok && (len(v) == 0 || v[0] == "") {
+		verr.Add(parent, `%sthe "validation:func" metadata must define the name of the validator`, ctx)
+	}
+	if _, ok := a.Metadata["json:single-value"]; ok && !a.Type.IsArray() {
+		verr.Add(parent, `%sthe "json:single-value" metadata only applies to arrays`, ctx)
+	}
+	// Make sure the case transforms apply to strings and do not contradict each other.
+	_, lower := a.Metadata["transform:lower"]
+	_, upper := a.Metadata["transform:upper"]
+	if lower && upper {
+		verr.Add(parent, `%scannot define both the "transform:lower" and "transform:upper" metadata`, ctx)
+	}
+	if (lower || upper) && a.Type.Kind() != StringKind {
+		verr.Add(parent, `%sthe "transform:lower" and "transform:upper" metadata only apply to strings`, ctx)
+	}
 	o := a.Type.ToObject()
 	if o != nil {
 		for _, n := range a.AllRequired() {
@@ -474,6 +759,50 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 				verr.Add(parent, `%srequired field "%s" does not exist`, ctx, n)
 			}
 		}
+		if a.Validation != nil {
+			for _, group := range a.Validation.ForbiddenWith {
+				for _, n := range group {
+					if _, ok := o[n]; !ok {
+						verr.Add(parent, `%sforbidden with field "%s" does not exist`, ctx, n)
+					} else if a.IsRequired(n) || a.HasDefaultValue(n) {
+						verr.Add(parent, `%sforbidden with field "%s" cannot be required or have a default value`, ctx, n)
+					}
+				}
+			}
+			for _, group := range a.Validation.EqualLength {
+				for _, n := range group {
+					if att, ok := o[n]; !ok {
+						verr.Add(parent, `%sequal length field "%s" does not exist`, ctx, n)
+					} else if !att.Type.IsArray() {
+						verr.Add(parent, `%sequal length field "%s" must be an array`, ctx, n)
+					}
+				}
+			}
+			for _, rw := range a.Validation.RequiredWhen {
+				if _, ok := o[rw.Name]; !ok {
+					verr.Add(parent, `%srequired when field "%s" does not exist`, ctx, rw.Name)
+				} else if a.IsRequired(rw.Name) || a.HasDefaultValue(rw.Name) {
+					verr.Add(parent, `%srequired when field "%s" cannot be required or have a default value`, ctx, rw.Name)
+				}
+				trigger, ok := o[rw.Trigger]
+				if !ok {
+					verr.Add(parent, `%srequired when trigger field "%s" does not exist`, ctx, rw.Trigger)
+					continue
+				}
+				found := false
+				if trigger.Validation != nil {
+					for _, v := range trigger.Validation.Values {
+						if v == rw.Value {
+							found = true
+							break
+						}
+					}
+				}
+				if !found {
+					verr.Add(parent, `%srequired when trigger field "%s" must define an enum validation that lists %#v`, ctx, rw.Trigger, rw.Value)
+				}
+			}
+		}
 		for n, att := range o {
 			ctx = fmt.Sprintf("field %s", n)
 			verr.Merge(att.Validate(ctx, parent))
@@ -482,6 +811,16 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 		if a.Type.IsArray() {
 			elemType := a.Type.ToArray().ElemType
 			verr.Merge(elemType.Validate(ctx, a))
+			if a.Validation != nil && a.Validation.UniqueBy != "" {
+				key := a.Validation.UniqueBy
+				if eo := elemType.Type.ToObject(); eo == nil {
+					verr.Add(parent, `%sunique by validation requires the array elements to be objects`, ctx)
+				} else if katt, ok := eo[key]; !ok {
+					verr.Add(parent, `%sunique by key "%s" does not exist`, ctx, key)
+				} else if !katt.Type.IsPrimitive() || katt.Type.Kind() == AnyKind {
+					verr.Add(parent, `%sunique by key "%s" must be a primitive type other than any`, ctx, key)
+				}
+			}
 		}
 	}
 
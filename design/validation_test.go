@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
@@ -113,6 +114,67 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with the single value metadata on a non-array attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("json:single-value")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`the "json:single-value" metadata only applies to arrays`))
+			})
+		})
+
+		Context("with a param validator metadata that does not name the validator", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("validation:func")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`the "validation:func" metadata must define the name of the validator`))
+			})
+		})
+
+		Context("with a case transform on a non-string attribute", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Integer, func() {
+						Metadata("transform:lower")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`metadata only apply to strings`))
+			})
+		})
+
+		Context("with both case transforms", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Metadata("transform:lower")
+						Metadata("transform:upper")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`cannot define both the "transform:lower" and "transform:upper" metadata`))
+			})
+		})
+
 		Context("with a valid pattern validation", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -277,6 +339,68 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with a minimum greater than the maximum", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Integer, func() {
+						Minimum(10)
+						Maximum(5)
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field attName - minimum 10 is greater than maximum 5"))
+			})
+		})
+
+		Context("with a minimum length greater than the maximum length", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						MinLength(3)
+						MaxLength(2)
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field attName - minimum length 3 is greater than maximum length 2"))
+			})
+		})
+
+		Context("with equal minimum and maximum", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, Number, func() {
+						Minimum(1.5)
+						Maximum(1.5)
+					})
+				}
+			})
+
+			It("does not produce an error", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an empty enum validation", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Enum()
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("field attName - enum validation must list at least one value"))
+			})
+		})
+
 		Context("with a required field validation", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -293,6 +417,825 @@ var _ = Describe("Validation", func() {
 		})
 	})
 
+	Describe("ActionDefinition", func() {
+		Context("with multiple responses using the same status code", func() {
+			BeforeEach(func() {
+				dslengine.Reset()
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(GET("/"))
+						Response(OK)
+						Response("Success", func() {
+							Status(200)
+						})
+					})
+				})
+			})
+
+			It("reports a single error listing the responses", func() {
+				err := dslengine.Run()
+				Ω(err).Should(HaveOccurred())
+				Ω(err.Error()).Should(ContainSubstring("Multiple response definitions with status code 200: OK, Success"))
+				Ω(strings.Count(err.Error(), "Multiple response definitions")).Should(Equal(1))
+			})
+		})
+	})
+
+	Describe("matrix params", func() {
+		var segment string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/items/:item"))
+					Params(func() {
+						Param("item")
+						Param("color", func() {
+							Metadata("param:matrix", segment)
+						})
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("referring to an existing path param", func() {
+			BeforeEach(func() {
+				segment = "item"
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("referring to an unknown path param", func() {
+			BeforeEach(func() {
+				segment = "color"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`refers to path parameter "color" which does not exist`))
+			})
+		})
+	})
+
+	Describe("forbidden with validation", func() {
+		var other string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Type("order", func() {
+				Attribute("coupon", String)
+				Attribute("discount", Number)
+				Attribute("total", Number)
+				Required("total")
+				ForbiddenWith("coupon", other)
+			})
+			dslengine.Run()
+		})
+
+		Context("using an optional attribute", func() {
+			BeforeEach(func() {
+				other = "discount"
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("using an unknown attribute", func() {
+			BeforeEach(func() {
+				other = "rebate"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`forbidden with field "rebate" does not exist`))
+			})
+		})
+
+		Context("using a required attribute", func() {
+			BeforeEach(func() {
+				other = "total"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`forbidden with field "total" cannot be required`))
+			})
+		})
+	})
+
+	Describe("required when validation", func() {
+		var name, trigger string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Type("order", func() {
+				Attribute("deliveryMethod", String, func() {
+					Enum("ship", "pickup")
+				})
+				Attribute("shippingAddress", String)
+				Attribute("note", String)
+				Attribute("total", Number)
+				Required("total")
+				RequiredWhen(name, trigger, "ship")
+			})
+			dslengine.Run()
+		})
+
+		BeforeEach(func() {
+			name = "shippingAddress"
+			trigger = "deliveryMethod"
+		})
+
+		Context("using an enum value of the trigger", func() {
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("using a required attribute", func() {
+			BeforeEach(func() {
+				name = "total"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`required when field "total" cannot be required`))
+			})
+		})
+
+		Context("using a trigger without enum", func() {
+			BeforeEach(func() {
+				trigger = "note"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`required when trigger field "note" must define an enum validation that lists "ship"`))
+			})
+		})
+	})
+
+	Describe("equal length validation", func() {
+		var other string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Type("series", func() {
+				Attribute("labels", ArrayOf(String))
+				Attribute("values", ArrayOf(Number))
+				Attribute("unit", String)
+				EqualLength("labels", other)
+			})
+			dslengine.Run()
+		})
+
+		BeforeEach(func() {
+			other = "values"
+		})
+
+		Context("using array attributes", func() {
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("using an attribute that is not an array", func() {
+			BeforeEach(func() {
+				other = "unit"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`equal length field "unit" must be an array`))
+			})
+		})
+
+		Context("using an attribute that does not exist", func() {
+			BeforeEach(func() {
+				other = "missing"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`equal length field "missing" does not exist`))
+			})
+		})
+	})
+
+	Describe("unique by validation", func() {
+		var key string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			item := Type("item", func() {
+				Attribute("id", Integer)
+				Attribute("tags", ArrayOf(String))
+			})
+			Type("items", func() {
+				Attribute("items", ArrayOf(item), func() {
+					UniqueBy(key)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("using a primitive element attribute", func() {
+			BeforeEach(func() {
+				key = "id"
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("using an unknown element attribute", func() {
+			BeforeEach(func() {
+				key = "name"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unique by key "name" does not exist`))
+			})
+		})
+
+		Context("using a non primitive element attribute", func() {
+			BeforeEach(func() {
+				key = "tags"
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`unique by key "tags" must be a primitive type`))
+			})
+		})
+	})
+
+	Describe("big number params", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/"))
+					Params(dsl)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("of type array of strings", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("amounts", ArrayOf(String), func() {
+						Metadata("param:bignum")
+					})
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("of type integer", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("amount", Integer, func() {
+						Metadata("param:bignum")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("is not a string or an array of strings"))
+			})
+		})
+
+		Context("with a default value", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("amount", String, func() {
+						Metadata("param:bignum")
+						Default("10")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot have a default value"))
+			})
+		})
+	})
+
+	Describe("enum map params", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/"))
+					Params(dsl)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("with wire to internal value pairs", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("status", Integer, func() {
+						Metadata("param:enum-map", "inactive=0", "active=1")
+					})
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("status", Integer, func() {
+						Metadata("param:enum-map", "active")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`must be of the form "wire=internal"`))
+			})
+		})
+	})
+
+	Describe("params with sources", func() {
+		var sources []string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("id", String, func() {
+							Metadata("param:sources", "header:X-Id", "path")
+						})
+						Param("tenant", String, func() {
+							Metadata("param:sources", sources...)
+						})
+					})
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("reading from headers and query strings", func() {
+			BeforeEach(func() {
+				sources = []string{"header:X-Tenant-Id", "query"}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an unknown source", func() {
+			BeforeEach(func() {
+				sources = []string{"cookie"}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid "param:sources" value "cookie" of parameter tenant`))
+			})
+		})
+
+		Context("reading a param that is not a path param from the path", func() {
+			BeforeEach(func() {
+				sources = []string{"query", "path"}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`"path" source of parameter tenant requires a path parameter with the same name`))
+			})
+		})
+	})
+
+	Describe("flag params", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/:id"))
+					Params(dsl)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("with a boolean query param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("verbose", Boolean, func() {
+						Metadata("param:flag")
+					})
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a string param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("verbose", String, func() {
+						Metadata("param:flag")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`defines the "param:flag" metadata but is not a boolean`))
+			})
+		})
+
+		Context("with a path param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("id", Boolean, func() {
+						Metadata("param:flag")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`path parameter id cannot define the "param:flag" metadata`))
+			})
+		})
+	})
+
+	Describe("boolean params with custom strings", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/"))
+					Params(dsl)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("with a true and a false string", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("notify", Boolean, func() {
+						Metadata("param:bool-values", "on", "off")
+					})
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a single string", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("notify", Boolean, func() {
+						Metadata("param:bool-values", "on")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`must define two distinct non-empty strings`))
+			})
+		})
+
+		Context("with a string param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("notify", String, func() {
+						Metadata("param:bool-values", "on", "off")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`defines the "param:bool-values" metadata but is not a boolean`))
+			})
+		})
+	})
+
+	Describe("basic auth params", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/:id"))
+					Params(dsl)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("reading the username into a string", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("user", String, func() {
+						Metadata("param:basic-auth", "username")
+					})
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("user", String, func() {
+						Metadata("param:basic-auth", "user")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`must be "username" or "password"`))
+			})
+		})
+
+		Context("on a path param", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("id", String, func() {
+						Metadata("param:basic-auth", "username")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`path parameter id cannot define the "param:basic-auth" metadata`))
+			})
+		})
+	})
+
+	Describe("payload variants", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Type("Cat", func() {
+				Attribute("kind", String)
+				Attribute("lives", Integer)
+			})
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(POST("/"))
+					Payload(dsl)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("with a required discriminator and existing variant types", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("payload:discriminator", "kind")
+					Metadata("payload:variants", "cat=Cat")
+					Member("kind", String)
+					Required("kind")
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an optional discriminator", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("payload:discriminator", "kind")
+					Metadata("payload:variants", "cat=Cat")
+					Member("kind", String)
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`discriminator "kind" must be required`))
+			})
+		})
+
+		Context("with an unknown variant type", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Metadata("payload:discriminator", "kind")
+					Metadata("payload:variants", "dog=Dog")
+					Member("kind", String)
+					Required("kind")
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`refers to type "Dog" which does not exist`))
+			})
+		})
+	})
+
+	Describe("payload presence", func() {
+		var consumes []interface{}
+		var named bool
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {
+				Consumes(consumes...)
+			})
+			patch := Type("Patch", func() {
+				Attribute("name", String)
+				Metadata("payload:presence")
+			})
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(PATCH("/"))
+					if named {
+						Payload(patch)
+					} else {
+						Payload(func() {
+							Metadata("payload:presence")
+							Member("name", String)
+						})
+					}
+				})
+			})
+			dslengine.Run()
+		})
+
+		BeforeEach(func() {
+			consumes = []interface{}{"application/json"}
+			named = false
+		})
+
+		Context("with an inline payload and an API consuming JSON", func() {
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with an API consuming XML", func() {
+			BeforeEach(func() {
+				consumes = []interface{}{"application/json", "application/xml"}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`requires the API to only consume JSON, it consumes application/xml`))
+			})
+		})
+
+		Context("with a user type payload", func() {
+			BeforeEach(func() {
+				named = true
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`only applies to payloads defined inline, Patch is a user type`))
+			})
+		})
+	})
+
+	Describe("date params", func() {
+		var dsl func()
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			Resource("res", func() {
+				Action("act", func() {
+					Routing(GET("/"))
+					Params(dsl)
+				})
+			})
+			dslengine.Run()
+		})
+
+		Context("of type array of dates", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("days", ArrayOf(String, func() {
+						Format("date")
+					}))
+				}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a default value", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Param("day", String, func() {
+						Format("date")
+						Default("2017-01-01")
+					})
+				}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`uses the "date" format and cannot have a default value`))
+			})
+		})
+	})
+
+	Describe("API host", func() {
+		var host string
+		var schemes []string
+
+		JustBeforeEach(func() {
+			dslengine.Reset()
+			API("test", func() {
+				Host(host)
+				Scheme(schemes...)
+			})
+			dslengine.Run()
+		})
+
+		Context("with a port matching one of the schemes", func() {
+			BeforeEach(func() {
+				host = "example.com:443"
+				schemes = []string{"http", "https"}
+			})
+
+			It("validates", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with a port matching none of the schemes", func() {
+			BeforeEach(func() {
+				host = "example.com:443"
+				schemes = []string{"http"}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring("does not match any of the API schemes http"))
+			})
+		})
+
+		Context("with a scheme", func() {
+			BeforeEach(func() {
+				host = "https://example.com"
+				schemes = []string{"https"}
+			})
+
+			It("reports an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+				Ω(dslengine.Errors.Error()).Should(ContainSubstring(`invalid host "https://example.com"`))
+			})
+		})
+	})
+
 	Describe("EncoderDefinition", func() {
 		var (
 			enc           *EncodingDefinition
@@ -77,6 +77,17 @@ type (
 		DSLFunc func()
 	}
 
+	// RequiredWhenDefinition makes the field with the given name required when the trigger
+	// field is set to the given value.
+	RequiredWhenDefinition struct {
+		// Name is the name of the field that becomes required.
+		Name string
+		// Trigger is the name of the field whose value makes the field required.
+		Trigger string
+		// Value is the value of the trigger field that makes the field required.
+		Value interface{}
+	}
+
 	// ValidationDefinition contains validation rules for an attribute.
 	ValidationDefinition struct {
 		// Values represents an enum validation as described at
@@ -88,6 +99,9 @@ type (
 		// PatternValidationDefinition represents a pattern validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor33
 		Pattern string
+		// Charset is the name of the character class all the characters of string
+		// attributes must belong to.
+		Charset string
 		// Minimum represents an minimum value validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor21.
 		Minimum *float64
@@ -103,6 +117,21 @@ type (
 		// Required list the required fields of object attributes as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor61.
 		Required []string
+		// RequiredOneOf lists groups of fields of object attributes where at least one field
+		// of each group must be present.
+		RequiredOneOf [][]string
+		// ForbiddenWith lists groups of fields of object attributes where the fields listed
+		// after the first field of each group may not be set when the first field is set.
+		ForbiddenWith [][]string
+		// RequiredWhen lists the fields of object attributes that are required only when
+		// another field holds a given value.
+		RequiredWhen []*RequiredWhenDefinition
+		// EqualLength lists groups of array fields of object attributes that must have the
+		// same number of elements.
+		EqualLength [][]string
+		// UniqueBy is the name of the attribute of the array element objects whose values
+		// must be unique across the array elements.
+		UniqueBy string
 	}
 )
 
@@ -135,6 +164,9 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.Pattern == "" {
 		v.Pattern = other.Pattern
 	}
+	if v.Charset == "" {
+		v.Charset = other.Charset
+	}
 	if v.Minimum == nil || (other.Minimum != nil && *v.Minimum > *other.Minimum) {
 		v.Minimum = other.Minimum
 	}
@@ -147,7 +179,14 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.MaxLength == nil || (other.MaxLength != nil && *v.MaxLength < *other.MaxLength) {
 		v.MaxLength = other.MaxLength
 	}
+	if v.UniqueBy == "" {
+		v.UniqueBy = other.UniqueBy
+	}
 	v.AddRequired(other.Required)
+	v.RequiredOneOf = append(v.RequiredOneOf, other.RequiredOneOf...)
+	v.ForbiddenWith = append(v.ForbiddenWith, other.ForbiddenWith...)
+	v.RequiredWhen = append(v.RequiredWhen, other.RequiredWhen...)
+	v.EqualLength = append(v.EqualLength, other.EqualLength...)
 }
 
 // AddRequired merges the required fields from other into v
@@ -171,7 +210,7 @@ func (v *ValidationDefinition) HasRequiredOnly() bool {
 	if len(v.Values) > 0 {
 		return false
 	}
-	if v.Format != "" || v.Pattern != "" {
+	if v.Format != "" || v.Pattern != "" || v.Charset != "" || v.UniqueBy != "" || len(v.ForbiddenWith) > 0 || len(v.RequiredWhen) > 0 || len(v.EqualLength) > 0 {
 		return false
 	}
 	if (v.Minimum != nil) || (v.Maximum != nil) || (v.MaxLength != nil) {
@@ -183,13 +222,19 @@ func (v *ValidationDefinition) HasRequiredOnly() bool {
 // Dup makes a shallow dup of the validation.
 func (v *ValidationDefinition) Dup() *ValidationDefinition {
 	return &ValidationDefinition{
-		Values:    v.Values,
-		Format:    v.Format,
-		Pattern:   v.Pattern,
-		Minimum:   v.Minimum,
-		Maximum:   v.Maximum,
-		MinLength: v.MinLength,
-		MaxLength: v.MaxLength,
-		Required:  v.Required,
+		Values:        v.Values,
+		Format:        v.Format,
+		Pattern:       v.Pattern,
+		Charset:       v.Charset,
+		Minimum:       v.Minimum,
+		Maximum:       v.Maximum,
+		MinLength:     v.MinLength,
+		MaxLength:     v.MaxLength,
+		Required:      v.Required,
+		RequiredOneOf: v.RequiredOneOf,
+		ForbiddenWith: v.ForbiddenWith,
+		RequiredWhen:  v.RequiredWhen,
+		EqualLength:   v.EqualLength,
+		UniqueBy:      v.UniqueBy,
 	}
 }
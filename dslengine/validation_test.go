@@ -122,6 +122,36 @@ var _ = Describe("Validation", func() {
 			})
 		})
 
+		Context("with a valid charset validation", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Charset("alphanumeric")
+					})
+				}
+			})
+
+			It("records the validation", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(att.Validation).ShouldNot(BeNil())
+				Ω(att.Validation.Charset).Should(Equal("alphanumeric"))
+			})
+		})
+
+		Context("with an unknown charset validation", func() {
+			BeforeEach(func() {
+				dsl = func() {
+					Attribute(attName, String, func() {
+						Charset("klingon")
+					})
+				}
+			})
+
+			It("produces an error", func() {
+				Ω(dslengine.Errors).Should(HaveOccurred())
+			})
+		})
+
 		Context("with an invalid format validation type", func() {
 			BeforeEach(func() {
 				dsl = func() {
@@ -1,14 +1,20 @@
 package goa
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/gob"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
 	"mime"
+	"net/http"
+	"reflect"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 )
 
 type (
@@ -76,6 +82,274 @@ func NewJSONEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
 // NewJSONDecoder is an adapter for the encoding package JSON decoder.
 func NewJSONDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
 
+// NewLenientJSONDecoder returns a JSON decoder that also accepts numbers encoded as JSON strings
+// for numeric fields, for example both 42 and "42" decode into an int field. Use it to support
+// loosely typed clients by registering it with the Consumes DSL:
+//
+//	Consumes("application/json", func() {
+//		Package("github.com/goadesign/goa")
+//		Function("NewLenientJSONDecoder")
+//	})
+func NewLenientJSONDecoder(r io.Reader) Decoder { return &lenientJSONDecoder{r: r} }
+
+// lenientJSONDecoder is the decoder returned by NewLenientJSONDecoder.
+type lenientJSONDecoder struct {
+	r io.Reader
+}
+
+// Decode decodes the JSON value read from the underlying reader into v, unquoting the strings
+// that correspond to numeric fields of v first.
+func (d *lenientJSONDecoder) Decode(v interface{}) error {
+	var raw interface{}
+	dec := json.NewDecoder(d.r)
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	b, err := json.Marshal(unquoteNumbers(raw, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// unquoteNumbers replaces the strings in raw that correspond to numeric values of type t with
+// JSON numbers.
+func unquoteNumbers(raw interface{}, t reflect.Type) interface{} {
+	if t == nil {
+		return raw
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if s, ok := raw.(string); ok {
+			n := json.Number(strings.TrimSpace(s))
+			if _, err := n.Float64(); err == nil {
+				return n
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if vals, ok := raw.([]interface{}); ok {
+			for i, val := range vals {
+				vals[i] = unquoteNumbers(val, t.Elem())
+			}
+		}
+	case reflect.Map:
+		if vals, ok := raw.(map[string]interface{}); ok {
+			for k, val := range vals {
+				vals[k] = unquoteNumbers(val, t.Elem())
+			}
+		}
+	case reflect.Struct:
+		if vals, ok := raw.(map[string]interface{}); ok {
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				name := strings.Split(f.Tag.Get("json"), ",")[0]
+				if name == "-" || f.PkgPath != "" {
+					continue
+				}
+				if name == "" {
+					name = f.Name
+				}
+				for k, val := range vals {
+					if strings.EqualFold(k, name) {
+						vals[k] = unquoteNumbers(val, f.Type)
+					}
+				}
+			}
+		}
+	}
+	return raw
+}
+
+// NewAnyCaseJSONDecoder returns a JSON decoder that accepts both the snake_case and the camelCase
+// spelling of object keys, for example both "user_id" and "userId" decode into the field tagged
+// with json:"user_id". This is useful to support clients during a migration from one casing to
+// the other. The keys of the raw JSON objects are renamed to the names found in the json tags of
+// the matching struct fields before the value is decoded, keys that match exactly take precedence.
+// Register the decoder with the Consumes DSL:
+//
+//	Consumes("application/json", func() {
+//		Package("github.com/goadesign/goa")
+//		Function("NewAnyCaseJSONDecoder")
+//	})
+func NewAnyCaseJSONDecoder(r io.Reader) Decoder { return &anyCaseJSONDecoder{r: r} }
+
+// anyCaseJSONDecoder is the decoder returned by NewAnyCaseJSONDecoder.
+type anyCaseJSONDecoder struct {
+	r io.Reader
+}
+
+// Decode decodes the JSON value read from the underlying reader into v, renaming the object keys
+// that match the fields of v in a different casing first.
+func (d *anyCaseJSONDecoder) Decode(v interface{}) error {
+	var raw interface{}
+	dec := json.NewDecoder(d.r)
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	b, err := json.Marshal(normalizeJSONKeys(raw, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// normalizeJSONKeys renames the keys of the objects in raw that correspond to the fields of the
+// structs of type t spelled with a different casing.
+func normalizeJSONKeys(raw interface{}, t reflect.Type) interface{} {
+	if t == nil {
+		return raw
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if vals, ok := raw.([]interface{}); ok {
+			for i, val := range vals {
+				vals[i] = normalizeJSONKeys(val, t.Elem())
+			}
+		}
+	case reflect.Map:
+		if vals, ok := raw.(map[string]interface{}); ok {
+			for k, val := range vals {
+				vals[k] = normalizeJSONKeys(val, t.Elem())
+			}
+		}
+	case reflect.Struct:
+		if vals, ok := raw.(map[string]interface{}); ok {
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				name := strings.Split(f.Tag.Get("json"), ",")[0]
+				if name == "-" || f.PkgPath != "" {
+					continue
+				}
+				if name == "" {
+					name = f.Name
+				}
+				if _, ok := vals[name]; !ok {
+					for k, val := range vals {
+						if strings.EqualFold(camelize(k), camelize(name)) {
+							delete(vals, k)
+							vals[name] = val
+							break
+						}
+					}
+				}
+				if val, ok := vals[name]; ok {
+					vals[name] = normalizeJSONKeys(val, f.Type)
+				}
+			}
+		}
+	}
+	return raw
+}
+
+// camelize converts the snake_case string s to camelCase, other strings are returned unchanged.
+func camelize(s string) string {
+	res := make([]rune, 0, len(s))
+	upper := false
+	for _, r := range s {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		res = append(res, r)
+	}
+	return string(res)
+}
+
+// UnmarshalSingleValue decodes the JSON data into the slice pointed to by v. data may either be
+// a JSON array or a single value in which case the slice is set to a slice with one element. v is
+// left unchanged if data is empty or null. The code generated for payload fields with the
+// "json:single-value" metadata uses this function to accept both forms.
+func UnmarshalSingleValue(data []byte, v interface{}) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if data[0] == '[' {
+		return json.Unmarshal(data, v)
+	}
+	slice := reflect.ValueOf(v).Elem()
+	elem := reflect.New(slice.Type().Elem())
+	if err := json.Unmarshal(data, elem.Interface()); err != nil {
+		return err
+	}
+	slice.Set(reflect.Append(reflect.MakeSlice(slice.Type(), 0, 1), elem.Elem()))
+	return nil
+}
+
+// NewBOMTolerantDecoder wraps the given decoder factory so that a leading UTF-8 byte order mark
+// and any white space that precedes it or follows it are skipped before the body is decoded.
+// This makes it possible to accept requests from clients that prefix their bodies with a BOM.
+func NewBOMTolerantDecoder(f DecoderFunc) DecoderFunc {
+	return func(r io.Reader) Decoder {
+		d := f(&bomSkipper{r: r})
+		if rd, ok := d.(ResettableDecoder); ok {
+			return &resettableBOMDecoder{rd}
+		}
+		return d
+	}
+}
+
+// NewBOMTolerantJSONDecoder is a JSON decoder that skips a leading UTF-8 byte order mark. Register
+// it with the Consumes DSL:
+//
+//	Consumes("application/json", func() {
+//		Package("github.com/goadesign/goa")
+//		Function("NewBOMTolerantJSONDecoder")
+//	})
+func NewBOMTolerantJSONDecoder(r io.Reader) Decoder {
+	return NewBOMTolerantDecoder(NewJSONDecoder)(r)
+}
+
+// resettableBOMDecoder makes sure that decoders reused from the decoder pool keep skipping the
+// byte order mark.
+type resettableBOMDecoder struct {
+	ResettableDecoder
+}
+
+// Reset resets the underlying decoder so that it reads from r after the byte order mark.
+func (d *resettableBOMDecoder) Reset(r io.Reader) {
+	d.ResettableDecoder.Reset(&bomSkipper{r: r})
+}
+
+// bomSkipper is a reader that skips the leading white space and UTF-8 byte order marks of the
+// underlying reader on first read.
+type bomSkipper struct {
+	r  io.Reader
+	br *bufio.Reader
+}
+
+// Read implements io.Reader.
+func (s *bomSkipper) Read(p []byte) (int, error) {
+	if s.br == nil {
+		s.br = bufio.NewReader(s.r)
+		for {
+			c, _, err := s.br.ReadRune()
+			if err != nil {
+				break
+			}
+			if c != '\uFEFF' && c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+				s.br.UnreadRune()
+				break
+			}
+		}
+	}
+	return s.br.Read(p)
+}
+
 // NewXMLEncoder is an adapter for the encoding package XML encoder.
 func NewXMLEncoder(w io.Writer) Encoder { return xml.NewEncoder(w) }
 
@@ -88,6 +362,22 @@ func NewGobEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
 // NewGobDecoder is an adapter for the encoding package gob decoder.
 func NewGobDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
 
+// RequestedView returns the name of the response view selected by the request. The view is read
+// from the query string parameter with the given name or else from the "view" parameter of the
+// media types listed in the Accept header, e.g. "application/json; view=tiny". RequestedView
+// returns the empty string if the request does not select a view.
+func RequestedView(req *http.Request, param string) string {
+	if v := req.URL.Query().Get(param); v != "" {
+		return v
+	}
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		if _, params, err := mime.ParseMediaType(accept); err == nil && params["view"] != "" {
+			return params["view"]
+		}
+	}
+	return ""
+}
+
 // NewHTTPEncoder creates an encoder that maps HTTP content types to low level encoders.
 func NewHTTPEncoder() *HTTPEncoder {
 	return &HTTPEncoder{
@@ -29,5 +29,22 @@ The DSL above causes the generated code to use the JSON encoder implemented in t
 github.com/goadesign/goa/encoding/json rather than the stdlib JSON encoder. Third party encoders
 can easily be used via adapter packages that expose the NewDecoder and NewEcoder methods expected
 by the generated code, see the json package as an example.
+
+The goa package also provides a JSON decoder that accepts numbers encoded as JSON strings for
+numeric fields which is useful to support loosely typed clients:
+
+	Consumes("application/json", func() {
+		Package("github.com/goadesign/goa")
+		Function("NewLenientJSONDecoder")
+	})
+
+Some clients prefix JSON bodies with a UTF-8 byte order mark which the standard JSON decoder
+rejects. NewBOMTolerantDecoder wraps any decoder factory so that the byte order mark and the
+surrounding white space are skipped, NewBOMTolerantJSONDecoder applies it to the JSON decoder:
+
+	Consumes("application/json", func() {
+		Package("github.com/goadesign/goa")
+		Function("NewBOMTolerantJSONDecoder")
+	})
 */
 package encoding
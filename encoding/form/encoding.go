@@ -2,16 +2,29 @@
 Package form provides a "application/x-www-form-encoding" encoder and decoder.  It uses
 github.com/ajg/form for the actual implementation which can be used directly as well.  The goal of
 this package is to raise awareness of the package above and its direct compatibility with goa.
+
+The decoder also supports repeated fields such as "tags=a&tags=b": the values of repeated fields
+and of fields that correspond to slice fields of the target struct are decoded into slices. Each
+value is converted to the type of the slice elements.
 */
 package form
 
 import (
 	"io"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"strings"
 
 	"github.com/ajg/form"
 	"github.com/goadesign/goa"
 )
 
+// Decoder is the form decoder returned by NewDecoder.
+type Decoder struct {
+	r io.Reader
+}
+
 // NewEncoder returns a form encoder that writes to w.
 func NewEncoder(w io.Writer) goa.Encoder {
 	return form.NewEncoder(w)
@@ -19,5 +32,59 @@ func NewEncoder(w io.Writer) goa.Encoder {
 
 // NewDecoder returns a form decoder that reads from r.
 func NewDecoder(r io.Reader) goa.Decoder {
-	return form.NewDecoder(r)
+	return &Decoder{r: r}
+}
+
+// Decode reads form-encoded data and decodes it into v.
+func (d *Decoder) Decode(v interface{}) error {
+	b, err := ioutil.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+	vals, err := url.ParseQuery(string(b))
+	if err != nil {
+		return err
+	}
+	return form.DecodeValues(v, indexRepeated(vals, sliceFields(reflect.TypeOf(v))))
+}
+
+// indexRepeated rewrites the keys of the repeated fields and of the fields listed in slices so
+// that their values get decoded into slices, e.g. "tags=a&tags=b" becomes "tags._=a&tags._=b".
+func indexRepeated(vals url.Values, slices map[string]bool) url.Values {
+	res := make(url.Values, len(vals))
+	for k, vs := range vals {
+		if !strings.HasSuffix(k, "._") && (slices[k] || (slices == nil && len(vs) > 1)) {
+			k += "._"
+		}
+		res[k] = append(res[k], vs...)
+	}
+	return res
+}
+
+// sliceFields returns the form names of the slice fields of the struct type t points to. It
+// returns nil if t is not a pointer to a struct.
+func sliceFields(t reflect.Type) map[string]bool {
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	t = t.Elem()
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() != reflect.Slice || ft.Elem().Kind() == reflect.Uint8 {
+			continue
+		}
+		name := f.Name
+		if tag := strings.Split(f.Tag.Get("form"), ",")[0]; tag != "" {
+			name = tag
+		}
+		if name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
 }
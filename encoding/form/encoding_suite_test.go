@@ -0,0 +1,13 @@
+package form_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestFormEncoding(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Form Encoding Suite")
+}
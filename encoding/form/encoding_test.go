@@ -0,0 +1,71 @@
+package form_test
+
+import (
+	"bytes"
+
+	"github.com/goadesign/goa/encoding/form"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FormEncoding", func() {
+	type Payload struct {
+		Name  *string  `form:"name,omitempty"`
+		Tags  []string `form:"tags,omitempty"`
+		Sizes []int    `form:"sizes,omitempty"`
+	}
+
+	var body string
+	var payload Payload
+	var decodeErr error
+
+	JustBeforeEach(func() {
+		payload = Payload{}
+		decodeErr = form.NewDecoder(bytes.NewBufferString(body)).Decode(&payload)
+	})
+
+	Context("with repeated fields", func() {
+		BeforeEach(func() {
+			body = "name=n&tags=a&tags=b&sizes=1&sizes=2"
+		})
+
+		It("decodes the values into slices", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(*payload.Name).Should(Equal("n"))
+			Ω(payload.Tags).Should(Equal([]string{"a", "b"}))
+			Ω(payload.Sizes).Should(Equal([]int{1, 2}))
+		})
+	})
+
+	Context("with a single value for a slice field", func() {
+		BeforeEach(func() {
+			body = "tags=a"
+		})
+
+		It("decodes the value into a slice", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(payload.Tags).Should(Equal([]string{"a"}))
+		})
+	})
+
+	Context("with indexed fields", func() {
+		BeforeEach(func() {
+			body = "tags.0=a&tags.1=b"
+		})
+
+		It("decodes the values into slices", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(payload.Tags).Should(Equal([]string{"a", "b"}))
+		})
+	})
+
+	Context("with an invalid element value", func() {
+		BeforeEach(func() {
+			body = "sizes=1&sizes=foo"
+		})
+
+		It("fails", func() {
+			Ω(decodeErr).Should(HaveOccurred())
+		})
+	})
+})
@@ -12,15 +12,35 @@ var (
 	// Handle used by encoder and decoder.
 	Handle codec.JsonHandle
 
+	// StrictHandle is the handle used by the decoder returned by NewStrictDecoder.
+	StrictHandle codec.JsonHandle
+
 	_ goa.ResettableDecoder = (*codec.Decoder)(nil)
 	_ goa.ResettableEncoder = (*codec.Encoder)(nil)
 )
 
+func init() {
+	StrictHandle.ErrorIfNoField = true
+}
+
 // NewDecoder returns a JSON decoder.
 func NewDecoder(r io.Reader) goa.Decoder {
 	return codec.NewDecoder(r, &Handle)
 }
 
+// NewStrictDecoder returns a JSON decoder that fails to decode objects that contain fields that
+// do not exist in the target struct. The generated code turns the error into a 400 response
+// describing the unexpected field. The strict decoder applies to all the actions that accept
+// JSON, use the Consumes DSL to register it:
+//
+//	Consumes("application/json", func() {
+//		Package("github.com/goadesign/goa/encoding/json")
+//		Function("NewStrictDecoder")
+//	})
+func NewStrictDecoder(r io.Reader) goa.Decoder {
+	return codec.NewDecoder(r, &StrictHandle)
+}
+
 // NewEncoder returns a JSON encoder.
 func NewEncoder(w io.Writer) goa.Encoder {
 	return codec.NewEncoder(w, &Handle)
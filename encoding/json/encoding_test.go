@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	goajson "github.com/goadesign/goa/encoding/json"
 	"github.com/goadesign/goa/uuid"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -71,3 +72,45 @@ var _ = Describe("JsonEncoding", func() {
 
 	})
 })
+
+var _ = Describe("NewStrictDecoder", func() {
+	type Payload struct {
+		Name string `json:"name"`
+	}
+
+	var body string
+	var payload Payload
+	var decodeErr error
+
+	JustBeforeEach(func() {
+		payload = Payload{}
+		decodeErr = goajson.NewStrictDecoder(bytes.NewBufferString(body)).Decode(&payload)
+	})
+
+	Context("with known fields", func() {
+		BeforeEach(func() {
+			body = `{"name":"foo"}`
+		})
+
+		It("decodes", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(payload.Name).Should(Equal("foo"))
+		})
+	})
+
+	Context("with an unknown field", func() {
+		BeforeEach(func() {
+			body = `{"name":"foo","nmae":"bar"}`
+		})
+
+		It("fails and describes the field", func() {
+			Ω(decodeErr).Should(HaveOccurred())
+			Ω(decodeErr.Error()).Should(ContainSubstring("nmae"))
+		})
+
+		It("is ignored by the default decoder", func() {
+			err := goajson.NewDecoder(bytes.NewBufferString(body)).Decode(&payload)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+})
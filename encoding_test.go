@@ -0,0 +1,280 @@
+package goa_test
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NewLenientJSONDecoder", func() {
+	type item struct {
+		Count *int `json:"count,omitempty"`
+	}
+	type payload struct {
+		ID    *int              `json:"id,omitempty"`
+		Price float64           `json:"price"`
+		Name  *string           `json:"name,omitempty"`
+		Items []*item           `json:"items,omitempty"`
+		Sizes map[string]uint32 `json:"sizes,omitempty"`
+	}
+
+	var body string
+	var decoded payload
+	var decodeErr error
+
+	JustBeforeEach(func() {
+		decoded = payload{}
+		decodeErr = goa.NewLenientJSONDecoder(strings.NewReader(body)).Decode(&decoded)
+	})
+
+	Context("with numbers", func() {
+		BeforeEach(func() {
+			body = `{"id":42,"price":1.5}`
+		})
+
+		It("decodes them", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(*decoded.ID).Should(Equal(42))
+			Ω(decoded.Price).Should(Equal(1.5))
+		})
+	})
+
+	Context("with numbers encoded as strings", func() {
+		BeforeEach(func() {
+			body = `{"id":"42","price":"1.5","name":"7","items":[{"count":"3"}],"sizes":{"s":"2"}}`
+		})
+
+		It("decodes them", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(*decoded.ID).Should(Equal(42))
+			Ω(decoded.Price).Should(Equal(1.5))
+			Ω(*decoded.Name).Should(Equal("7"))
+			Ω(decoded.Items).Should(HaveLen(1))
+			Ω(*decoded.Items[0].Count).Should(Equal(3))
+			Ω(decoded.Sizes).Should(Equal(map[string]uint32{"s": 2}))
+		})
+	})
+
+	Context("with a string that is not a number", func() {
+		BeforeEach(func() {
+			body = `{"id":"foo"}`
+		})
+
+		It("fails", func() {
+			Ω(decodeErr).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("NewAnyCaseJSONDecoder", func() {
+	type item struct {
+		UnitPrice *int `json:"unit_price,omitempty"`
+	}
+	type payload struct {
+		UserID    *string          `json:"user_id,omitempty"`
+		FirstName *string          `json:"first_name,omitempty"`
+		Items     []*item          `json:"line_items,omitempty"`
+		Extra     map[string]*item `json:"extra,omitempty"`
+	}
+
+	var body string
+	var decoded payload
+	var decodeErr error
+
+	JustBeforeEach(func() {
+		decoded = payload{}
+		decodeErr = goa.NewAnyCaseJSONDecoder(strings.NewReader(body)).Decode(&decoded)
+	})
+
+	Context("with snake_case keys", func() {
+		BeforeEach(func() {
+			body = `{"user_id":"u1","first_name":"Jo","line_items":[{"unit_price":3}]}`
+		})
+
+		It("decodes them", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(*decoded.UserID).Should(Equal("u1"))
+			Ω(*decoded.FirstName).Should(Equal("Jo"))
+			Ω(decoded.Items).Should(HaveLen(1))
+			Ω(*decoded.Items[0].UnitPrice).Should(Equal(3))
+		})
+	})
+
+	Context("with camelCase keys", func() {
+		BeforeEach(func() {
+			body = `{"userId":"u1","firstName":"Jo","lineItems":[{"unitPrice":3}],"extra":{"k":{"unitPrice":4}}}`
+		})
+
+		It("decodes them", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(*decoded.UserID).Should(Equal("u1"))
+			Ω(*decoded.FirstName).Should(Equal("Jo"))
+			Ω(decoded.Items).Should(HaveLen(1))
+			Ω(*decoded.Items[0].UnitPrice).Should(Equal(3))
+			Ω(*decoded.Extra["k"].UnitPrice).Should(Equal(4))
+		})
+	})
+
+	Context("with both casings of the same key", func() {
+		BeforeEach(func() {
+			body = `{"userId":"camel","user_id":"snake"}`
+		})
+
+		It("uses the key that matches exactly", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(*decoded.UserID).Should(Equal("snake"))
+		})
+	})
+})
+
+var _ = Describe("UnmarshalSingleValue", func() {
+	var data string
+	var tags []string
+	var err error
+
+	BeforeEach(func() {
+		tags = nil
+	})
+
+	JustBeforeEach(func() {
+		err = goa.UnmarshalSingleValue([]byte(data), &tags)
+	})
+
+	Context("with an array", func() {
+		BeforeEach(func() {
+			data = `["x", "y"]`
+		})
+
+		It("decodes the array", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(tags).Should(Equal([]string{"x", "y"}))
+		})
+	})
+
+	Context("with a single value", func() {
+		BeforeEach(func() {
+			data = ` "x"`
+		})
+
+		It("decodes a slice with one element", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(tags).Should(Equal([]string{"x"}))
+		})
+	})
+
+	Context("with null", func() {
+		BeforeEach(func() {
+			data = "null"
+		})
+
+		It("leaves the slice unchanged", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(tags).Should(BeNil())
+		})
+	})
+
+	Context("with a value of the wrong type", func() {
+		BeforeEach(func() {
+			data = "42"
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("NewBOMTolerantJSONDecoder", func() {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	var body string
+	var decoded payload
+	var decodeErr error
+
+	JustBeforeEach(func() {
+		decoded = payload{}
+		decodeErr = goa.NewBOMTolerantJSONDecoder(strings.NewReader(body)).Decode(&decoded)
+	})
+
+	Context("with a body prefixed with a byte order mark", func() {
+		BeforeEach(func() {
+			body = "\xef\xbb\xbf" + `{"name":"bottle"}`
+		})
+
+		It("decodes the body", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(decoded.Name).Should(Equal("bottle"))
+		})
+	})
+
+	Context("with white space around the byte order mark", func() {
+		BeforeEach(func() {
+			body = "\r\n\xef\xbb\xbf  \n" + `{"name":"bottle"}`
+		})
+
+		It("decodes the body", func() {
+			Ω(decodeErr).ShouldNot(HaveOccurred())
+			Ω(decoded.Name).Should(Equal("bottle"))
+		})
+	})
+
+	Context("used by a HTTP decoder", func() {
+		It("decodes BOM prefixed bodies", func() {
+			dec := goa.NewHTTPDecoder()
+			dec.Register(goa.NewBOMTolerantDecoder(goa.NewJSONDecoder), "application/json")
+			var v payload
+			err := dec.Decode(&v, strings.NewReader("\xef\xbb\xbf"+`{"name":"wine"}`), "application/json")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(v.Name).Should(Equal("wine"))
+		})
+	})
+})
+
+var _ = Describe("RequestedView", func() {
+	var url, accept string
+	var view string
+
+	BeforeEach(func() {
+		url = "/bottles/1"
+		accept = ""
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequest("GET", url, nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		view = goa.RequestedView(req, "view")
+	})
+
+	It("returns the empty string when no view is selected", func() {
+		Ω(view).Should(BeEmpty())
+	})
+
+	Context("with a view query string parameter", func() {
+		BeforeEach(func() {
+			url = "/bottles/1?view=tiny"
+			accept = "application/json; view=full"
+		})
+
+		It("returns the query string parameter value", func() {
+			Ω(view).Should(Equal("tiny"))
+		})
+	})
+
+	Context("with an Accept header media type view parameter", func() {
+		BeforeEach(func() {
+			accept = "text/plain, application/json; view=full"
+		})
+
+		It("returns the media type parameter value", func() {
+			Ω(view).Should(Equal("full"))
+		})
+	})
+})
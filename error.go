@@ -162,6 +162,17 @@ func MissingParamError(name string) error {
 	return ErrInvalidRequest(msg, "name", name)
 }
 
+// MissingOneOfParamsError is the error produced for requests that are missing all the path or
+// querystring parameters of a group where at least one parameter must be provided.
+func MissingOneOfParamsError(names []string) error {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%#v", n)
+	}
+	msg := fmt.Sprintf("at least one of the parameters %s is required", strings.Join(quoted, ", "))
+	return ErrInvalidRequest(msg, "names", names)
+}
+
 // InvalidAttributeTypeError is the error produced when the type of payload field does not match
 // the type defined in the design.
 func InvalidAttributeTypeError(ctx string, val interface{}, expected string) error {
@@ -175,6 +186,34 @@ func MissingAttributeError(ctx, name string) error {
 	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx)
 }
 
+// EmptyAttributeError is the error produced when a required array or map field of a request
+// payload is present but empty.
+func EmptyAttributeError(ctx, name string) error {
+	msg := fmt.Sprintf("attribute %#v of %s must not be empty", name, ctx)
+	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx)
+}
+
+// MissingAttributeWhenError is the error produced when a request payload is missing a field that
+// is required because another field is set to a given value.
+func MissingAttributeWhenError(ctx, name, trigger string, value interface{}) error {
+	msg := fmt.Sprintf("attribute %#v of %s is missing and required when %#v is %#v", name, ctx, trigger, value)
+	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx, "trigger", trigger, "value", value)
+}
+
+// LengthMismatchError is the error produced when a request payload sets two array fields that must
+// have the same number of elements with arrays of different lengths.
+func LengthMismatchError(ctx, name, other string, length, otherLength int) error {
+	msg := fmt.Sprintf("attribute %#v of %s has %d elements but must have as many as %#v which has %d", name, ctx, length, other, otherLength)
+	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx, "other", other, "length", length, "other_length", otherLength)
+}
+
+// ForbiddenAttributeError is the error produced when a request payload sets a field that may not
+// be set together with another field that is also set.
+func ForbiddenAttributeError(ctx, name, other string) error {
+	msg := fmt.Sprintf("attribute %#v of %s must not be set when %#v is set", name, ctx, other)
+	return ErrInvalidRequest(msg, "attribute", name, "parent", ctx, "conflict", other)
+}
+
 // MissingHeaderError is the error produced when a request is missing a required header.
 func MissingHeaderError(name string) error {
 	msg := fmt.Sprintf("missing required HTTP header %#v", name)
@@ -206,6 +245,14 @@ func InvalidPatternError(ctx, target string, pattern string) error {
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "regexp", pattern)
 }
 
+// InvalidCharsetError is the error produced when the value of a parameter or payload field
+// contains a character that does not belong to the character class defined in the design. r is
+// the first disallowed character and pos its byte offset in target.
+func InvalidCharsetError(ctx, target string, charset Charset, r rune, pos int) error {
+	msg := fmt.Sprintf("%s must only contain %s characters but got %q at position %d in value %#v", ctx, charset, r, pos, target)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "charset", charset)
+}
+
 // InvalidRangeError is the error produced when the value of a parameter or payload field does
 // not match the range validation defined in the design. value may be a int or a float64.
 func InvalidRangeError(ctx string, target interface{}, value interface{}, min bool) error {
@@ -228,6 +275,24 @@ func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool)
 	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "len", ln, "comp", comp, "expected", value)
 }
 
+// InvalidItemCountError is the error produced when the number of items of an array parameter or
+// payload field does not match the length validation defined in the design.
+func InvalidItemCountError(ctx string, target interface{}, count, value int, min bool) error {
+	comp := "at least"
+	if !min {
+		comp = "at most"
+	}
+	msg := fmt.Sprintf("%s must contain %s %d items but got %d items", ctx, comp, value, count)
+	return ErrInvalidRequest(msg, "attribute", ctx, "value", target, "count", count, "comp", comp, "expected", value)
+}
+
+// DuplicateItemError is the error produced when two items of an array payload field share the same
+// value for the attribute whose values must be unique across the array items.
+func DuplicateItemError(ctx, key string, val interface{}) error {
+	msg := fmt.Sprintf("items of %s must have unique %#v values but got value %#v more than once", ctx, key, val)
+	return ErrInvalidRequest(msg, "attribute", ctx, "key", key, "value", val)
+}
+
 // NoAuthMiddleware is the error produced when goa is unable to lookup a auth middleware for a
 // security scheme defined in the design.
 func NoAuthMiddleware(schemeName string) error {
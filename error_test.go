@@ -67,6 +67,23 @@ var _ = Describe("MissingParaerror", func() {
 	})
 })
 
+var _ = Describe("MissingOneOfParamsError", func() {
+	var valErr error
+	names := []string{"name", "email"}
+
+	JustBeforeEach(func() {
+		valErr = MissingOneOfParamsError(names)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(`"name", "email"`))
+		Ω(err.Meta).Should(HaveKeyWithValue("names", names))
+	})
+})
+
 var _ = Describe("InvalidAttributeTypeError", func() {
 	var valErr error
 	ctx := "ctx"
@@ -183,6 +200,26 @@ var _ = Describe("InvalidPatternError", func() {
 	})
 })
 
+var _ = Describe("InvalidCharsetError", func() {
+	var valErr error
+	ctx := "ctx"
+	target := "ab-c"
+
+	JustBeforeEach(func() {
+		valErr = InvalidCharsetError(ctx, target, CharsetAlphanumeric, '-', 2)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring(target))
+		Ω(err.Detail).Should(ContainSubstring("alphanumeric"))
+		Ω(err.Detail).Should(ContainSubstring(`'-' at position 2`))
+	})
+})
+
 var _ = Describe("InvalidRangeError", func() {
 	var valErr error
 	var value interface{}
@@ -277,6 +314,121 @@ var _ = Describe("InvalidLengthError", func() {
 	})
 })
 
+var _ = Describe("InvalidItemCountError", func() {
+	const ctx = "ctx"
+
+	var valErr error
+
+	BeforeEach(func() {
+		valErr = InvalidItemCountError(ctx, []string{"target"}, 1, 2, true)
+	})
+
+	It("creates a http error describing the number of items", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(Equal("ctx must contain at least 2 items but got 1 items"))
+		Ω(err.Meta).Should(HaveKeyWithValue("count", 1))
+	})
+})
+
+var _ = Describe("EmptyAttributeError", func() {
+	const ctx = "ctx"
+
+	var valErr error
+
+	BeforeEach(func() {
+		valErr = EmptyAttributeError(ctx, "ids")
+	})
+
+	It("creates a http error describing the empty attribute", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(Equal(`attribute "ids" of ctx must not be empty`))
+		Ω(err.Meta).Should(HaveKeyWithValue("attribute", "ids"))
+	})
+})
+
+var _ = Describe("ForbiddenAttributeError", func() {
+	const ctx = "ctx"
+
+	var valErr error
+
+	BeforeEach(func() {
+		valErr = ForbiddenAttributeError(ctx, "discount", "coupon")
+	})
+
+	It("creates a http error describing the conflicting attributes", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(Equal(`attribute "discount" of ctx must not be set when "coupon" is set`))
+		Ω(err.Meta).Should(HaveKeyWithValue("attribute", "discount"))
+		Ω(err.Meta).Should(HaveKeyWithValue("conflict", "coupon"))
+	})
+})
+
+var _ = Describe("MissingAttributeWhenError", func() {
+	const ctx = "ctx"
+
+	var valErr error
+
+	BeforeEach(func() {
+		valErr = MissingAttributeWhenError(ctx, "shippingAddress", "deliveryMethod", "ship")
+	})
+
+	It("creates a http error describing the condition", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(Equal(`attribute "shippingAddress" of ctx is missing and required when "deliveryMethod" is "ship"`))
+		Ω(err.Meta).Should(HaveKeyWithValue("attribute", "shippingAddress"))
+		Ω(err.Meta).Should(HaveKeyWithValue("trigger", "deliveryMethod"))
+		Ω(err.Meta).Should(HaveKeyWithValue("value", "ship"))
+	})
+})
+
+var _ = Describe("LengthMismatchError", func() {
+	const ctx = "ctx"
+
+	var valErr error
+
+	BeforeEach(func() {
+		valErr = LengthMismatchError(ctx, "labels", "values", 2, 3)
+	})
+
+	It("creates a http error describing both lengths", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(Equal(`attribute "labels" of ctx has 2 elements but must have as many as "values" which has 3`))
+		Ω(err.Meta).Should(HaveKeyWithValue("attribute", "labels"))
+		Ω(err.Meta).Should(HaveKeyWithValue("other", "values"))
+		Ω(err.Meta).Should(HaveKeyWithValue("length", 2))
+		Ω(err.Meta).Should(HaveKeyWithValue("other_length", 3))
+	})
+})
+
+var _ = Describe("DuplicateItemError", func() {
+	const ctx = "ctx"
+
+	var valErr error
+
+	BeforeEach(func() {
+		valErr = DuplicateItemError(ctx, "id", 42)
+	})
+
+	It("creates a http error describing the duplicate value", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&ErrorResponse{}))
+		err := valErr.(*ErrorResponse)
+		Ω(err.Detail).Should(Equal(`items of ctx must have unique "id" values but got value 42 more than once`))
+		Ω(err.Meta).Should(HaveKeyWithValue("key", "id"))
+		Ω(err.Meta).Should(HaveKeyWithValue("value", 42))
+	})
+})
+
 var _ = Describe("Merge", func() {
 	var err, err2 error
 	var mErr *ErrorResponse
@@ -8,7 +8,8 @@ import (
 	"github.com/goadesign/goa/design"
 )
 
-// Finalizer is the code generator for the 'Finalize' type methods.
+// Finalizer is the code generator for the 'Finalize' type methods. The generated code sets the
+// default values and applies the transforms of the string fields.
 type Finalizer struct {
 	assignmentT      *template.Template
 	arrayAssignmentT *template.Template
@@ -79,6 +80,18 @@ func (f *Finalizer) recurse(root, att *design.AttributeDefinition, target string
 				}
 				buf.WriteString(RunTemplate(f.assignmentT, data))
 			}
+			if catt.Type == design.String {
+				field := fmt.Sprintf("%s.%s", target, Goify(n, true))
+				if t := Transform(catt, "*"+field); t != "*"+field {
+					if !first {
+						buf.WriteByte('\n')
+					} else {
+						first = false
+					}
+					buf.WriteString(fmt.Sprintf("%sif %s != nil {\n%s\t*%s = %s\n%s}",
+						Tabs(depth), field, Tabs(depth), field, t, Tabs(depth)))
+				}
+			}
 			a := f.recurse(root, catt, fmt.Sprintf("%s.%s", target, Goify(n, true)), depth+1).String()
 			if a != "" {
 				if catt.Type.IsObject() {
@@ -107,6 +120,22 @@ func (f *Finalizer) recurse(root, att *design.AttributeDefinition, target string
 	return buf
 }
 
+// Transform returns the Go expression that applies the transforms listed in the "transform:trim",
+// "transform:lower" and "transform:upper" metadata of the given string attribute to the value
+// of expr. The white space is trimmed first. Transform returns expr if the attribute does not
+// define any transform.
+func Transform(att *design.AttributeDefinition, expr string) string {
+	if _, ok := att.Metadata["transform:trim"]; ok {
+		expr = fmt.Sprintf("strings.TrimSpace(%s)", expr)
+	}
+	if _, ok := att.Metadata["transform:lower"]; ok {
+		expr = fmt.Sprintf("strings.ToLower(%s)", expr)
+	} else if _, ok := att.Metadata["transform:upper"]; ok {
+		expr = fmt.Sprintf("strings.ToUpper(%s)", expr)
+	}
+	return expr
+}
+
 // PrintVal prints the given value corresponding to the given data type.
 // The value is already checked for the compatibility with the data type.
 func PrintVal(t design.DataType, val interface{}) string {
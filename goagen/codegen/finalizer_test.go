@@ -2,6 +2,7 @@ package codegen_test
 
 import (
 	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -54,6 +55,27 @@ var _ = Describe("Struct finalize code generation", func() {
 		})
 	})
 
+	Context("given a string field with transforms", func() {
+		BeforeEach(func() {
+			att = &design.AttributeDefinition{
+				Type: &design.Object{
+					"foo": &design.AttributeDefinition{
+						Type: design.String,
+						Metadata: dslengine.MetadataDefinition{
+							"transform:trim":  nil,
+							"transform:upper": nil,
+						},
+					},
+				},
+			}
+			target = "ut"
+		})
+		It("transforms the field", func() {
+			code := finalizer.Code(att, target, 0)
+			Ω(code).Should(Equal(transformCode))
+		})
+	})
+
 	Context("given an array field", func() {
 		BeforeEach(func() {
 			att = &design.AttributeDefinition{
@@ -178,6 +200,10 @@ if ut.Foo == nil {
 	ut.Foo = &defaultFoo
 }`
 
+	transformCode = `if ut.Foo != nil {
+	*ut.Foo = strings.ToUpper(strings.TrimSpace(*ut.Foo))
+}`
+
 	arrayAssignmentCode = `if ut.Foo == nil {
 	ut.Foo = []string{"bar", "baz"}
 }`
@@ -101,10 +101,33 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 	}
 }
 
+// GoTypeFields returns the Go code that declares the fields of the Go struct which matches the
+// object data structure definition, one field per line. It returns the empty string if the data
+// structure is not an object. tabs, jsonTags and private have the same meaning as in GoTypeDef.
+func GoTypeFields(ds design.DataStructure, tabs int, jsonTags, private bool) string {
+	def := ds.Definition()
+	obj, ok := def.Type.(design.Object)
+	if !ok {
+		return ""
+	}
+	var buffer bytes.Buffer
+	writeTypeFields(&buffer, obj, def, tabs, jsonTags, private)
+	return buffer.String()
+}
+
 // goTypeDefObject returns the Go code that defines a Go struct.
 func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs int, jsonTags, private bool) string {
 	var buffer bytes.Buffer
 	buffer.WriteString("struct {\n")
+	writeTypeFields(&buffer, obj, def, tabs, jsonTags, private)
+	WriteTabs(&buffer, tabs)
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
+// writeTypeFields writes the declarations of the Go struct fields that match the object
+// attributes to buffer.
+func writeTypeFields(buffer *bytes.Buffer, obj design.Object, def *design.AttributeDefinition, tabs int, jsonTags, private bool) {
 	keys := make([]string, len(obj))
 	i := 0
 	for n := range obj {
@@ -113,7 +136,7 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 	}
 	sort.Strings(keys)
 	for _, name := range keys {
-		WriteTabs(&buffer, tabs+1)
+		WriteTabs(buffer, tabs+1)
 		field := obj[name]
 		typedef := GoTypeDef(field, tabs+1, jsonTags, private)
 		if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
@@ -131,9 +154,6 @@ func goTypeDefObject(obj design.Object, def *design.AttributeDefinition, tabs in
 		}
 		buffer.WriteString(fmt.Sprintf("%s%s %s%s\n", desc, fname, typedef, tags))
 	}
-	WriteTabs(&buffer, tabs)
-	buffer.WriteString("}")
-	return buffer.String()
 }
 
 // attributeTags computes the struct field tags.
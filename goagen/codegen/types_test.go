@@ -431,6 +431,43 @@ var _ = Describe("code generation", func() {
 		})
 
 	})
+
+	Describe("GoTypeFields", func() {
+		var att *AttributeDefinition
+		var fields string
+
+		JustBeforeEach(func() {
+			fields = codegen.GoTypeFields(att, 0, true, false)
+		})
+
+		Context("given an object", func() {
+			BeforeEach(func() {
+				att = &AttributeDefinition{
+					Type: Object{
+						"foo": &AttributeDefinition{Type: Integer},
+						"bar": &AttributeDefinition{Type: String},
+					},
+					Validation: &dslengine.ValidationDefinition{Required: []string{"foo"}},
+				}
+			})
+
+			It("produces the struct fields go code", func() {
+				expected := "	Bar *string `form:\"bar,omitempty\" json:\"bar,omitempty\" xml:\"bar,omitempty\"`\n" +
+					"	Foo int `form:\"foo\" json:\"foo\" xml:\"foo\"`\n"
+				Ω(fields).Should(Equal(expected))
+			})
+		})
+
+		Context("given an array", func() {
+			BeforeEach(func() {
+				att = &AttributeDefinition{Type: &Array{ElemType: &AttributeDefinition{Type: String}}}
+			})
+
+			It("produces no fields", func() {
+				Ω(fields).Should(BeEmpty())
+			})
+		})
+	})
 })
 
 var _ = Describe("GoTypeTransform", func() {
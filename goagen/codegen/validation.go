@@ -7,17 +7,24 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 )
 
 var (
-	enumValT     *template.Template
-	formatValT   *template.Template
-	patternValT  *template.Template
-	minMaxValT   *template.Template
-	lengthValT   *template.Template
-	requiredValT *template.Template
+	enumValT         *template.Template
+	uniqueValT       *template.Template
+	formatValT       *template.Template
+	patternValT      *template.Template
+	charsetValT      *template.Template
+	minMaxValT       *template.Template
+	finiteValT       *template.Template
+	lengthValT       *template.Template
+	requiredValT     *template.Template
+	forbiddenValT    *template.Template
+	requiredWhenValT *template.Template
+	equalLengthValT  *template.Template
 )
 
 //  init instantiates the templates.
@@ -28,34 +35,62 @@ func init() {
 		"slice":    toSlice,
 		"oneof":    oneof,
 		"constant": constant,
+		"charset":  charsetConstant,
 		"goifyAtt": GoifyAtt,
 		"add":      Add,
+		"literal":  contextLiteral,
 	}
 	if enumValT, err = template.New("enum").Funcs(fm).Parse(enumValTmpl); err != nil {
 		panic(err)
 	}
+	if uniqueValT, err = template.New("unique").Funcs(fm).Parse(uniqueValTmpl); err != nil {
+		panic(err)
+	}
 	if formatValT, err = template.New("format").Funcs(fm).Parse(formatValTmpl); err != nil {
 		panic(err)
 	}
 	if patternValT, err = template.New("pattern").Funcs(fm).Parse(patternValTmpl); err != nil {
 		panic(err)
 	}
+	if charsetValT, err = template.New("charset").Funcs(fm).Parse(charsetValTmpl); err != nil {
+		panic(err)
+	}
 	if minMaxValT, err = template.New("minMax").Funcs(fm).Parse(minMaxValTmpl); err != nil {
 		panic(err)
 	}
+	if finiteValT, err = template.New("finite").Funcs(fm).Parse(finiteValTmpl); err != nil {
+		panic(err)
+	}
 	if lengthValT, err = template.New("length").Funcs(fm).Parse(lengthValTmpl); err != nil {
 		panic(err)
 	}
 	if requiredValT, err = template.New("required").Funcs(fm).Parse(requiredValTmpl); err != nil {
 		panic(err)
 	}
+	if forbiddenValT, err = template.New("forbidden").Funcs(fm).Parse(forbiddenValTmpl); err != nil {
+		panic(err)
+	}
+	if requiredWhenValT, err = template.New("requiredWhen").Funcs(fm).Parse(requiredWhenValTmpl); err != nil {
+		panic(err)
+	}
+	if equalLengthValT, err = template.New("equalLength").Funcs(fm).Parse(equalLengthValTmpl); err != nil {
+		panic(err)
+	}
 }
 
 // Validator is the code generator for the 'Validate' type methods.
 type Validator struct {
+	// JSONPointer causes the generated code to build the contexts given to the validation
+	// errors as JSON pointers (RFC 6901) such as "/items/0/name" rather than dotted paths such
+	// as "raw.items[0].name". The root context given to Code is ignored in this case.
+	JSONPointer bool
+
 	arrayValT *template.Template
+	hashValT  *template.Template
 	userValT  *template.Template
 	seen      map[string]*bytes.Buffer
+	// nesting is the number of array and hash loops enclosing the code being generated.
+	nesting int
 }
 
 // NewValidator instantiates a validate code generator.
@@ -77,6 +112,10 @@ func NewValidator() *Validator {
 	if err != nil {
 		panic(err)
 	}
+	v.hashValT, err = template.New("hash").Funcs(fm).Parse(hashValTmpl)
+	if err != nil {
+		panic(err)
+	}
 	v.userValT, err = template.New("user").Funcs(fm).Parse(userValTmpl)
 	if err != nil {
 		panic(err)
@@ -84,14 +123,40 @@ func NewValidator() *Validator {
 	return v
 }
 
+// elemCode produces the validation code for the elements or keys of arrays and hashes. The code
+// calls the Validate method of user and media types.
+func (v *Validator) elemCode(att *design.AttributeDefinition, target, context string, depth int) string {
+	val := v.code(att, true, false, false, target, context, depth+1, false)
+	if val == "" {
+		return ""
+	}
+	switch att.Type.(type) {
+	case *design.UserTypeDefinition, *design.MediaTypeDefinition:
+		val = RunTemplate(v.userValT, map[string]interface{}{
+			"depth":  depth + 2,
+			"target": target,
+		})
+		val = fmt.Sprintf("%sif %s != nil {\n%s\n%s}", Tabs(depth+1), target, val, Tabs(depth+1))
+	}
+	return val
+}
+
 // Code produces Go code that runs the validation checks recursively over the given attribute.
 func (v *Validator) Code(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
+	if v.JSONPointer {
+		// The pointer of the root value is the empty string.
+		context = ""
+	}
+	return v.code(att, nonzero, required, hasDefault, target, context, depth, private)
+}
+
+// code produces the validation code of Code given the context of the validated value.
+func (v *Validator) code(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
 	if _, ok := att.Metadata["struct:field:type"]; ok {
 		// Skip validation generation for attributes with custom types
 		return ""
 	}
-	buf := v.recurse(att, nonzero, required, hasDefault, target, context, depth, private)
-	return buf.String()
+	return v.recurse(att, nonzero, required, hasDefault, target, context, depth, private).String()
 }
 
 func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) *bytes.Buffer {
@@ -138,22 +203,25 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 	} else if a := att.Type.ToArray(); a != nil {
 		// Perform any validation on the array type such as MinLength, MaxLength, etc.
 		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
+		if unique := uniqueByCode(att, a, target, context, depth, private); unique != "" {
+			if validation != "" {
+				validation += "\n"
+			}
+			validation += unique
+		}
 		first := true
 		if validation != "" {
 			buf.WriteString(validation)
 			first = false
 		}
-		val := v.Code(a.ElemType, true, false, false, "e", context+"[*]", depth+1, false)
+		// Tag the element errors with their index, the context ends up
+		// between backticks in the generated code.
+		i, e := v.loopVar("i"), v.loopVar("e")
+		elemContext := v.elemContext(context, "fmt.Sprint("+i+")", false)
+		v.nesting++
+		val := v.elemCode(a.ElemType, e, elemContext, depth)
+		v.nesting--
 		if val != "" {
-			switch a.ElemType.Type.(type) {
-			case *design.UserTypeDefinition, *design.MediaTypeDefinition:
-				// For user and media types, call the Validate method
-				val = RunTemplate(v.userValT, map[string]interface{}{
-					"depth":  depth + 2,
-					"target": "e",
-				})
-				val = fmt.Sprintf("%sif e != nil {\n%s\n%s}", Tabs(depth+1), val, Tabs(depth+1))
-			}
 			data := map[string]interface{}{
 				"elemType":   a.ElemType,
 				"context":    context,
@@ -161,6 +229,9 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 				"depth":      1,
 				"private":    private,
 				"validation": val,
+				"index":      strings.Contains(val, strings.TrimSuffix(elemContext, " + `")),
+				"i":          i,
+				"e":          e,
 			}
 			validation = RunTemplate(v.arrayValT, data)
 			if !first {
@@ -170,6 +241,43 @@ func (v *Validator) recurse(att *design.AttributeDefinition, nonzero, required,
 			}
 			buf.WriteString(validation)
 		}
+	} else if h := att.Type.ToHash(); h != nil {
+		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
+		first := true
+		if validation != "" {
+			buf.WriteString(validation)
+			first = false
+		}
+		// Tag the key and value errors with the key, the context ends up
+		// between backticks in the generated code.
+		k, e := v.loopVar("k"), v.loopVar("e")
+		elemContext := v.elemContext(context, "fmt.Sprint("+k+")", true)
+		v.nesting++
+		keyVal := v.elemCode(h.KeyType, k, elemContext, depth)
+		elemVal := v.elemCode(h.ElemType, e, elemContext, depth)
+		v.nesting--
+		if keyVal != "" || elemVal != "" {
+			var vals []string
+			for _, val := range []string{keyVal, elemVal} {
+				if val != "" {
+					vals = append(vals, val)
+				}
+			}
+			data := map[string]interface{}{
+				"target":     target,
+				"depth":      depth,
+				"validation": strings.Join(vals, "\n"),
+				"key":        keyVal != "" || strings.Contains(elemVal, strings.TrimSuffix(elemContext, " + `")),
+				"elem":       elemVal != "",
+				"k":          k,
+				"e":          e,
+			}
+			validation = RunTemplate(v.hashValT, data)
+			if !first {
+				buf.WriteByte('\n')
+			}
+			buf.WriteString(validation)
+		}
 	} else {
 		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
 		if validation != "" {
@@ -230,7 +338,7 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 			att.IsRequired(n),
 			att.HasDefaultValue(n),
 			fmt.Sprintf("%s.%s", target, GoifyAtt(catt, n, true)),
-			fmt.Sprintf("%s.%s", context, n),
+			v.attContext(context, n),
 			dp,
 			private,
 		).String()
@@ -244,6 +352,49 @@ func (v *Validator) recurseAttribute(att, catt *design.AttributeDefinition, n, t
 	return validation
 }
 
+// loopVar returns the name of the loop variable with the given base name for the array or hash
+// loop being generated. Loops nested in other loops use numbered names so that the inner loop
+// variables do not shadow the outer ones, for example "e2" for the elements of an array of arrays.
+func (v *Validator) loopVar(base string) string {
+	if v.nesting == 0 {
+		return base
+	}
+	return fmt.Sprintf("%s%d", base, v.nesting+1)
+}
+
+// attContext returns the context of the validation errors of the child attribute n given the
+// context of its parent.
+func (v *Validator) attContext(context, n string) string {
+	if v.JSONPointer {
+		return context + "/" + goa.EscapeJSONPointer(n)
+	}
+	return fmt.Sprintf("%s.%s", context, n)
+}
+
+// elemContext returns the context of the validation errors of the array element or hash entry
+// whose index or key is the result of the Go expression key. The context ends up between backticks
+// in the generated code. escape indicates whether the key may contain characters that must be
+// escaped in JSON pointers.
+func (v *Validator) elemContext(context, key string, escape bool) string {
+	if v.JSONPointer {
+		if escape {
+			key = "goa.EscapeJSONPointer(" + key + ")"
+		}
+		return context + "/` + " + key + " + `"
+	}
+	return context + "[` + " + key + " + `]"
+}
+
+// contextLiteral returns the Go expression of the given context. The context is the content of a
+// raw string literal which may be interrupted to concatenate the expressions of array indices and
+// hash keys, the literal is closed right after the last expression when it ends with one.
+func contextLiteral(context string) string {
+	if strings.HasSuffix(context, " + `") {
+		return "`" + strings.TrimSuffix(context, " + `")
+	}
+	return "`" + context + "`"
+}
+
 // ValidationChecker produces Go code that runs the validation defined in the given attribute
 // definition against the content of the variable named target recursively.
 // context is used to keep track of recursion to produce helpful error messages in case of type
@@ -267,13 +418,54 @@ func ValidationChecker(att *design.AttributeDefinition, nonzero, required, hasDe
 		"string":    att.Type.Kind() == design.StringKind,
 		"array":     att.Type.IsArray(),
 		"hash":      att.Type.IsHash(),
+		"number":    att.Type.Kind() == design.NumberKind,
 		"depth":     depth,
 		"private":   private,
 	}
+	if _, ok := att.Metadata["validation:allow-empty"]; ok && !required {
+		data["allowEmpty"] = att.Type.Kind() == design.StringKind
+	}
 	res := validationsCode(att.Validation, data)
 	return strings.Join(res, "\n")
 }
 
+// uniqueByCode produces Go code that checks that the values of the element attribute named by the
+// UniqueBy validation of the given array attribute are unique across the array elements.
+func uniqueByCode(att *design.AttributeDefinition, a *design.Array, target, context string, depth int, private bool) string {
+	if att.Validation == nil || att.Validation.UniqueBy == "" {
+		return ""
+	}
+	key := att.Validation.UniqueBy
+	elem := a.ElemType
+	if ds, ok := elem.Type.(design.DataStructure); ok {
+		elem = ds.Definition()
+	}
+	o := elem.Type.ToObject()
+	if o == nil {
+		return ""
+	}
+	katt, ok := o[key]
+	if !ok || !katt.Type.IsPrimitive() {
+		return ""
+	}
+	isPointer := private || (!elem.IsRequired(key) && !elem.HasDefaultValue(key) && !elem.IsNonZero(key))
+	field := "e." + GoifyAtt(katt, key, true)
+	keyVal := field
+	if isPointer {
+		keyVal = "*" + field
+	}
+	return RunTemplate(uniqueValT, map[string]interface{}{
+		"target":    target,
+		"context":   context,
+		"depth":     depth,
+		"key":       key,
+		"keyType":   GoTypeRef(katt.Type, nil, 0, false),
+		"field":     field,
+		"keyVal":    keyVal,
+		"isPointer": isPointer,
+	})
+}
+
 func validationsCode(validation *dslengine.ValidationDefinition, data map[string]interface{}) (res []string) {
 	if validation == nil {
 		return nil
@@ -296,6 +488,32 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 			res = append(res, val)
 		}
 	}
+	if charset := validation.Charset; charset != "" {
+		data["charset"] = charset
+		if val := RunTemplate(charsetValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if number, _ := data["number"].(bool); number && (validation.Minimum != nil || validation.Maximum != nil) {
+		// NaN compares false against both bounds and an infinite value passes the check of
+		// the opposite bound, reject the values that the range checks below would accept.
+		delete(data, "min")
+		delete(data, "max")
+		data["isMin"] = validation.Minimum != nil
+		data["infSign"] = 0
+		if min := validation.Minimum; min != nil {
+			data["min"] = *min
+			if validation.Maximum == nil {
+				data["infSign"] = 1
+			}
+		} else {
+			data["max"] = *validation.Maximum
+			data["infSign"] = -1
+		}
+		if val := RunTemplate(finiteValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if min := validation.Minimum; min != nil {
 		data["min"] = *min
 		data["isMin"] = true
@@ -329,19 +547,159 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 		}
 	}
 	if required := validation.Required; len(required) > 0 {
-		var val string
-		for i, r := range required {
-			if i > 0 {
-				val += "\n"
+		att := data["attribute"].(*design.AttributeDefinition)
+		var vals []string
+		for _, r := range required {
+			// Attributes with a default value are always set once the defaults are
+			// applied, their zero value may also be the default value.
+			if att.HasDefaultValue(r) {
+				continue
+			}
+			// Read-only attributes are not sent in request bodies which are decoded into
+			// the private types.
+			if data["private"].(bool) && isReadOnly(att, r) {
+				continue
 			}
 			data["required"] = r
-			val += RunTemplate(requiredValT, data)
+			vals = append(vals, RunTemplate(requiredValT, data))
+		}
+		if len(vals) > 0 {
+			res = append(res, strings.Join(vals, "\n"))
+		}
+	}
+	if forbidden := validation.ForbiddenWith; len(forbidden) > 0 {
+		att := data["attribute"].(*design.AttributeDefinition)
+		target := data["target"].(string)
+		private := data["private"].(bool)
+		var vals []string
+		for _, group := range forbidden {
+			for _, other := range group[1:] {
+				data["name"] = group[0]
+				data["nameSet"] = isSetCode(att, group[0], target, private)
+				data["other"] = other
+				data["otherSet"] = isSetCode(att, other, target, private)
+				if val := RunTemplate(forbiddenValT, data); val != "" {
+					vals = append(vals, val)
+				}
+			}
+		}
+		if len(vals) > 0 {
+			res = append(res, strings.Join(vals, "\n"))
+		}
+	}
+	if requiredWhen := validation.RequiredWhen; len(requiredWhen) > 0 {
+		att := data["attribute"].(*design.AttributeDefinition)
+		target := data["target"].(string)
+		private := data["private"].(bool)
+		var vals []string
+		for _, rw := range requiredWhen {
+			data["name"] = rw.Name
+			data["nameSet"] = isSetCode(att, rw.Name, target, private)
+			data["trigger"] = rw.Trigger
+			data["triggerVal"] = rw.Value
+			data["triggerIs"] = isValueCode(att, rw.Trigger, rw.Value, target, private)
+			if val := RunTemplate(requiredWhenValT, data); val != "" {
+				vals = append(vals, val)
+			}
+		}
+		if len(vals) > 0 {
+			res = append(res, strings.Join(vals, "\n"))
+		}
+	}
+	if equalLength := validation.EqualLength; len(equalLength) > 0 {
+		att := data["attribute"].(*design.AttributeDefinition)
+		target := data["target"].(string)
+		private := data["private"].(bool)
+		var vals []string
+		for _, group := range equalLength {
+			for _, other := range group[1:] {
+				// Optional arrays are only compared when both are set.
+				var sets []string
+				for _, n := range []string{group[0], other} {
+					if !att.IsRequired(n) {
+						sets = append(sets, isSetCode(att, n, target, private))
+					}
+				}
+				data["name"] = group[0]
+				data["nameField"] = fieldCode(att, group[0], target)
+				data["other"] = other
+				data["otherField"] = fieldCode(att, other, target)
+				data["sets"] = sets
+				if val := RunTemplate(equalLengthValT, data); val != "" {
+					vals = append(vals, val)
+				}
+			}
+		}
+		if len(vals) > 0 {
+			res = append(res, strings.Join(vals, "\n"))
 		}
-		res = append(res, val)
 	}
 	return
 }
 
+// fieldCode produces the Go expression that refers to the field generated for the child attribute
+// of att with the given name.
+func fieldCode(att *design.AttributeDefinition, name, target string) string {
+	catt := att.Type.ToObject()[name]
+	if catt == nil {
+		return target + "." + Goify(name, true)
+	}
+	return fmt.Sprintf("%s.%s", target, GoifyAtt(catt, name, true))
+}
+
+// isReadOnly returns true if the child attribute of att with the given name is marked with the
+// swagger:read-only metadata.
+func isReadOnly(att *design.AttributeDefinition, name string) bool {
+	if catt := att.Type.ToObject()[name]; catt != nil {
+		_, ok := catt.Metadata["swagger:read-only"]
+		return ok
+	}
+	return false
+}
+
+// isValueCode produces a Go expression that evaluates to true if the field generated for the
+// child attribute of att with the given name is set to the given value. The expression evaluates
+// to false when the field is a nil pointer.
+func isValueCode(att *design.AttributeDefinition, name string, value interface{}, target string, private bool) string {
+	catt := att.Type.ToObject()[name]
+	if catt == nil {
+		return "false"
+	}
+	field := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, name, true))
+	if private || att.IsPrimitivePointer(name) {
+		return fmt.Sprintf("%s != nil && *%s == %#v", field, field, value)
+	}
+	return fmt.Sprintf("%s == %#v", field, value)
+}
+
+// isSetCode produces a Go expression that evaluates to true if the field generated for the child
+// attribute of att with the given name is set. Pointer, slice and map fields are set when not nil,
+// other fields are set when they do not hold the zero value.
+func isSetCode(att *design.AttributeDefinition, name, target string, private bool) string {
+	catt := att.Type.ToObject()[name]
+	if catt == nil {
+		return "false"
+	}
+	field := fmt.Sprintf("%s.%s", target, GoifyAtt(catt, name, true))
+	if private || !catt.Type.IsPrimitive() || att.IsPrimitivePointer(name) {
+		return field + " != nil"
+	}
+	switch catt.Type.Kind() {
+	case design.BooleanKind:
+		return field
+	case design.IntegerKind, design.NumberKind:
+		return field + " != 0"
+	case design.StringKind:
+		return field + ` != ""`
+	case design.DateTimeKind:
+		return "!" + field + ".IsZero()"
+	case design.UUIDKind:
+		return fmt.Sprintf("(%s != uuid.UUID{})", field)
+	default:
+		return field + " != nil"
+	}
+}
+
 // oneof produces code that compares target with each element of vals and ORs
 // the result, e.g. "target == 1 || target == 2".
 func oneof(target string, vals []interface{}) string {
@@ -357,6 +715,8 @@ func constant(formatName string) string {
 	switch formatName {
 	case "date-time":
 		return "goa.FormatDateTime"
+	case "date":
+		return "goa.FormatDate"
 	case "email":
 		return "goa.FormatEmail"
 	case "hostname":
@@ -377,13 +737,51 @@ func constant(formatName string) string {
 		return "goa.FormatRegexp"
 	case "rfc1123":
 		return "goa.FormatRFC1123"
+	case "byte":
+		return "goa.FormatByte"
 	}
 	panic("unknown format") // bug
 }
 
+// charsetConstant returns the runtime constant for the character class with the given name.
+func charsetConstant(charset string) string {
+	switch charset {
+	case "alpha":
+		return "goa.CharsetAlpha"
+	case "numeric":
+		return "goa.CharsetNumeric"
+	case "alphanumeric":
+		return "goa.CharsetAlphanumeric"
+	case "hex":
+		return "goa.CharsetHex"
+	case "ascii":
+		return "goa.CharsetASCII"
+	case "printable":
+		return "goa.CharsetPrintable"
+	}
+	panic("unknown charset") // bug
+}
+
 const (
-	arrayValTmpl = `{{ tabs .depth }}for _, e := range {{ .target }} {
+	arrayValTmpl = `{{ tabs .depth }}for {{ if .index }}{{ .i }}{{ else }}_{{ end }}, {{ .e }} := range {{ .target }} {
 {{ .validation }}
+{{ tabs .depth }}}`
+
+	hashValTmpl = `{{ tabs .depth }}for {{ if .elem }}{{ if .key }}{{ .k }}{{ else }}_{{ end }}, {{ .e }}{{ else }}{{ .k }}{{ end }} := range {{ .target }} {
+{{ .validation }}
+{{ tabs .depth }}}`
+
+	uniqueValTmpl = `{{ tabs .depth }}if len({{ .target }}) > 1 {
+{{ tabs .depth }}	seen := make(map[{{ .keyType }}]struct{}, len({{ .target }}))
+{{ tabs .depth }}	for _, e := range {{ .target }} {
+{{ tabs .depth }}		if e == nil{{ if .isPointer }} || {{ .field }} == nil{{ end }} {
+{{ tabs .depth }}			continue
+{{ tabs .depth }}		}
+{{ tabs .depth }}		if _, ok := seen[{{ .keyVal }}]; ok {
+{{ tabs .depth }}			err = goa.MergeErrors(err, goa.DuplicateItemError({{ literal .context }}, "{{ .key }}", {{ .keyVal }}))
+{{ tabs .depth }}		}
+{{ tabs .depth }}		seen[{{ .keyVal }}] = struct{}{}
+{{ tabs .depth }}	}
 {{ tabs .depth }}}`
 
 	userValTmpl = `{{ tabs .depth }}if err2 := {{ .target }}.Validate(); err2 != nil {
@@ -393,28 +791,45 @@ const (
 	enumValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
 {{ end }}{{ tabs $depth }}if !({{ oneof .targetVal .values }}) {
-{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ slice .values }}))
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidEnumValueError({{ literal .context }}, {{ .targetVal }}, {{ slice .values }}))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
 	patternValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
-*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil{{ if .allowEmpty }} && {{ .targetVal }} != ""{{ end }} {
 {{ end }}{{ tabs $depth }}if ok := goa.ValidatePattern(` + "`{{ .pattern }}`" + `, {{ .targetVal }}); !ok {
-{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, ` + "`{{ .pattern }}`" + `))
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidPatternError({{ literal .context }}, {{ .targetVal }}, ` + "`{{ .pattern }}`" + `))
 {{ tabs $depth }}}{{ if .isPointer }}
 {{ tabs .depth }}}{{ end }}`
 
-	formatValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+	charsetValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs $depth }}for pos, r := range {{ .targetVal }} {
+{{ tabs $depth }}	if !goa.InCharset({{ charset .charset }}, r) {
+{{ tabs $depth }}		err = goa.MergeErrors(err, goa.InvalidCharsetError({{ literal .context }}, {{ .targetVal }}, {{ charset .charset }}, r, pos))
+{{ tabs $depth }}		break
+{{ tabs $depth }}	}
+{{ tabs $depth }}}{{ if .isPointer }}
+{{ tabs .depth }}}{{ end }}`
+
+	formatValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil{{ if .allowEmpty }} && {{ .targetVal }} != ""{{ end }} {
 {{ end }}{{ tabs $depth }}if err2 := goa.ValidateFormat({{ constant .format }}, {{ .targetVal }}); err2 != nil {
-{{ tabs $depth }}		err = goa.MergeErrors(err, goa.InvalidFormatError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ constant .format }}, err2))
+{{ tabs $depth }}		err = goa.MergeErrors(err, goa.InvalidFormatError({{ literal .context }}, {{ .targetVal }}, {{ constant .format }}, err2))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
 	minMaxValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
 {{ end }}{{ tabs .depth }}	if {{ .targetVal }} {{ if .isMin }}<{{ else }}>{{ end }} {{ if .isMin }}{{ .min }}{{ else }}{{ .max }}{{ end }} {
-{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `{{ .context }}` + "`" + `, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidRangeError({{ literal .context }}, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
+{{ if .isPointer }}{{ tabs $depth }}}
+{{ end }}{{ tabs .depth }}}`
+
+	finiteValTmpl = `{{ $depth := or (and .isPointer (add .depth 1)) .depth }}{{/*
+*/}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
+{{ end }}{{ tabs .depth }}	if math.IsNaN({{ .targetVal }}){{ if .infSign }} || math.IsInf({{ .targetVal }}, {{ .infSign }}){{ end }} {
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidRangeError({{ literal .context }}, {{ .targetVal }}, {{ if .isMin }}{{ .min }}, true{{ else }}{{ .max }}, false{{ end }}))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
@@ -422,14 +837,28 @@ const (
 */}}{{ $target := or (and (or (or .array .hash) .nonzero) .target) .targetVal }}{{/*
 */}}{{ if .isPointer }}{{ tabs .depth }}if {{ .target }} != nil {
 {{ end }}{{ tabs .depth }}	if {{ if .string }}utf8.RuneCountInString({{ $target }}){{ else }}len({{ $target }}){{ end }} {{ if .isMinLength }}<{{ else }}>{{ end }} {{ if .isMinLength }}{{ .minLength }}{{ else }}{{ .maxLength }}{{ end }} {
-{{ tabs $depth }}	err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `{{ .context }}` + "`" + `, {{ $target }}, {{ if .string }}utf8.RuneCountInString({{ $target }}){{ else }}len({{ $target }}){{ end }}, {{ if .isMinLength }}{{ .minLength }}, true{{ else }}{{ .maxLength }}, false{{ end }}))
+{{ tabs $depth }}	err = goa.MergeErrors(err, goa.{{ if .array }}InvalidItemCountError{{ else }}InvalidLengthError{{ end }}({{ literal .context }}, {{ $target }}, {{ if .string }}utf8.RuneCountInString({{ $target }}){{ else }}len({{ $target }}){{ end }}, {{ if .isMinLength }}{{ .minLength }}, true{{ else }}{{ .maxLength }}, false{{ end }}))
 {{ if .isPointer }}{{ tabs $depth }}}
 {{ end }}{{ tabs .depth }}}`
 
 	requiredValTmpl = `{{ $att := index $.attribute.Type.ToObject .required }}{{/*
 */}}{{ if and (not $.private) (eq $att.Type.Kind 4) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == "" {
-{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{  .required  }}"))
+{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError({{ literal $.context }}, "{{  .required  }}"))
 {{ tabs $.depth }}}{{ else if or $.private (not $att.Type.IsPrimitive) }}{{ tabs $.depth }}if {{ $.target }}.{{ goifyAtt $att .required true }} == nil {
-{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{ $.context }}` + "`" + `, "{{ .required }}"))
-{{ tabs $.depth }}}{{ end }}`
+{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.MissingAttributeError({{ literal $.context }}, "{{ .required }}"))
+{{ tabs $.depth }}}{{ if or $att.Type.IsArray $att.Type.IsHash }} else if len({{ $.target }}.{{ goifyAtt $att .required true }}) == 0 {
+{{ tabs $.depth }}	err = goa.MergeErrors(err, goa.EmptyAttributeError({{ literal $.context }}, "{{ .required }}"))
+{{ tabs $.depth }}}{{ end }}{{ end }}`
+
+	requiredWhenValTmpl = `{{ tabs .depth }}if {{ .triggerIs }} && !({{ .nameSet }}) {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.MissingAttributeWhenError({{ literal .context }}, "{{ .name }}", "{{ .trigger }}", {{ printf "%#v" .triggerVal }}))
+{{ tabs .depth }}}`
+
+	equalLengthValTmpl = `{{ tabs .depth }}if {{ range .sets }}{{ . }} && {{ end }}len({{ .nameField }}) != len({{ .otherField }}) {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.LengthMismatchError({{ literal .context }}, "{{ .name }}", "{{ .other }}", len({{ .nameField }}), len({{ .otherField }})))
+{{ tabs .depth }}}`
+
+	forbiddenValTmpl = `{{ tabs .depth }}if {{ .nameSet }} && {{ .otherSet }} {
+{{ tabs .depth }}	err = goa.MergeErrors(err, goa.ForbiddenAttributeError({{ literal .context }}, "{{ .other }}", "{{ .name }}"))
+{{ tabs .depth }}}`
 )
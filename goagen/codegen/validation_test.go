@@ -45,6 +45,27 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of format on an optional string allowing empty values", func() {
+				BeforeEach(func() {
+					attType = design.String
+					validation = &dslengine.ValidationDefinition{
+						Format: "email",
+					}
+				})
+
+				It("skips the validation of empty values", func() {
+					att.Metadata = dslengine.MetadataDefinition{"validation:allow-empty": nil}
+					code = codegen.NewValidator().Code(att, false, false, false, target, context, 1, false)
+					Ω(code).Should(Equal(allowEmptyFormatValCode))
+				})
+
+				It("still validates required values", func() {
+					att.Metadata = dslengine.MetadataDefinition{"validation:allow-empty": nil}
+					code = codegen.NewValidator().Code(att, false, true, false, target, context, 1, false)
+					Ω(code).ShouldNot(ContainSubstring(`!= ""`))
+				})
+			})
+
 			Context("of pattern", func() {
 				BeforeEach(func() {
 					attType = design.String
@@ -58,6 +79,19 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of charset", func() {
+				BeforeEach(func() {
+					attType = design.String
+					validation = &dslengine.ValidationDefinition{
+						Charset: "alphanumeric",
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(charsetValCode))
+				})
+			})
+
 			Context("of min value 0", func() {
 				BeforeEach(func() {
 					attType = design.Integer
@@ -72,6 +106,30 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of number min value 0 and max value 1", func() {
+				BeforeEach(func() {
+					attType = design.Number
+					min, max := 0.0, 1.0
+					validation = &dslengine.ValidationDefinition{
+						Minimum: &min,
+						Maximum: &max,
+					}
+				})
+
+				It("rejects NaN values", func() {
+					Ω(code).Should(Equal(numberRangeValCode))
+				})
+
+				It("rejects infinite values beyond the only bound", func() {
+					att.Validation = &dslengine.ValidationDefinition{Minimum: validation.Minimum}
+					code = codegen.NewValidator().Code(att, false, false, false, target, context, 1, false)
+					Ω(code).Should(ContainSubstring("if math.IsNaN(*val) || math.IsInf(*val, 1) {"))
+					att.Validation = &dslengine.ValidationDefinition{Maximum: validation.Maximum}
+					code = codegen.NewValidator().Code(att, false, false, false, target, context, 1, false)
+					Ω(code).Should(ContainSubstring("if math.IsNaN(*val) || math.IsInf(*val, -1) {"))
+				})
+			})
+
 			Context("of array min length 1", func() {
 				BeforeEach(func() {
 					attType = &design.Array{
@@ -90,6 +148,321 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of array elements enum", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.String,
+							Validation: &dslengine.ValidationDefinition{
+								Values: []interface{}{"a", "b", "c"},
+							},
+						},
+					}
+					validation = nil
+				})
+
+				It("validates each element value", func() {
+					Ω(code).Should(Equal(arrayEnumValCode))
+				})
+
+				It("validates each element value of private data structures", func() {
+					code = codegen.NewValidator().Code(att, false, false, false, target, context, 1, true)
+					Ω(code).Should(Equal(arrayEnumValCode))
+				})
+			})
+
+			Context("of array of objects with required attributes", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.Object{
+								"name": &design.AttributeDefinition{Type: design.String},
+							},
+							Validation: &dslengine.ValidationDefinition{
+								Required: []string{"name"},
+							},
+						},
+					}
+					validation = nil
+				})
+
+				It("tags the errors of each element with its index", func() {
+					Ω(code).Should(Equal(arrayElemValCode))
+				})
+			})
+
+			Context("of array of objects unique by an attribute", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.Object{
+								"id": &design.AttributeDefinition{Type: design.Integer},
+							},
+						},
+					}
+					validation = &dslengine.ValidationDefinition{
+						UniqueBy: "id",
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(arrayUniqueByValCode))
+				})
+			})
+
+			Context("of map with user type keys and values", func() {
+				BeforeEach(func() {
+					newType := func(name string) *design.UserTypeDefinition {
+						return &design.UserTypeDefinition{
+							TypeName: name,
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"name": &design.AttributeDefinition{Type: design.String},
+								},
+								Validation: &dslengine.ValidationDefinition{
+									Required: []string{"name"},
+								},
+							},
+						}
+					}
+					attType = &design.Hash{
+						KeyType:  &design.AttributeDefinition{Type: newType("Key")},
+						ElemType: &design.AttributeDefinition{Type: newType("Value")},
+					}
+					validation = nil
+				})
+
+				It("validates both the keys and the values in one loop", func() {
+					Ω(code).Should(Equal(hashUserTypesValCode))
+				})
+			})
+
+			Context("of array of maps", func() {
+				BeforeEach(func() {
+					min := 1.0
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: &design.Hash{
+								KeyType: &design.AttributeDefinition{Type: design.String},
+								ElemType: &design.AttributeDefinition{
+									Type:       design.Integer,
+									Validation: &dslengine.ValidationDefinition{Minimum: &min},
+								},
+							},
+						},
+					}
+					validation = nil
+				})
+
+				It("uses distinct loop variables for the nested loops", func() {
+					Ω(code).Should(Equal(arrayOfHashesValCode))
+				})
+			})
+
+			Context("of map of arrays", func() {
+				BeforeEach(func() {
+					min := 1.0
+					attType = &design.Hash{
+						KeyType: &design.AttributeDefinition{Type: design.String},
+						ElemType: &design.AttributeDefinition{
+							Type: &design.Array{
+								ElemType: &design.AttributeDefinition{
+									Type:       design.Integer,
+									Validation: &dslengine.ValidationDefinition{Minimum: &min},
+								},
+							},
+						},
+					}
+					validation = nil
+				})
+
+				It("uses distinct loop variables for the nested loops", func() {
+					Ω(code).Should(Equal(hashOfArraysValCode))
+				})
+			})
+
+			Context("of array of arrays", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: &design.Array{
+								ElemType: &design.AttributeDefinition{
+									Type: design.String,
+									Validation: &dslengine.ValidationDefinition{
+										Values: []interface{}{"a"},
+									},
+								},
+							},
+						},
+					}
+					validation = nil
+				})
+
+				It("tags the errors with the indexes of both loops", func() {
+					Ω(code).Should(Equal(arrayOfArraysValCode))
+				})
+			})
+
+			Context("with JSON pointer contexts", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"items": &design.AttributeDefinition{
+							Type: &design.Array{
+								ElemType: &design.AttributeDefinition{
+									Type: design.Object{
+										"name": &design.AttributeDefinition{Type: design.String},
+									},
+									Validation: &dslengine.ValidationDefinition{
+										Required: []string{"name"},
+									},
+								},
+							},
+						},
+						"tags": &design.AttributeDefinition{
+							Type: &design.Hash{
+								KeyType: &design.AttributeDefinition{Type: design.String},
+								ElemType: &design.AttributeDefinition{
+									Type: design.String,
+									Validation: &dslengine.ValidationDefinition{
+										Values: []interface{}{"a", "b"},
+									},
+								},
+							},
+						},
+						"a/b": &design.AttributeDefinition{
+							Type: design.String,
+							Validation: &dslengine.ValidationDefinition{
+								Pattern: "^a",
+							},
+						},
+					}
+					validation = nil
+				})
+
+				It("builds the contexts of nested array and map values as JSON pointers", func() {
+					v := codegen.NewValidator()
+					v.JSONPointer = true
+					code = v.Code(att, false, false, false, target, "", 1, false)
+					Ω(code).Should(Equal(jsonPointerValCode))
+				})
+			})
+
+			Context("of required array and map attributes", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"ids": &design.AttributeDefinition{
+							Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+						},
+						"tags": &design.AttributeDefinition{
+							Type: &design.Hash{
+								KeyType:  &design.AttributeDefinition{Type: design.String},
+								ElemType: &design.AttributeDefinition{Type: design.String},
+							},
+						},
+					}
+					validation = &dslengine.ValidationDefinition{
+						Required: []string{"ids", "tags"},
+					}
+				})
+
+				It("rejects nil and empty values", func() {
+					Ω(code).Should(Equal(requiredNonEmptyValCode))
+				})
+			})
+
+			Context("of required read-only attributes", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"id": &design.AttributeDefinition{
+							Type:     design.String,
+							Metadata: dslengine.MetadataDefinition{"swagger:read-only": nil},
+						},
+						"name": &design.AttributeDefinition{Type: design.String},
+					}
+					validation = &dslengine.ValidationDefinition{
+						Required: []string{"id", "name"},
+					}
+				})
+
+				It("requires them in public data structures", func() {
+					Ω(code).Should(ContainSubstring(`"id"`))
+				})
+
+				It("does not require them in private data structures", func() {
+					code = codegen.NewValidator().Code(att, false, false, false, target, context, 1, true)
+					Ω(code).ShouldNot(ContainSubstring(`"id"`))
+					Ω(code).Should(ContainSubstring(`goa.MissingAttributeError(` + "`context`" + `, "name")`))
+				})
+			})
+
+			Context("of forbidden attribute combinations", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"coupon":   &design.AttributeDefinition{Type: design.String},
+						"discount": &design.AttributeDefinition{Type: design.Number},
+						"codes":    &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+					}
+					validation = &dslengine.ValidationDefinition{
+						ForbiddenWith: [][]string{{"coupon", "discount", "codes"}},
+					}
+				})
+
+				It("rejects values setting both attributes", func() {
+					Ω(code).Should(Equal(forbiddenWithValCode))
+				})
+			})
+
+			Context("of optional arrays of equal length", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"labels": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+						"values": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Number}}},
+					}
+					validation = &dslengine.ValidationDefinition{
+						EqualLength: [][]string{{"labels", "values"}},
+					}
+				})
+
+				It("compares the lengths when both arrays are set", func() {
+					Ω(code).Should(Equal(equalLengthValCode))
+				})
+			})
+
+			Context("of required arrays of equal length", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"labels": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}}},
+						"values": &design.AttributeDefinition{Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Number}}},
+					}
+					validation = &dslengine.ValidationDefinition{
+						Required:    []string{"labels", "values"},
+						EqualLength: [][]string{{"labels", "values"}},
+					}
+				})
+
+				It("always compares the lengths", func() {
+					Ω(code).Should(Equal(requiredEqualLengthValCode))
+				})
+			})
+
+			Context("of an attribute required when another has a given value", func() {
+				BeforeEach(func() {
+					attType = design.Object{
+						"deliveryMethod":  &design.AttributeDefinition{Type: design.String},
+						"shippingAddress": &design.AttributeDefinition{Type: design.String},
+					}
+					validation = &dslengine.ValidationDefinition{
+						RequiredWhen: []*dslengine.RequiredWhenDefinition{
+							{Name: "shippingAddress", Trigger: "deliveryMethod", Value: "ship"},
+						},
+					}
+				})
+
+				It("checks the attribute only when the trigger is set to the value", func() {
+					Ω(code).Should(Equal(requiredWhenValCode))
+				})
+			})
+
 			Context("of string min length 2", func() {
 				BeforeEach(func() {
 					attType = design.String
@@ -139,6 +512,38 @@ var _ = Describe("validation code generation", func() {
 						Ω(code).Should(Equal(embeddedRequiredValCode))
 					})
 				})
+				Context("and the optional parent requires the child", func() {
+					BeforeEach(func() {
+						catt.Type = design.Object{
+							"bar": ccatt,
+							"baz": &design.AttributeDefinition{Type: design.String},
+						}
+						catt.Validation = &dslengine.ValidationDefinition{
+							Required: []string{"baz"},
+						}
+						validation = nil
+					})
+					It("checks the child is present only when the parent is", func() {
+						Ω(code).Should(Equal(embeddedOptionalParentValCode))
+					})
+				})
+				Context("and the required child has a default value", func() {
+					BeforeEach(func() {
+						bazatt := &design.AttributeDefinition{Type: design.String}
+						bazatt.SetDefault("")
+						catt.Type = design.Object{"bar": ccatt, "baz": bazatt}
+						catt.Validation = &dslengine.ValidationDefinition{
+							Required: []string{"baz"},
+						}
+						validation = &dslengine.ValidationDefinition{
+							Required: []string{"foo"},
+						}
+					})
+					It("does not check the child is present", func() {
+						Ω(code).ShouldNot(ContainSubstring(`"baz"`))
+						Ω(code).Should(ContainSubstring(`goa.MissingAttributeError(` + "`context`" + `, "foo")`))
+					})
+				})
 				Context("with a child attribute with struct:tag:name metadata", func() {
 					const fieldTag = "FOO"
 
@@ -259,9 +664,31 @@ const (
 		}
 	}`
 
+	numberRangeValCode = `	if val != nil {
+		if math.IsNaN(*val) {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, *val, 0, true))
+		}
+	}
+	if val != nil {
+		if *val < 0 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, *val, 0, true))
+		}
+	}
+	if val != nil {
+		if *val > 1 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, *val, 1, false))
+		}
+	}`
+
 	arrayMinLengthValCode = `	if val != nil {
 		if len(val) < 1 {
-			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `context` + "`" + `, val, len(val), 1, true))
+			err = goa.MergeErrors(err, goa.InvalidItemCountError(` + "`" + `context` + "`" + `, val, len(val), 1, true))
+		}
+	}`
+
+	arrayElemValCode = `	for i, e := range val {
+		if e.Name == "" {
+			err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context[` + fmt.Sprint(i) + `]`" + `, "name"))
 		}
 	}`
 
@@ -308,6 +735,8 @@ const (
 
 	utCode = `	if val.Foo == nil {
 		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context`" + `, "foo"))
+	} else if len(val.Foo) == 0 {
+		err = goa.MergeErrors(err, goa.EmptyAttributeError(` + "`context`" + `, "foo"))
 	}`
 
 	utRequiredCode = `	for _, e := range val.Foo {
@@ -317,4 +746,142 @@ const (
 			}
 		}
 	}`
+
+	allowEmptyFormatValCode = `	if val != nil && *val != "" {
+		if err2 := goa.ValidateFormat(goa.FormatEmail, *val); err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidFormatError(` + "`context`" + `, *val, goa.FormatEmail, err2))
+		}
+	}`
+
+	arrayUniqueByValCode = `	if len(val) > 1 {
+		seen := make(map[int]struct{}, len(val))
+		for _, e := range val {
+			if e == nil || e.ID == nil {
+				continue
+			}
+			if _, ok := seen[*e.ID]; ok {
+				err = goa.MergeErrors(err, goa.DuplicateItemError(` + "`context`" + `, "id", *e.ID))
+			}
+			seen[*e.ID] = struct{}{}
+		}
+	}`
+
+	requiredNonEmptyValCode = `	if val.Ids == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context`" + `, "ids"))
+	} else if len(val.Ids) == 0 {
+		err = goa.MergeErrors(err, goa.EmptyAttributeError(` + "`context`" + `, "ids"))
+	}
+	if val.Tags == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context`" + `, "tags"))
+	} else if len(val.Tags) == 0 {
+		err = goa.MergeErrors(err, goa.EmptyAttributeError(` + "`context`" + `, "tags"))
+	}`
+
+	charsetValCode = `	if val != nil {
+		for pos, r := range *val {
+			if !goa.InCharset(goa.CharsetAlphanumeric, r) {
+				err = goa.MergeErrors(err, goa.InvalidCharsetError(` + "`context`" + `, *val, goa.CharsetAlphanumeric, r, pos))
+				break
+			}
+		}
+	}`
+
+	arrayEnumValCode = `	for i, e := range val {
+		if !(e == "a" || e == "b" || e == "c") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `context[` + "`" + ` + fmt.Sprint(i) + ` + "`" + `]` + "`" + `, e, []interface{}{"a", "b", "c"}))
+		}
+	}`
+
+	arrayOfHashesValCode = `	for i, e := range val {
+		for k2, e2 := range e {
+				if e2 < 1 {
+				err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context[` + "`" + ` + fmt.Sprint(i) + ` + "`" + `][` + "`" + ` + fmt.Sprint(k2) + ` + "`" + `]` + "`" + `, e2, 1, true))
+			}
+		}
+	}`
+
+	hashOfArraysValCode = `	for k, e := range val {
+	for i2, e2 := range e {
+				if e2 < 1 {
+				err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context[` + "`" + ` + fmt.Sprint(k) + ` + "`" + `][` + "`" + ` + fmt.Sprint(i2) + ` + "`" + `]` + "`" + `, e2, 1, true))
+			}
+	}
+	}`
+
+	arrayOfArraysValCode = `	for i, e := range val {
+	for i2, e2 := range e {
+			if !(e2 == "a") {
+				err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `context[` + "`" + ` + fmt.Sprint(i) + ` + "`" + `][` + "`" + ` + fmt.Sprint(i2) + ` + "`" + `]` + "`" + `, e2, []interface{}{"a"}))
+			}
+	}
+	}`
+
+	hashUserTypesValCode = `	for k, e := range val {
+		if k != nil {
+			if err2 := k.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+		if e != nil {
+			if err2 := e.Validate(); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}`
+
+	embeddedOptionalParentValCode = `	if val.Foo != nil {
+		if val.Foo.Baz == "" {
+			err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context.foo`" + `, "baz"))
+		}
+		if val.Foo.Bar != nil {
+			if !(*val.Foo.Bar == 1 || *val.Foo.Bar == 2 || *val.Foo.Bar == 3) {
+				err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`context.foo.bar`" + `, *val.Foo.Bar, []interface{}{1, 2, 3}))
+			}
+		}
+	}`
+
+	forbiddenWithValCode = `	if val.Coupon != nil && val.Discount != nil {
+		err = goa.MergeErrors(err, goa.ForbiddenAttributeError(` + "`context`" + `, "discount", "coupon"))
+	}
+	if val.Coupon != nil && val.Codes != nil {
+		err = goa.MergeErrors(err, goa.ForbiddenAttributeError(` + "`context`" + `, "codes", "coupon"))
+	}`
+
+	requiredWhenValCode = `	if val.DeliveryMethod != nil && *val.DeliveryMethod == "ship" && !(val.ShippingAddress != nil) {
+		err = goa.MergeErrors(err, goa.MissingAttributeWhenError(` + "`context`" + `, "shippingAddress", "deliveryMethod", "ship"))
+	}`
+
+	equalLengthValCode = `	if val.Labels != nil && val.Values != nil && len(val.Labels) != len(val.Values) {
+		err = goa.MergeErrors(err, goa.LengthMismatchError(` + "`" + `context` + "`" + `, "labels", "values", len(val.Labels), len(val.Values)))
+	}`
+
+	requiredEqualLengthValCode = `	if val.Labels == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `context` + "`" + `, "labels"))
+	} else if len(val.Labels) == 0 {
+		err = goa.MergeErrors(err, goa.EmptyAttributeError(` + "`" + `context` + "`" + `, "labels"))
+	}
+	if val.Values == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `context` + "`" + `, "values"))
+	} else if len(val.Values) == 0 {
+		err = goa.MergeErrors(err, goa.EmptyAttributeError(` + "`" + `context` + "`" + `, "values"))
+	}
+	if len(val.Labels) != len(val.Values) {
+		err = goa.MergeErrors(err, goa.LengthMismatchError(` + "`" + `context` + "`" + `, "labels", "values", len(val.Labels), len(val.Values)))
+	}`
+
+	jsonPointerValCode = `	if val.AB != nil {
+		if ok := goa.ValidatePattern(` + "`" + `^a` + "`" + `, *val.AB); !ok {
+			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `/a~1b` + "`" + `, *val.AB, ` + "`" + `^a` + "`" + `))
+		}
+	}
+	for i, e := range val.Items {
+		if e.Name == "" {
+			err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `/items/` + "`" + ` + fmt.Sprint(i), "name"))
+		}
+	}
+	for k, e := range val.Tags {
+		if !(e == "a" || e == "b") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `/tags/` + "`" + ` + goa.EscapeJSONPointer(fmt.Sprint(k)), e, []interface{}{"a", "b"}))
+		}
+	}`
 )
@@ -63,6 +63,7 @@ var (
 		"gotypedef":           GoTypeDef,
 		"gotypename":          GoTypeName,
 		"gotypedesc":          GoTypeDesc,
+		"gotypefields":        GoTypeFields,
 		"gotyperef":           GoTypeRef,
 		"join":                strings.Join,
 		"recursivePublicizer": RecursivePublicizer,
@@ -1,5 +1,13 @@
 /*
 Package genapp provides the generator for the handlers, context data structures and tests of a goa
 application. It generates the glue between user code and the low level router.
+
+When invoked with --fuzz the generator also emits a Go 1.18 fuzz target per action in
+decoders_fuzz_test.go. Each target builds a request from the fuzzed body and query string and
+runs the action context and payload decoders on it, run them with "go test -fuzz".
+
+When invoked with --json-pointer the validation errors of the request payloads, media types and
+user types identify the invalid fields with JSON pointers such as "/items/0/name" rather than
+dotted paths such as "raw.items[0].name".
 */
 package genapp
@@ -0,0 +1,88 @@
+package genapp
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// generateFuzzTests generates one Go fuzz target per action that runs the action context and
+// payload decoders against arbitrary request bodies and query strings. The targets rely on the
+// native fuzzing support introduced in Go 1.18 and are excluded from builds using older versions.
+func (g *Generator) generateFuzzTests() error {
+	var actions []map[string]interface{}
+	g.API.IterateResources(func(r *design.ResourceDefinition) error {
+		return r.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.Routes) == 0 {
+				return nil
+			}
+			name := codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true)
+			action := map[string]interface{}{
+				"Name":       name,
+				"DesignName": a.Name,
+				"Resource":   r.Name,
+				"Verb":       a.Routes[0].Verb,
+				"Context":    name + "Context",
+			}
+			if a.Payload != nil {
+				action["Unmarshal"] = "unmarshal" + name + "Payload"
+			}
+			actions = append(actions, action)
+			return nil
+		})
+	})
+	if len(actions) == 0 {
+		return nil
+	}
+
+	filename := filepath.Join(g.OutDir, "decoders_fuzz_test.go")
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write([]byte("//go:build go1.18\n// +build go1.18\n\n")); err != nil {
+		return err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("context"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/http/httptest"),
+		codegen.SimpleImport("testing"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	title := fmt.Sprintf("%s: Decoder Fuzz Tests", g.API.Context())
+	if err := file.WriteHeader(title, g.Target, imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	if err := file.ExecuteTemplate("fuzz", fuzzT, nil, actions); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
+const fuzzT = `{{ range . }}
+// FuzzDecode{{ .Name }} feeds arbitrary request bodies and query strings to the
+// decoders of the {{ .Resource }} {{ .DesignName }} action and fails if they panic.
+// Path parameters are read from the query string.
+func FuzzDecode{{ .Name }}(f *testing.F) {
+	f.Add([]byte("{}"), "")
+	service := goa.New("fuzz")
+	initService(service)
+	f.Fuzz(func(t *testing.T, body []byte, query string) {
+		req, err := http.NewRequest({{ printf "%q" .Verb }}, "/", bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.URL.RawQuery = query
+		ctx := goa.NewContext(context.Background(), httptest.NewRecorder(), req, req.URL.Query())
+{{ if .Unmarshal }}		if err := {{ .Unmarshal }}(ctx, service, req); err != nil {
+			return
+		}
+{{ end }}		New{{ .Context }}(ctx, req, service)
+	})
+}
+{{ end }}`
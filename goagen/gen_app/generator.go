@@ -26,19 +26,22 @@ func NewGenerator(options ...Option) *Generator {
 
 // Generator is the application code generator.
 type Generator struct {
-	API       *design.APIDefinition // The API definition
-	OutDir    string                // Path to output directory
-	Target    string                // Name of generated package
-	NoTest    bool                  // Whether to skip test generation
-	genfiles  []string              // Generated files
-	validator *codegen.Validator    // Validation code generator
+	API         *design.APIDefinition // The API definition
+	OutDir      string                // Path to output directory
+	Target      string                // Name of generated package
+	NoTest      bool                  // Whether to skip test generation
+	Fuzz        bool                  // Whether to generate decoder fuzz tests
+	JSONPointer bool                  // Whether to build the validation error contexts as JSON pointers
+	genfiles    []string              // Generated files
+	validator   *codegen.Validator    // Validation code generator
 }
 
 // Generate is the generator entry point called by the meta generator.
 func Generate() (files []string, err error) {
 	var (
 		outDir, target, ver string
-		notest              bool
+		notest, fuzz        bool
+		jsonPointer         bool
 	)
 
 	set := flag.NewFlagSet("app", flag.PanicOnError)
@@ -47,6 +50,8 @@ func Generate() (files []string, err error) {
 	set.StringVar(&target, "pkg", "app", "")
 	set.StringVar(&ver, "version", "", "")
 	set.BoolVar(&notest, "notest", false, "")
+	set.BoolVar(&fuzz, "fuzz", false, "")
+	set.BoolVar(&jsonPointer, "json-pointer", false, "")
 	set.Bool("force", false, "")
 	set.Parse(os.Args[1:])
 	outDir = filepath.Join(outDir, target)
@@ -56,7 +61,7 @@ func Generate() (files []string, err error) {
 	}
 
 	target = codegen.Goify(target, false)
-	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, API: design.Design, validator: codegen.NewValidator()}
+	g := &Generator{OutDir: outDir, Target: target, NoTest: notest, Fuzz: fuzz, JSONPointer: jsonPointer, API: design.Design, validator: codegen.NewValidator()}
 
 	return g.Generate()
 }
@@ -89,6 +94,11 @@ func (g *Generator) Generate() (_ []string, err error) {
 	if err := g.generateControllers(); err != nil {
 		return nil, err
 	}
+	if g.Fuzz {
+		if err := g.generateFuzzTests(); err != nil {
+			return nil, err
+		}
+	}
 	if err := g.generateSecurity(); err != nil {
 		return nil, err
 	}
@@ -127,9 +137,13 @@ func (g *Generator) generateContexts() error {
 	if err != nil {
 		panic(err) // bug
 	}
+	ctxWr.Validator.JSONPointer = g.JSONPointer
 	title := fmt.Sprintf("%s: Application Contexts", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("math"),
+		codegen.SimpleImport("math/big"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
@@ -191,6 +205,12 @@ func (g *Generator) generateContexts() error {
 				DefaultPkg:   g.Target,
 				Security:     a.Security,
 			}
+			if v, ok := a.Metadata["idempotency:key"]; ok {
+				ctxData.IdempotencyKey = "optional"
+				if len(v) > 0 && v[0] == "required" {
+					ctxData.IdempotencyKey = "required"
+				}
+			}
 			return ctxWr.Execute(&ctxData)
 		})
 	})
@@ -208,14 +228,18 @@ func (g *Generator) generateControllers() error {
 	if err != nil {
 		panic(err) // bug
 	}
+	ctlWr.Validator.JSONPointer = g.JSONPointer
 	title := fmt.Sprintf("%s: Application Controllers", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("io/ioutil"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/cors"),
 		codegen.SimpleImport("regexp"),
+		codegen.SimpleImport("strings"),
 	}
 	encoders, err := BuildEncoders(g.API.Produces, true)
 	if err != nil {
@@ -276,12 +300,16 @@ func (g *Generator) generateControllers() error {
 			action := map[string]interface{}{
 				"Name":            codegen.Goify(a.Name, true),
 				"DesignName":      a.Name,
+				"Endpoint":        r.Name + "." + a.Name,
 				"Routes":          a.Routes,
 				"Context":         context,
 				"Unmarshal":       unmarshal,
 				"Payload":         a.Payload,
 				"PayloadOptional": a.PayloadOptional,
+				"MaxBodySize":     a.MaxBodySize,
 				"Security":        a.Security,
+				"Deprecated":      a.IsDeprecated(),
+				"AggregateErrors": aggregateErrors(a),
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
@@ -384,10 +412,12 @@ func (g *Generator) generateMediaTypes() error {
 	if err != nil {
 		panic(err) // bug
 	}
+	mtWr.Validator.JSONPointer = g.JSONPointer
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("math"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
@@ -420,9 +450,13 @@ func (g *Generator) generateUserTypes() error {
 	if err != nil {
 		panic(err) // bug
 	}
+	utWr.Validator.JSONPointer = g.JSONPointer
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("math"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
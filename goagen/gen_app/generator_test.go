@@ -190,7 +190,7 @@ var _ = Describe("Generate", func() {
 
 			It("generates the corresponding code", func() {
 				Ω(genErr).Should(BeNil())
-				Ω(files).Should(HaveLen(8))
+				Ω(files).Should(HaveLen(9))
 
 				isSource("contexts.go", contextsCode)
 				isSource("controllers.go", controllersCode)
@@ -199,6 +199,52 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with fuzz tests enabled", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--fuzz")
+			})
+
+			It("generates a fuzz target per action", func() {
+				Ω(genErr).Should(BeNil())
+				Ω(files).Should(HaveLen(10))
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "decoders_fuzz_test.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(HavePrefix("//go:build go1.18\n// +build go1.18\n"))
+				Ω(string(content)).Should(ContainSubstring(fuzzCode))
+			})
+		})
+
+		Context("with JSON pointers enabled", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--json-pointer")
+				minLength := 2
+				payload = &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{
+							"items": &design.AttributeDefinition{
+								Type: &design.Array{ElemType: &design.AttributeDefinition{
+									Type:       design.String,
+									Validation: &dslengine.ValidationDefinition{MinLength: &minLength},
+								}},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"items"}},
+					},
+					TypeName: "Items",
+				}
+				design.Design.Resources["Widget"].Actions["get"].Payload = payload
+			})
+
+			It("builds the validation error contexts as JSON pointers", func() {
+				Ω(genErr).Should(BeNil())
+
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "contexts.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(jsonPointerValidationCode))
+			})
+		})
+
 		Context("with a slice payload", func() {
 			BeforeEach(func() {
 				elemType := &design.AttributeDefinition{Type: design.Integer}
@@ -245,22 +291,114 @@ var _ = Describe("Generate", func() {
 		})
 
 	})
+
+	Context("with a design defining many attributes", func() {
+		readFiles := func() map[string]string {
+			files := make(map[string]string)
+			err := filepath.Walk(filepath.Join(outDir, "app"), func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				b, err := ioutil.ReadFile(path)
+				files[path] = string(b)
+				return err
+			})
+			Ω(err).ShouldNot(HaveOccurred())
+			return files
+		}
+
+		BeforeEach(func() {
+			names := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel"}
+			attributes := func() design.Object {
+				o := design.Object{}
+				for _, n := range names {
+					o[n] = &design.AttributeDefinition{Type: design.String}
+				}
+				return o
+			}
+			headers := design.Object{}
+			for _, n := range names {
+				headers["X-"+n] = &design.AttributeDefinition{Type: design.Integer}
+			}
+			ut := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{Type: attributes()},
+				TypeName:            "Thing",
+			}
+			mtAtt := &design.AttributeDefinition{Type: attributes()}
+			mt := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{AttributeDefinition: mtAtt, TypeName: "ThingMedia"},
+				Identifier:         "application/vnd.thing",
+				Views: map[string]*design.ViewDefinition{
+					"default": {AttributeDefinition: mtAtt, Name: "default"},
+				},
+			}
+			mt.Views["default"].Parent = mt
+			params := &design.AttributeDefinition{
+				Type:       attributes(),
+				Validation: &dslengine.ValidationDefinition{Required: []string{"charlie", "alpha"}},
+			}
+			res := &design.ResourceDefinition{Name: "thing"}
+			act := &design.ActionDefinition{
+				Name:        "create",
+				Parent:      res,
+				Routes:      []*design.RouteDefinition{{Verb: "POST", Path: "/things"}},
+				Params:      params,
+				QueryParams: params,
+				Headers:     &design.AttributeDefinition{Type: headers},
+				Payload:     ut,
+				Responses: map[string]*design.ResponseDefinition{
+					"OK":                  {Name: "OK", Status: 200, MediaType: mt.Identifier},
+					"Created":             {Name: "Created", Status: 201, MediaType: mt.Identifier},
+					"NotFound":            {Name: "NotFound", Status: 404},
+					"InternalServerError": {Name: "InternalServerError", Status: 500},
+				},
+			}
+			act.Routes[0].Parent = act
+			res.Actions = map[string]*design.ActionDefinition{"create": act}
+			design.Design = &design.APIDefinition{
+				Name:       "test api",
+				Resources:  map[string]*design.ResourceDefinition{"thing": res},
+				Types:      map[string]*design.UserTypeDefinition{"Thing": ut},
+				MediaTypes: map[string]*design.MediaTypeDefinition{mt.Identifier: mt},
+			}
+			codegen.TempCount = 0
+		})
+
+		It("generates the same code on repeated runs", func() {
+			Ω(genErr).Should(BeNil())
+			expected := readFiles()
+			Ω(expected).Should(HaveKey(filepath.Join(outDir, "app", "test", "thing_testing.go")))
+			for i := 0; i < 5; i++ {
+				delete(codegen.Reserved, "app")
+				design.GeneratedMediaTypes = make(design.MediaTypeRoot)
+				design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+				codegen.TempCount = 0
+				_, err := genapp.Generate()
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(readFiles()).Should(Equal(expected))
+			}
+		})
+	})
 })
 
 var _ = Describe("NewGenerator", func() {
 	var generator *genapp.Generator
 
 	var args = struct {
-		api    *design.APIDefinition
-		outDir string
-		target string
-		noTest bool
+		api         *design.APIDefinition
+		outDir      string
+		target      string
+		noTest      bool
+		fuzz        bool
+		jsonPointer bool
 	}{
 		api: &design.APIDefinition{
 			Name: "test api",
 		},
-		target: "app",
-		noTest: true,
+		target:      "app",
+		noTest:      true,
+		fuzz:        true,
+		jsonPointer: true,
 	}
 
 	Context("with options all options set", func() {
@@ -271,6 +409,8 @@ var _ = Describe("NewGenerator", func() {
 				genapp.OutDir(args.outDir),
 				genapp.Target(args.target),
 				genapp.NoTest(args.noTest),
+				genapp.Fuzz(args.fuzz),
+				genapp.JSONPointer(args.jsonPointer),
 			)
 		})
 
@@ -280,6 +420,8 @@ var _ = Describe("NewGenerator", func() {
 			Ω(generator.OutDir).Should(Equal(args.outDir))
 			Ω(generator.Target).Should(Equal(args.target))
 			Ω(generator.NoTest).Should(Equal(args.noTest))
+			Ω(generator.Fuzz).Should(Equal(args.fuzz))
+			Ω(generator.JSONPointer).Should(Equal(args.jsonPointer))
 		})
 
 	})
@@ -367,9 +509,17 @@ type WidgetController interface {
 }
 
 // MountWidgetController "mounts" a Widget resource controller on the given service.
-func MountWidgetController(service *goa.Service, ctrl WidgetController) {
+// The optional prefix is prepended to the path of each route so that the same controller may be
+// mounted multiple times under different paths. Wildcards in the prefix are matched but their
+// values are not loaded into the action contexts. The prefix is not reflected in the Swagger
+// specification basePath. Actions rejected by the service MountFilter are not mounted.
+func MountWidgetController(service *goa.Service, ctrl WidgetController, prefix ...string) {
 	initService(service)
 	var h goa.Handler
+	var p string
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -383,8 +533,28 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
-	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, nil))
-	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
+	if service.Mounts("Widget.get") {
+		service.Mux.Handle("GET", p+"/:id", ctrl.MuxHandler("get", h, nil))
+		service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET "+p+"/:id")
+	}
+}
+
+// MapServiceError returns the HTTP status code and body of the response corresponding to err.
+// Errors created with goa error classes keep their status code if it is below 500, if it is 500 or
+// if it is one of the server error status codes declared in the design, other errors produce
+// internal errors. MapServiceError can be used with the middleware.ErrorMappingHandler middleware.
+func MapServiceError(err error) (int, interface{}) {
+	if e, ok := err.(goa.ServiceError); ok {
+		status := e.ResponseStatus()
+		if status < http.StatusInternalServerError {
+			return status, e
+		}
+		switch status {
+		case 500:
+			return status, e
+		}
+	}
+	return http.StatusInternalServerError, goa.ErrInternal(err)
 }
 `
 
@@ -427,9 +597,17 @@ package app
 
 const controllersSlicePayloadCode = `
 // MountWidgetController "mounts" a Widget resource controller on the given service.
-func MountWidgetController(service *goa.Service, ctrl WidgetController) {
+// The optional prefix is prepended to the path of each route so that the same controller may be
+// mounted multiple times under different paths. Wildcards in the prefix are matched but their
+// values are not loaded into the action contexts. The prefix is not reflected in the Swagger
+// specification basePath. Actions rejected by the service MountFilter are not mounted.
+func MountWidgetController(service *goa.Service, ctrl WidgetController, prefix ...string) {
 	initService(service)
 	var h goa.Handler
+	var p string
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -449,8 +627,10 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
-	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, unmarshalGetWidgetPayload))
-	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
+	if service.Mounts("Widget.get") {
+		service.Mux.Handle("GET", p+"/:id", ctrl.MuxHandler("get", h, unmarshalGetWidgetPayload))
+		service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET "+p+"/:id")
+	}
 }
 
 // unmarshalGetWidgetPayload unmarshals the request body into the context request data Payload field.
@@ -459,6 +639,10 @@ func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *h
 	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
 	}
+	if payload == nil {
+		// A null body is handled as a missing payload
+		return nil
+	}
 	goa.ContextRequest(ctx).Payload = payload
 	return nil
 }
@@ -466,9 +650,17 @@ func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *h
 
 const controllersOptionalPayloadCode = `
 // MountWidgetController "mounts" a Widget resource controller on the given service.
-func MountWidgetController(service *goa.Service, ctrl WidgetController) {
+// The optional prefix is prepended to the path of each route so that the same controller may be
+// mounted multiple times under different paths. Wildcards in the prefix are matched but their
+// values are not loaded into the action contexts. The prefix is not reflected in the Swagger
+// specification basePath. Actions rejected by the service MountFilter are not mounted.
+func MountWidgetController(service *goa.Service, ctrl WidgetController, prefix ...string) {
 	initService(service)
 	var h goa.Handler
+	var p string
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -486,8 +678,10 @@ func MountWidgetController(service *goa.Service, ctrl WidgetController) {
 		}
 		return ctrl.Get(rctx)
 	}
-	service.Mux.Handle("GET", "/:id", ctrl.MuxHandler("get", h, unmarshalGetWidgetPayload))
-	service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET /:id")
+	if service.Mounts("Widget.get") {
+		service.Mux.Handle("GET", p+"/:id", ctrl.MuxHandler("get", h, unmarshalGetWidgetPayload))
+		service.LogInfo("mount", "ctrl", "Widget", "action", "Get", "route", "GET "+p+"/:id")
+	}
 }
 
 // unmarshalGetWidgetPayload unmarshals the request body into the context request data Payload field.
@@ -496,7 +690,44 @@ func unmarshalGetWidgetPayload(ctx context.Context, service *goa.Service, req *h
 	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
 	}
+	if payload == nil {
+		// A null body is handled as a missing payload
+		return nil
+	}
 	goa.ContextRequest(ctx).Payload = payload
 	return nil
 }
 `
+
+const fuzzCode = `// FuzzDecodeGetWidget feeds arbitrary request bodies and query strings to the
+// decoders of the Widget get action and fails if they panic.
+// Path parameters are read from the query string.
+func FuzzDecodeGetWidget(f *testing.F) {
+	f.Add([]byte("{}"), "")
+	service := goa.New("fuzz")
+	initService(service)
+	f.Fuzz(func(t *testing.T, body []byte, query string) {
+		req, err := http.NewRequest("GET", "/", bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		req.URL.RawQuery = query
+		ctx := goa.NewContext(context.Background(), httptest.NewRecorder(), req, req.URL.Query())
+		NewGetWidgetContext(ctx, req, service)
+	})
+}
+`
+
+const jsonPointerValidationCode = `func (payload *Items) Validate() (err error) {
+	if payload.Items == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `` + "`" + `, "items"))
+	} else if len(payload.Items) == 0 {
+		err = goa.MergeErrors(err, goa.EmptyAttributeError(` + "`" + `` + "`" + `, "items"))
+	}
+	for i, e := range payload.Items {
+		if utf8.RuneCountInString(e) < 2 {
+			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `/items/` + "`" + `+fmt.Sprint(i), e, utf8.RuneCountInString(e), 2, true))
+		}
+	}
+	return
+}`
@@ -32,3 +32,17 @@ func NoTest(noTest bool) Option {
 		g.NoTest = noTest
 	}
 }
+
+//Fuzz Whether to generate decoder fuzz tests
+func Fuzz(fuzz bool) Option {
+	return func(g *Generator) {
+		g.Fuzz = fuzz
+	}
+}
+
+//JSONPointer Whether to build the validation error contexts as JSON pointers
+func JSONPointer(jsonPointer bool) Option {
+	return func(g *Generator) {
+		g.JSONPointer = jsonPointer
+	}
+}
@@ -56,6 +56,17 @@ func (t *TestMethod) Escape(s string) string {
 	return s
 }
 
+// HasBasicAuth returns true if the value of one of the query params is read from the request basic
+// auth credentials.
+func (t *TestMethod) HasBasicAuth() bool {
+	for _, p := range t.QueryParams {
+		if p.BasicAuth != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // ObjectType structure
 type ObjectType struct {
 	Label       string
@@ -63,6 +74,8 @@ type ObjectType struct {
 	Type        string
 	Pointer     string
 	Validatable bool
+	BoolValues  []string
+	BasicAuth   string
 }
 
 func (g *Generator) generateResourceTest() error {
@@ -99,6 +112,10 @@ func (g *Generator) generateResourceTest() error {
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
 
+	if err := g.generateTestServer(outDir, appPkg); err != nil {
+		return err
+	}
+
 	return g.API.IterateResources(func(res *design.ResourceDefinition) error {
 		filename := filepath.Join(outDir, codegen.SnakeCase(res.Name)+"_testing.go")
 		file, err := codegen.SourceFileFor(filename)
@@ -147,6 +164,60 @@ func (g *Generator) generateResourceTest() error {
 	})
 }
 
+// generateTestServer generates the NewTestServer function which mounts the API controllers on a
+// service and returns the corresponding HTTP handler. It also generates one controller mock per
+// resource.
+func (g *Generator) generateTestServer(outDir, appPkg string) error {
+	filename := filepath.Join(outDir, "test_server.go")
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport(appPkg),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	title := fmt.Sprintf("%s: Test Server", g.API.Context())
+	if err := file.WriteHeader(title, "test", imports); err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, filename)
+	var ctrls []string
+	var mocks []map[string]interface{}
+	g.API.IterateResources(func(res *design.ResourceDefinition) error {
+		if len(res.Actions) == 0 && len(res.FileServers) == 0 {
+			return nil
+		}
+		name := codegen.Goify(res.Name, true)
+		ctrls = append(ctrls, name)
+		var actions []map[string]string
+		res.IterateActions(func(a *design.ActionDefinition) error {
+			actions = append(actions, map[string]string{
+				"Name":    codegen.Goify(a.Name, true),
+				"Context": codegen.Goify(a.Name, true) + name + "Context",
+			})
+			return nil
+		})
+		mocks = append(mocks, map[string]interface{}{
+			"Name":     name,
+			"Resource": res.Name,
+			"Actions":  actions,
+		})
+		return nil
+	})
+	data := map[string]interface{}{
+		"API":         g.API,
+		"Target":      g.Target,
+		"Controllers": ctrls,
+		"Mocks":       mocks,
+	}
+	if err := file.ExecuteTemplate("server", testServerT, nil, data); err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
 func (g *Generator) createTestMethod(resource *design.ResourceDefinition, action *design.ActionDefinition,
 	response *design.ResponseDefinition, route *design.RouteDefinition, routeIndex int,
 	mediaType *design.MediaTypeDefinition, view *design.ViewDefinition) *TestMethod {
@@ -284,7 +355,13 @@ func queryParams(action *design.ActionDefinition) []*ObjectType {
 		}
 	}
 	sort.Strings(qparams)
-	return paramFromNames(action, qparams)
+	params := paramFromNames(action, qparams)
+	for _, p := range params {
+		if n := queryName(action.Params.Type.ToObject()[p.Label]); n != "" {
+			p.Label = n
+		}
+	}
+	return params
 }
 
 func paramFromNames(action *design.ActionDefinition, names []string) (params []*ObjectType) {
@@ -323,6 +400,8 @@ func attToObject(name string, parent, att *design.AttributeDefinition) *ObjectTy
 	if att.Type.IsPrimitive() && parent.IsPrimitivePointer(name) {
 		obj.Pointer = "*"
 	}
+	obj.BoolValues = boolValues(att)
+	obj.BasicAuth = basicAuth(att)
 	return obj
 }
 
@@ -348,9 +427,57 @@ var convertParamTmpl = `{{ if eq .Type "string" }}		sliceVal := []string{ {{ if
 		for i, v := range {{ .Name }} {
 			sliceVal[i] = fmt.Sprintf("%v", v)
 		}{{/*
+*/}}{{ else if .BoolValues }}		sliceVal := []string{ {{ printf "%q" (index .BoolValues 1) }} }
+		if {{ if .Pointer }}*{{ end }}{{ .Name }} {
+			sliceVal[0] = {{ printf "%q" (index .BoolValues 0) }}
+		}{{/*
 */}}{{ else if eq .Type "time.Time" }}		sliceVal := []string{ {{ if .Pointer }}(*{{ end }}{{ .Name }}{{ if .Pointer }}){{ end }}.Format(time.RFC3339)}{{/*
 */}}{{ else }}		sliceVal := []string{fmt.Sprintf("%v", {{ if .Pointer }}*{{ end }}{{ .Name }})}{{ end }}`
 
+// testServerT generates the NewTestServer function.
+// template input: map[string]interface{}
+const testServerT = `// TestControllers lists the controllers mounted by NewTestServer.
+// Controllers left nil are not mounted.
+type TestControllers struct {
+{{ range .Controllers }}	{{ . }} {{ $.Target }}.{{ . }}Controller
+{{ end }}}
+
+// NewTestServer mounts the given controllers on service and returns the HTTP handler that serves
+// their requests. The handler can be given to httptest.NewServer to run end-to-end tests.
+// If service is nil then a default service is created.
+func NewTestServer(service *goa.Service, ctrls TestControllers) http.Handler {
+	if service == nil {
+		service = goa.New({{ printf "%q" .API.Name }})
+	}
+{{ range .Controllers }}	if ctrls.{{ . }} != nil {
+		{{ $.Target }}.Mount{{ . }}Controller(service, ctrls.{{ . }})
+	}
+{{ end }}	return service.Mux
+}
+{{ range .Mocks }}
+// {{ .Name }}ControllerMock implements the {{ $.Target }}.{{ .Name }}Controller interface with functions that
+// can be set individually. Actions whose function is nil do not write a response and return nil.
+// Mocks make it possible to exercise the request decoding and response encoding of the {{ .Resource }}
+// resource without a real controller, for example by giving them to NewTestServer.
+type {{ .Name }}ControllerMock struct {
+	*goa.Controller
+{{ range .Actions }}	{{ .Name }}Func func(*{{ $.Target }}.{{ .Context }}) error
+{{ end }}}
+
+// New{{ .Name }}ControllerMock creates a {{ .Resource }} controller mock for the given service.
+func New{{ .Name }}ControllerMock(service *goa.Service) *{{ .Name }}ControllerMock {
+	return &{{ .Name }}ControllerMock{Controller: service.NewController("{{ .Name }}ControllerMock")}
+}
+{{ $mock := . }}{{ range .Actions }}
+// {{ .Name }} runs {{ .Name }}Func if set.
+func (m *{{ $mock.Name }}ControllerMock) {{ .Name }}(ctx *{{ $.Target }}.{{ .Context }}) error {
+	if m.{{ .Name }}Func == nil {
+		return nil
+	}
+	return m.{{ .Name }}Func(ctx)
+}
+{{ end }}{{ end }}`
+
 var testTmpl = `{{ define "convertParam" }}` + convertParamTmpl + `{{ end }}` + `
 {{ range $test := . }}
 // {{ $test.Name }} {{ $test.Comment }}
@@ -393,11 +520,11 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 	// Setup request context
 	{{ $rw := $test.Escape "rw" }}{{ $rw }} := httptest.NewRecorder()
 {{ $query := $test.Escape "query" }}{{ if $test.QueryParams}}	{{ $query }} := url.Values{}
-{{ range $param := $test.QueryParams }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
+{{ range $param := $test.QueryParams }}{{ if not $param.BasicAuth }}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {{ end }}{
 {{ template "convertParam" $param }}
 		{{ $query }}[{{ printf "%q" $param.Label }}] = sliceVal
 	}
-{{ end }}{{ end }}	{{ $u := $test.Escape "u" }}{{ $u }}:= &url.URL{
+{{ end }}{{ end }}{{ end }}	{{ $u := $test.Escape "u" }}{{ $u }}:= &url.URL{
 		Path: fmt.Sprintf({{ printf "%q" $test.FullPath }}{{ range $param := $test.Params }}, {{ $param.Name }}{{ end }}),
 {{ if $test.QueryParams }}		RawQuery: {{ $query }}.Encode(),
 {{ end }}	}
@@ -409,13 +536,20 @@ func {{ $test.Name }}(t goatest.TInterface, ctx context.Context, service *goa.Se
 {{ template "convertParam" $header }}
 		{{ $req }}.Header[{{ printf "%q" $header.Label }}] = sliceVal
 	}
+{{ end }}{{ if $test.HasBasicAuth }}{{ $basicAuth := $test.Escape "basicAuth" }}	var {{ $basicAuth }} [2]string
+{{ range $param := $test.QueryParams }}{{ with $param.BasicAuth }}{{ $i := 0 }}{{ if eq . "password" }}{{ $i = 1 }}{{ end }}{{/*
+*/}}{{ if $param.Pointer }}	if {{ $param.Name }} != nil {
+		{{ $basicAuth }}[{{ $i }}] = *{{ $param.Name }}
+	}
+{{ else }}	{{ $basicAuth }}[{{ $i }}] = {{ $param.Name }}
+{{ end }}{{ end }}{{ end }}	{{ $req }}.SetBasicAuth({{ $basicAuth }}[0], {{ $basicAuth }}[1])
 {{ end }} {{ $prms := $test.Escape "prms" }}{{ $prms }} := url.Values{}
 {{ range $param := $test.Params }}	{{ $prms }}["{{ $param.Label }}"] = []string{fmt.Sprintf("%v",{{ $param.Name}})}
-{{ end }}{{ range $param := $test.QueryParams }}{{ if $param.Pointer }} if {{ $param.Name }} != nil {{ end }} {
+{{ end }}{{ range $param := $test.QueryParams }}{{ if not $param.BasicAuth }}{{ if $param.Pointer }} if {{ $param.Name }} != nil {{ end }} {
 {{ template "convertParam" $param }}
 		{{ $prms }}[{{ printf "%q" $param.Label }}] = sliceVal
 	}
-{{ end }}	if ctx == nil {
+{{ end }}{{ end }}	if ctx == nil {
 		ctx = context.Background()
 	}
 	{{ $goaCtx := $test.Escape "goaCtx" }}{{ $goaCtx }} := goa.NewContext(goa.WithAction(ctx, "{{ $test.ResourceName }}Test"), {{ $rw }}, {{ $req }}, {{ $prms }})
@@ -185,16 +185,37 @@ var _ = Describe("Generate", func() {
 
 		It("does not call Validate on the resulting media type when it does not exist", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(9))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
 			Ω(content).ShouldNot(ContainSubstring("err = mt.Validate()"))
 		})
 
+		It("generates a test server mounting the controllers", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "test_server.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring("Foo app.FooController"))
+			Ω(content).Should(ContainSubstring("func NewTestServer(service *goa.Service, ctrls TestControllers) http.Handler {"))
+			Ω(content).Should(ContainSubstring("app.MountFooController(service, ctrls.Foo)"))
+		})
+
+		It("generates controller mocks", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "test_server.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+
+			Ω(content).Should(ContainSubstring("type FooControllerMock struct {"))
+			Ω(content).Should(ContainSubstring("ShowFunc func(*app.ShowFooContext) error"))
+			Ω(content).Should(ContainSubstring("func NewFooControllerMock(service *goa.Service) *FooControllerMock {"))
+			Ω(content).Should(ContainSubstring("func (m *FooControllerMock) Show(ctx *app.ShowFooContext) error {"))
+		})
+
 		It("generates the ActionRouteResponse test methods ", func() {
 			Ω(genErr).Should(BeNil())
-			Ω(files).Should(HaveLen(8))
+			Ω(files).Should(HaveLen(9))
 			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
 			Ω(err).ShouldNot(HaveOccurred())
 
@@ -263,4 +284,118 @@ var _ = Describe("Generate", func() {
 			Ω(strings.Split(string(content), "\n")).Should(ContainElement(MatchRegexp(`^// Code generated .* DO NOT EDIT\.$`)))
 		})
 	})
+
+	Context("with an action reading params from the basic auth credentials", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			params := &design.AttributeDefinition{
+				Type: design.Object{
+					"user": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"param:basic-auth": []string{"username"}},
+					},
+					"pass": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"param:basic-auth": []string{"password"}},
+					},
+				},
+				Validation: &dslengine.ValidationDefinition{Required: []string{"user"}},
+			}
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				MediaTypes: map[string]*design.MediaTypeDefinition{
+					design.ErrorMedia.Identifier: design.ErrorMedia,
+				},
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name:        "show",
+								Params:      params,
+								QueryParams: params,
+								Routes:      []*design.RouteDefinition{{Verb: "GET", Path: ""}},
+								Responses: map[string]*design.ResponseDefinition{
+									"ok": {
+										Name:      "ok",
+										Type:      design.ErrorMedia,
+										MediaType: "application/vnd.goa.error",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			for _, a := range fooRes.Actions {
+				a.Parent = fooRes
+				a.Routes[0].Parent = a
+			}
+		})
+
+		It("sets the request credentials", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("basicAuth[0] = user\n"))
+			Ω(content).Should(ContainSubstring("basicAuth[1] = *pass\n"))
+			Ω(content).Should(ContainSubstring("req.SetBasicAuth(basicAuth[0], basicAuth[1])"))
+			Ω(content).ShouldNot(ContainSubstring(`["user"]`))
+			Ω(content).ShouldNot(ContainSubstring(`["pass"]`))
+		})
+	})
+
+	Context("with an action reading a param from a query string parameter with a different name", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			params := &design.AttributeDefinition{
+				Type: design.Object{
+					"idFilter": &design.AttributeDefinition{
+						Type:     design.String,
+						Metadata: dslengine.MetadataDefinition{"param:query": []string{"id"}},
+					},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				MediaTypes: map[string]*design.MediaTypeDefinition{
+					design.ErrorMedia.Identifier: design.ErrorMedia,
+				},
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name:        "show",
+								Params:      params,
+								QueryParams: params,
+								Routes:      []*design.RouteDefinition{{Verb: "GET", Path: ""}},
+								Responses: map[string]*design.ResponseDefinition{
+									"ok": {
+										Name:      "ok",
+										Type:      design.ErrorMedia,
+										MediaType: "application/vnd.goa.error",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			for _, a := range fooRes.Actions {
+				a.Parent = fooRes
+				a.Routes[0].Parent = a
+			}
+		})
+
+		It("sets the query string parameter", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "app", "test", "foo_testing.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring(`query["id"] = sliceVal`))
+			Ω(content).ShouldNot(ContainSubstring(`["idFilter"]`))
+		})
+	})
 })
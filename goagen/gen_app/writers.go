@@ -1,6 +1,7 @@
 package genapp
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -16,6 +17,13 @@ import (
 // WildcardRegex is the regex used to capture path parameters.
 var WildcardRegex = regexp.MustCompile("(?:[^/]*/:([^/]+))+")
 
+// validatedCtorTmpl is the template used to generate the NewValidated constructors.
+var validatedCtorTmpl = template.Must(template.New("ctor").Parse(validatedCtorT))
+
+// singleValueTmpl is the template used to generate the UnmarshalJSON methods of the types with
+// fields that accept a single value in place of an array.
+var singleValueTmpl = template.Must(template.New("singleValue").Parse(singleValueT))
+
 type (
 	// ContextsWriter generate codes for a goa application contexts.
 	ContextsWriter struct {
@@ -85,13 +93,16 @@ type (
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		// IdempotencyKey is "required" or "optional" if the action reads the Idempotency-Key
+		// header, empty otherwise.
+		IdempotencyKey string
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
 	ControllerTemplateData struct {
 		API            *design.APIDefinition          // API definition
 		Resource       string                         // Lower case plural resource name, e.g. "bottles"
-		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Routes", "Context" and "Unmarshal"
+		Actions        []map[string]interface{}       // Array of actions, each action has keys "Name", "DesignName", "Endpoint", "Routes", "Context" and "Unmarshal"
 		FileServers    []*design.FileServerDefinition // File servers
 		Encoders       []*EncoderTemplateData         // Encoder data
 		Decoders       []*EncoderTemplateData         // Decoder data
@@ -167,9 +178,82 @@ func (c *ContextTemplateData) HasParamAndHeader(name string) bool {
 }
 
 // MustValidate returns true if code that checks for the presence of the given param must be
-// generated.
+// generated. The params read from the basic auth credentials are all missing when the request
+// has no credentials so only the first of them that must be present is checked.
 func (c *ContextTemplateData) MustValidate(name string) bool {
-	return c.Params.IsRequired(name) && !c.IsPathParam(name)
+	if !c.Params.IsRequired(name) || c.IsPathParam(name) {
+		return false
+	}
+	obj := c.Params.Type.ToObject()
+	if basicAuth(obj[name]) == "" {
+		return true
+	}
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		if basicAuth(obj[n]) != "" && c.Params.IsRequired(n) && !c.Params.HasDefaultValue(n) {
+			return n == name
+		}
+	}
+	return true
+}
+
+// RequiredOneOfParams returns the groups of params where at least one param must be present. The
+// groups including a param that always holds a value, because it is required or has a default
+// value, are always satisfied and omitted.
+func (c *ContextTemplateData) RequiredOneOfParams() [][]string {
+	if c.Params == nil || c.Params.Validation == nil {
+		return nil
+	}
+	var groups [][]string
+	for _, group := range c.Params.Validation.RequiredOneOf {
+		satisfied := false
+		for _, n := range group {
+			if c.ParamUnset(n) == "" {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// ParamUnset returns the Go expression that tests whether the context field of the given param was
+// left unset because the request does not provide the param, whatever the sources the param is
+// read from. It returns the empty string if the field always holds a value.
+func (c *ContextTemplateData) ParamUnset(name string) string {
+	att := c.Params.Type.ToObject()[name]
+	if att == nil {
+		return ""
+	}
+	if bigNum(att) != "" || c.Params.IsPrimitivePointer(name) ||
+		att.Type.IsArray() && !c.Params.IsRequired(name) && !c.Params.HasDefaultValue(name) {
+		return "rctx." + codegen.GoifyAtt(att, name, true) + " == nil"
+	}
+	return ""
+}
+
+// MatrixSegments returns the sorted names of the path params whose values contain matrix params.
+func (c *ContextTemplateData) MatrixSegments() []string {
+	if c.Params == nil {
+		return nil
+	}
+	var segments []string
+	seen := make(map[string]bool)
+	for _, att := range c.Params.Type.ToObject() {
+		if s := matrixSegment(att); s != "" && !seen[s] {
+			seen[s] = true
+			segments = append(segments, s)
+		}
+	}
+	sort.Strings(segments)
+	return segments
 }
 
 // IterateResponses iterates through the responses sorted by status code.
@@ -206,7 +290,7 @@ func NewContextsWriter(filename string) (*ContextsWriter, error) {
 
 // Execute writes the code for the context types to the writer.
 func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
-	if err := w.ExecuteTemplate("context", ctxT, nil, data); err != nil {
+	if err := w.ExecuteTemplate("context", ctxT, template.FuncMap{"bigNumType": bigNumType, "isDate": isDate}, data); err != nil {
 		return err
 	}
 	fn := template.FuncMap{
@@ -215,29 +299,47 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 		"printVal":           codegen.PrintVal,
 		"canonicalHeaderKey": http.CanonicalHeaderKey,
 		"isPathParam":        data.IsPathParam,
+		"transform":          codegen.Transform,
+		"queryName":          queryName,
+		"paramAliases":       paramAliases,
+		"jsonArray":          jsonArray,
+		"errorCode":          errorCode,
+		"enumMap":            enumMap,
+		"matrixSegment":      matrixSegment,
+		"basicAuth":          basicAuth,
+		"paramValidator":     paramValidator,
+		"flagParam":          flagParam,
+		"boolValues":         boolValues,
+		"paramSources":       paramSources,
+		"bigNum":             bigNum,
+		"bigNumType":         bigNumType,
+		"isDate":             isDate,
+		"linkFields":         linkFields,
 	}
 	if err := w.ExecuteTemplate("new", ctxNewT, fn, data); err != nil {
 		return err
 	}
 	if data.Payload != nil {
-		found := false
-		for _, t := range design.Design.Types {
-			if t.TypeName == data.Payload.TypeName {
-				found = true
-				break
-			}
-		}
-		if !found {
+		if inlinePayload(data.Payload) {
 			fn := template.FuncMap{
-				"finalizeCode":   w.Finalizer.Code,
-				"validationCode": w.Validator.Code,
+				"finalizeCode":         w.Finalizer.Code,
+				"validationCode":       w.Validator.Code,
+				"trackPresence":        trackPresence,
+				"keepRawBody":          keepRawBody,
+				"payloadVariants":      payloadVariants,
+				"discriminator":        discriminator,
+				"validatedConstructor": validatedConstructor,
+				"singleValueDecoder":   singleValueDecoder,
 			}
 			if err := w.ExecuteTemplate("payload", payloadT, fn, data); err != nil {
 				return err
 			}
 		}
+		if err := w.ExecuteTemplate("payloadAccessor", ctxPayloadT, nil, data); err != nil {
+			return err
+		}
 	}
-	return data.IterateResponses(func(resp *design.ResponseDefinition) error {
+	err := data.IterateResponses(func(resp *design.ResponseDefinition) error {
 		respData := map[string]interface{}{
 			"Context":  data,
 			"Response": resp,
@@ -266,6 +368,7 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				}
 				sort.Strings(views)
 			}
+			var viewResps []map[string]string
 			for _, view := range views {
 				projected, _, err := mt.Project(view)
 				if err != nil {
@@ -284,11 +387,102 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 				if err := w.ExecuteTemplate("response", ctxMTRespT, fn, respData); err != nil {
 					return err
 				}
+				arg := "r"
+				if view != "default" {
+					arg = codegen.Goify(view, false)
+				}
+				viewResps = append(viewResps, map[string]string{
+					"View":     view,
+					"RespName": respData["RespName"].(string),
+					"Arg":      arg,
+					"Type":     codegen.GoTypeRef(projected, projected.AllRequired(), 0, false),
+				})
+			}
+			if param, ok := resp.Metadata["view:param"]; ok && resp.ViewName == "" && len(views) > 1 {
+				// Move the default view first so that it is the first argument.
+				for i, v := range viewResps {
+					if v["View"] == "default" {
+						viewResps = append(append([]map[string]string{v}, viewResps[:i]...), viewResps[i+1:]...)
+						break
+					}
+				}
+				name := "view"
+				if len(param) > 0 && param[0] != "" {
+					name = param[0]
+				}
+				viewData := map[string]interface{}{
+					"Context":  data,
+					"Response": resp,
+					"Param":    name,
+					"Views":    viewResps,
+				}
+				return w.ExecuteTemplate("response", ctxViewRespT, nil, viewData)
 			}
 			return nil
 		}
 		return w.ExecuteTemplate("response", ctxNoMTRespT, nil, respData)
 	})
+	if err != nil {
+		return err
+	}
+	return w.writeRespond(data)
+}
+
+// writeRespond writes the Respond helper for actions whose responses use a media type with an
+// attribute holding the response status code. The attribute is identified by the http:status
+// metadata.
+func (w *ContextsWriter) writeRespond(data *ContextTemplateData) error {
+	var (
+		mt        *design.MediaTypeDefinition
+		projected *design.MediaTypeDefinition
+		field     string
+		pointer   bool
+		resps     []*design.ResponseDefinition
+	)
+	err := data.IterateResponses(func(resp *design.ResponseDefinition) error {
+		if resp.ViewName != "" && resp.ViewName != "default" {
+			return nil
+		}
+		rmt, ok := resp.Type.(*design.MediaTypeDefinition)
+		if !ok {
+			rmt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
+		}
+		if rmt == nil {
+			return nil
+		}
+		if mt == nil {
+			p, _, err := rmt.Project("default")
+			if err != nil {
+				return nil
+			}
+			p.Type.ToObject().IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+				if _, ok := att.Metadata["http:status"]; ok && att.Type.Kind() == design.IntegerKind && field == "" {
+					field = codegen.GoifyAtt(att, n, true)
+					pointer = p.IsPrimitivePointer(n)
+				}
+				return nil
+			})
+			if field == "" {
+				return nil
+			}
+			mt, projected = rmt, p
+		} else if rmt.Identifier != mt.Identifier {
+			return nil
+		}
+		resps = append(resps, resp)
+		return nil
+	})
+	if err != nil || len(resps) == 0 {
+		return err
+	}
+	respData := map[string]interface{}{
+		"Context":   data,
+		"Projected": projected,
+		"Field":     field,
+		"Pointer":   pointer,
+		"Responses": resps,
+	}
+	return w.ExecuteTemplate("respond", ctxRespondT, nil, respData)
 }
 
 // NewControllersWriter returns a handlers code writer.
@@ -336,14 +530,68 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 			}
 		}
 		fn := template.FuncMap{
-			"finalizeCode":   w.Finalizer.Code,
-			"validationCode": w.Validator.Code,
+			"finalizeCode":    w.Finalizer.Code,
+			"validationCode":  w.Validator.Code,
+			"trackPresence":   trackPresence,
+			"keepRawBody":     keepRawBody,
+			"discriminator":   discriminator,
+			"payloadVariants": payloadVariants,
 		}
 		if err := w.ExecuteTemplate("unmarshal", unmarshalT, fn, d); err != nil {
 			return err
 		}
 	}
-	return nil
+	if err := w.writeValidateEndpointPayload(data); err != nil {
+		return err
+	}
+	return w.writeMapServiceError(data[0].API)
+}
+
+// writeMapServiceError writes the MapServiceError function which maps errors to the HTTP status
+// code and body of the corresponding responses using the server error statuses declared by the API
+// actions. Internal errors are always kept.
+func (w *ControllersWriter) writeMapServiceError(api *design.APIDefinition) error {
+	declared := map[int]bool{http.StatusInternalServerError: true}
+	if api != nil {
+		api.IterateResources(func(r *design.ResourceDefinition) error {
+			return r.IterateActions(func(a *design.ActionDefinition) error {
+				for _, resp := range a.Responses {
+					if resp.Status >= http.StatusInternalServerError {
+						declared[resp.Status] = true
+					}
+				}
+				return nil
+			})
+		})
+	}
+	statuses := make([]int, 0, len(declared))
+	for status := range declared {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	return w.ExecuteTemplate("mapServiceError", mapServiceErrorT, nil, statuses)
+}
+
+// writeValidateEndpointPayload writes the ValidateEndpointPayload function which validates the
+// payload of any action given the action endpoint name.
+func (w *ControllersWriter) writeValidateEndpointPayload(data []*ControllerTemplateData) error {
+	var actions []map[string]interface{}
+	for _, d := range data {
+		for _, a := range d.Actions {
+			if p, ok := a["Payload"].(*design.UserTypeDefinition); ok && p != nil {
+				actions = append(actions, a)
+			}
+		}
+	}
+	if len(actions) == 0 {
+		return nil
+	}
+	fn := template.FuncMap{
+		"hasValidate": func(p *design.UserTypeDefinition) bool {
+			return w.Validator.Code(p.AttributeDefinition, false, false, false, "payload", "raw", 1, false) != ""
+		},
+	}
+	return w.ExecuteTemplate("validateEndpointPayload", validateEndpointPayloadT, fn, actions)
 }
 
 // NewSecurityWriter returns a security functionality code writer.
@@ -390,7 +638,16 @@ func NewMediaTypesWriter(filename string) (*MediaTypesWriter, error) {
 func (w *MediaTypesWriter) Execute(mt *design.MediaTypeDefinition) error {
 	var (
 		mLinks *design.UserTypeDefinition
-		fn     = template.FuncMap{"validationCode": w.Validator.Code}
+		fn     = template.FuncMap{
+			"validationCode": w.Validator.Code,
+			// The projected media types do not carry the metadata of the media type.
+			"validatedConstructor": func(att *design.AttributeDefinition, typeName string, validates bool) string {
+				if _, ok := mt.Metadata["struct:constructor"]; !ok {
+					return ""
+				}
+				return constructorCode(att, typeName, validates)
+			},
+		}
 	)
 	err := mt.IterateViews(func(view *design.ViewDefinition) error {
 		p, links, err := mt.Project(view.Name)
@@ -433,8 +690,10 @@ func NewUserTypesWriter(filename string) (*UserTypesWriter, error) {
 // Execute writes the code for the context types to the writer.
 func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 	fn := template.FuncMap{
-		"finalizeCode":   w.Finalizer.Code,
-		"validationCode": w.Validator.Code,
+		"finalizeCode":         w.Finalizer.Code,
+		"validationCode":       w.Validator.Code,
+		"validatedConstructor": validatedConstructor,
+		"singleValueDecoder":   singleValueDecoder,
 	}
 	return w.ExecuteTemplate("types", userTypeT, fn, t)
 }
@@ -451,6 +710,394 @@ func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, p
 	}
 }
 
+// inlinePayload returns true if the given payload is defined inline in the action rather than
+// with a user type of the API. The contexts writer generates the types of inline payloads.
+func inlinePayload(payload *design.UserTypeDefinition) bool {
+	for _, t := range design.Design.Types {
+		if t.TypeName == payload.TypeName {
+			return false
+		}
+	}
+	return true
+}
+
+// trackPresence returns true if the code generated for the given payload must record the names
+// of the fields present in the request body. This makes it possible to distinguish fields that
+// are absent from fields explicitly set to null. Only payloads defined inline are supported, the
+// design validation rejects the metadata on other payloads.
+func trackPresence(payload *design.UserTypeDefinition) bool {
+	if _, ok := payload.Metadata["payload:presence"]; !ok || !payload.IsObject() {
+		return false
+	}
+	return inlinePayload(payload)
+}
+
+// ctorField describes a field initialized by a NewValidated constructor.
+type ctorField struct {
+	// FieldName is the name of the struct field.
+	FieldName string
+	// VarName is the name of the constructor argument, empty if the field is initialized with
+	// the default value.
+	VarName string
+	// TypeRef is the Go type of the field.
+	TypeRef string
+	// Value is the Go code of the value assigned to the field.
+	Value string
+}
+
+// validatedConstructor returns the code of the NewValidated constructor of the public type with
+// the given name if att has the struct:constructor metadata, the empty string otherwise.
+func validatedConstructor(att *design.AttributeDefinition, typeName string, validates bool) string {
+	if _, ok := att.Metadata["struct:constructor"]; !ok {
+		return ""
+	}
+	return constructorCode(att, typeName, validates)
+}
+
+// constructorCode returns the code of the NewValidated constructor of the public type with the
+// given name. The required attributes with no default value become the constructor arguments in
+// alphabetical order, the primitive attributes with a default value are initialized with it.
+func constructorCode(att *design.AttributeDefinition, typeName string, validates bool) string {
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return ""
+	}
+	var args, fields []*ctorField
+	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		f := &ctorField{FieldName: codegen.GoifyAtt(at, n, true)}
+		switch {
+		case att.IsRequired(n) && !att.HasDefaultValue(n):
+			f.VarName = codegen.Goify(n, false)
+			f.TypeRef = codegen.GoTypeDef(at, 1, false, false)
+			if at.Type.IsObject() {
+				f.TypeRef = "*" + f.TypeRef
+			}
+			f.Value = f.VarName
+			args = append(args, f)
+		case att.HasDefaultValue(n) && at.Type.IsPrimitive() && at.Type.Kind() != design.DateTimeKind &&
+			at.Type.Kind() != design.UUIDKind && at.Type.Kind() != design.AnyKind:
+			f.Value = codegen.PrintVal(at.Type, at.DefaultValue)
+		default:
+			return nil
+		}
+		fields = append(fields, f)
+		return nil
+	})
+	// Name the constructed value so that it does not shadow an argument, Goify only produces
+	// trailing underscores for reserved words.
+	varName := "v"
+	for _, a := range args {
+		if a.VarName == varName {
+			varName += "_"
+		}
+	}
+	data := map[string]interface{}{
+		"TypeName":  typeName,
+		"VarName":   varName,
+		"Args":      args,
+		"Fields":    fields,
+		"Validates": validates,
+	}
+	var b bytes.Buffer
+	if err := validatedCtorTmpl.Execute(&b, data); err != nil {
+		panic(err) // bug
+	}
+	return b.String()
+}
+
+// singleValueField describes an array field that accepts a single value when decoded from JSON.
+type singleValueField struct {
+	// FieldName is the name of the struct field.
+	FieldName string
+	// JSONName is the name of the field in the JSON representation.
+	JSONName string
+}
+
+// singleValueDecoder returns the code of the UnmarshalJSON method of the private type with the
+// given name if att has array attributes with the "json:single-value" metadata, the empty string
+// otherwise. The method decodes a single value given in place of an array of these attributes
+// into a slice with one element.
+func singleValueDecoder(att *design.AttributeDefinition, typeName, receiver string) string {
+	obj := att.Type.ToObject()
+	if obj == nil {
+		return ""
+	}
+	var fields []*singleValueField
+	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		if _, ok := at.Metadata["json:single-value"]; !ok || !at.Type.IsArray() {
+			return nil
+		}
+		name := n
+		if tag, ok := at.Metadata["struct:tag:json"]; ok && len(tag) > 0 && tag[0] != "" {
+			name = tag[0]
+		}
+		fields = append(fields, &singleValueField{
+			FieldName: codegen.GoifyAtt(at, n, true),
+			JSONName:  name,
+		})
+		return nil
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	data := map[string]interface{}{
+		"TypeName": typeName,
+		"Receiver": receiver,
+		"Fields":   fields,
+	}
+	var b bytes.Buffer
+	if err := singleValueTmpl.Execute(&b, data); err != nil {
+		panic(err) // bug
+	}
+	return b.String()
+}
+
+// keepRawBody returns true if the code generated for the given payload must store the raw request
+// body bytes in the payload RawBody field. Only payloads defined inline are supported.
+func keepRawBody(payload *design.UserTypeDefinition) bool {
+	if _, ok := payload.Metadata["payload:raw"]; !ok || !payload.IsObject() {
+		return false
+	}
+	return inlinePayload(payload)
+}
+
+// payloadVariant describes a variant of a payload with a discriminator.
+type payloadVariant struct {
+	// Value is the value of the discriminator that selects the variant.
+	Value string
+	// Type is the type the request body is decoded into.
+	Type *design.UserTypeDefinition
+}
+
+// discriminator returns the name of the payload attribute whose value selects the type the
+// request body is decoded into, the empty string if the payload has no variants.
+func discriminator(payload *design.UserTypeDefinition) string {
+	d, ok := payload.Metadata["payload:discriminator"]
+	if !ok || len(d) == 0 || !payload.IsObject() || !inlinePayload(payload) {
+		return ""
+	}
+	return d[0]
+}
+
+// payloadVariants returns the variants of the payload defined with the payload:variants metadata
+// in the order they are listed, nil if the payload has no discriminator.
+func payloadVariants(payload *design.UserTypeDefinition) []*payloadVariant {
+	if discriminator(payload) == "" {
+		return nil
+	}
+	var variants []*payloadVariant
+	for _, v := range payload.Metadata["payload:variants"] {
+		elems := strings.SplitN(v, "=", 2)
+		if len(elems) != 2 {
+			continue
+		}
+		if t, ok := design.Design.Types[elems[1]]; ok {
+			variants = append(variants, &payloadVariant{Value: elems[0], Type: t})
+		}
+	}
+	return variants
+}
+
+// aggregateErrors returns true if the action is marked with the validation:aggregate metadata.
+func aggregateErrors(a *design.ActionDefinition) bool {
+	_, ok := a.Metadata["validation:aggregate"]
+	return ok
+}
+
+// basicAuth returns "username" or "password" if the value of the given param is read from the
+// request HTTP Basic auth credentials, the empty string otherwise.
+func basicAuth(a *design.AttributeDefinition) string {
+	if v, ok := a.Metadata["param:basic-auth"]; ok && len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// paramValidator returns the name of the param validator registered in the service that validates
+// the value of the given param at runtime, the empty string if there is none.
+func paramValidator(a *design.AttributeDefinition) string {
+	if v, ok := a.Metadata["validation:func"]; ok && len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// matrixSegment returns the name of the path param whose value contains the given matrix param,
+// the empty string if the param is not a matrix param.
+func matrixSegment(a *design.AttributeDefinition) string {
+	if n, ok := a.Metadata["param:matrix"]; ok && len(n) > 0 {
+		return n[0]
+	}
+	return ""
+}
+
+// queryName returns the name of the query string parameter the value of the given param is read
+// from if it differs from the name of the param, the empty string otherwise.
+func queryName(a *design.AttributeDefinition) string {
+	if n, ok := a.Metadata["param:query"]; ok && len(n) > 0 {
+		return n[0]
+	}
+	return ""
+}
+
+// paramAliases returns the alternate names of the query string parameters the value of the given
+// param is read from when the request does not define it.
+func paramAliases(a *design.AttributeDefinition) []string {
+	return a.Metadata["param:alias"]
+}
+
+// jsonArray returns true if the raw value of the given array param may be written as a JSON array
+// instead of comma separated values.
+func jsonArray(a *design.AttributeDefinition) bool {
+	_, ok := a.Metadata["param:json"]
+	return ok && a.Type.IsArray()
+}
+
+// flagParam returns true if the given param is true when present in the request with an empty
+// value.
+func flagParam(a *design.AttributeDefinition) bool {
+	_, ok := a.Metadata["param:flag"]
+	return ok && a.Type.Kind() == design.BooleanKind
+}
+
+// paramSources returns the Go expressions that read the values of the given param from the
+// sources listed in its "param:sources" metadata, in order. The sources are "header", "query" and
+// "path", optionally followed by a colon and the name of the header or query string parameter.
+// paramSources returns nil if the param does not define the metadata.
+func paramSources(a *design.AttributeDefinition, name string) []string {
+	var srcs []string
+	for _, s := range a.Metadata["param:sources"] {
+		kind, n := s, name
+		if i := strings.Index(s, ":"); i > 0 {
+			kind, n = s[:i], s[i+1:]
+		}
+		switch kind {
+		case "header":
+			srcs = append(srcs, fmt.Sprintf("req.Header[%q]", http.CanonicalHeaderKey(n)))
+		case "query":
+			srcs = append(srcs, fmt.Sprintf("req.URL.Query()[%q]", n))
+		case "path":
+			// Path params take precedence over query string params in req.Params.
+			srcs = append(srcs, fmt.Sprintf("req.Params[%q]", name))
+		}
+	}
+	return srcs
+}
+
+// boolValues returns the strings that stand for true and false in the values of the given boolean
+// param or header, nil if the param uses the standard boolean values.
+func boolValues(a *design.AttributeDefinition) []string {
+	vals, ok := a.Metadata["param:bool-values"]
+	if !ok || len(vals) != 2 || a.Type.Kind() != design.BooleanKind {
+		return nil
+	}
+	return vals
+}
+
+// enumMap returns the Go code of the map literal that maps the wire values of the given param to
+// its internal values, the empty string if the param does not define the "param:enum-map"
+// metadata.
+func enumMap(a *design.AttributeDefinition) string {
+	vals, ok := a.Metadata["param:enum-map"]
+	if !ok || !a.Type.IsPrimitive() {
+		return ""
+	}
+	elems := make([]string, 0, len(vals))
+	for _, v := range vals {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		elems = append(elems, fmt.Sprintf("%q: %q", kv[0], kv[1]))
+	}
+	return fmt.Sprintf("map[string]string{%s}", strings.Join(elems, ", "))
+}
+
+// errorCode returns the code of the errors sent with the given error response: the value of the
+// "error:code" metadata if any, the snake case response name otherwise.
+func errorCode(r *design.ResponseDefinition) string {
+	if c, ok := r.Metadata["error:code"]; ok && len(c) > 0 && c[0] != "" {
+		return c[0]
+	}
+	return codegen.SnakeCase(r.Name)
+}
+
+// bigNum returns "Int" or "Rat" if the value of the given param must be parsed into a big.Int or a
+// big.Rat respectively, the empty string otherwise. Only string and array of string params
+// support the "param:bignum" metadata.
+func bigNum(a *design.AttributeDefinition) string {
+	n, ok := a.Metadata["param:bignum"]
+	if !ok {
+		return ""
+	}
+	kind := a.Type.Kind()
+	if a.Type.IsArray() {
+		kind = arrayAttribute(a).Type.Kind()
+	}
+	if kind != design.StringKind {
+		return ""
+	}
+	if len(n) > 0 && n[0] == "rat" {
+		return "Rat"
+	}
+	return "Int"
+}
+
+// bigNumType returns the Go type of the context field holding the value of the given param if it
+// is parsed into a math/big number, the empty string otherwise.
+func bigNumType(a *design.AttributeDefinition) string {
+	n := bigNum(a)
+	if n == "" {
+		return ""
+	}
+	if a.Type.IsArray() {
+		return "[]*big." + n
+	}
+	return "*big." + n
+}
+
+// isDate returns true if the given param is a string or an array of strings using the "date"
+// format. The values of such params are parsed into time.Time values.
+func isDate(a *design.AttributeDefinition) bool {
+	if a.Type.IsArray() {
+		a = arrayAttribute(a)
+	}
+	return a.Type.Kind() == design.StringKind && a.Validation != nil && a.Validation.Format == "date"
+}
+
+// linkField describes a media type attribute holding the URL of a pagination link.
+type linkField struct {
+	// Rel is the link relation type, e.g. "next".
+	Rel string
+	// Field is the name of the Go struct field holding the link URL.
+	Field string
+	// Pointer is true if the struct field is a pointer.
+	Pointer bool
+}
+
+// linkFields returns the string attributes of the given media type projection that define the
+// "http:link" metadata sorted by attribute name.
+func linkFields(p *design.MediaTypeDefinition) []*linkField {
+	o := p.Type.ToObject()
+	if o == nil {
+		return nil
+	}
+	var fields []*linkField
+	o.IterateAttributes(func(n string, att *design.AttributeDefinition) error {
+		rel, ok := att.Metadata["http:link"]
+		if !ok || len(rel) == 0 || att.Type.Kind() != design.StringKind {
+			return nil
+		}
+		fields = append(fields, &linkField{
+			Rel:     rel[0],
+			Field:   codegen.GoifyAtt(att, n, true),
+			Pointer: p.IsPrimitivePointer(n),
+		})
+		return nil
+	})
+	return fields
+}
+
 // arrayAttribute returns the array element attribute definition.
 func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
 	return a.Type.(*design.Array).ElemType
@@ -467,7 +1114,7 @@ type {{ .Name }} struct {
 {{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}{{ if not ($.HasParamAndHeader $name) }}{{/*
 */}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Headers.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
-*/}}	{{ goifyatt $att $name true }} {{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
+*/}}	{{ goifyatt $att $name true }} {{ with bigNumType $att }}{{ . }}{{ else }}{{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ if isDate $att }}{{ if $att.Type.IsArray }}[]{{ end }}time.Time{{ else }}{{ gotyperef $att.Type nil 0 false }}{{ end }}{{ end }}
 {{ end }}{{ end }}{{ if .Payload }}	Payload {{ gotyperef .Payload nil 0 false }}
 {{ end }}}
 `
@@ -478,8 +1125,10 @@ type {{ .Name }} struct {
 
 */}}{{/* BooleanType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
-*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := strconv.ParseBool(raw{{ goify .Name true }}); err2 == nil {
-{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+*/}}{{ with boolValues .Attribute }}{{ tabs $.Depth }}if raw{{ goify $.Name true }} == {{ printf "%q" (index . 0) }} || raw{{ goify $.Name true }} == {{ printf "%q" (index . 1) }} {
+{{ tabs $.Depth }}	{{ $.VarName }} := raw{{ goify $.Name true }} == {{ printf "%q" (index . 0) }}
+{{ else }}{{ tabs .Depth }}if {{ .VarName }}, err2 := strconv.ParseBool(raw{{ goify .Name true }}); err2 == nil {
+{{ end }}{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
 {{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "boolean"))
@@ -538,9 +1187,32 @@ type {{ .Name }} struct {
 {{ else }}{{ tabs .Depth }}{{ .Pkg }} = raw{{ goify .Name true }}
 {{ end }}{{ end }}`
 
+	// bigNumT generates the code that parses the raw value of a param into a math/big number.
+	// template input: map[string]interface{} as returned by newCoerceData
+	bigNumT = `{{ $big := bigNum .Attribute }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, ok := new(big.{{ $big }}).SetString(raw{{ goify .Name true }}{{ if eq $big "Int" }}, 10{{ end }}); ok {
+{{ tabs .Depth }}	{{ .Pkg }} = {{ .VarName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "{{ if eq $big "Int" }}big integer{{ else }}big rational{{ end }}"))
+{{ tabs .Depth }}}
+`
+
+	// dateT generates the code that parses the raw value of a param into a time.Time using the
+	// RFC3339 full-date layout.
+	// template input: map[string]interface{} as returned by newCoerceData
+	dateT = `{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := time.Parse("2006-01-02", raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "date"))
+{{ tabs .Depth }}}
+`
+
 	// ctxNewT generates the code for the context factory method.
 	// template input: *ContextTemplateData
-	ctxNewT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `
+	ctxNewT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `{{ define "BigNum" }}` + bigNumT + `{{ end }}` +
+		`{{ define "Date" }}` + dateT + `{{ end }}` + `
 // New{{ goify .Name true }} parses the incoming request URL and body, performs validations and creates the
 // context used by the {{ .ResourceName }} controller {{ .ActionName }} action.
 func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service) (*{{ .Name }}, error) {
@@ -549,7 +1221,12 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	resp.Service = service
 	req := goa.ContextRequest(ctx)
 	req.Request = r
-	rctx := {{ .Name }}{Context: ctx, ResponseData: resp, RequestData: req}{{/*
+{{ with .IdempotencyKey }}	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = goa.WithIdempotencyKey(ctx, key)
+	}{{ if eq . "required" }} else {
+		err = goa.MergeErrors(err, goa.MissingHeaderError("Idempotency-Key"))
+	}{{ end }}
+{{ end }}	rctx := {{ .Name }}{Context: ctx, ResponseData: resp, RequestData: req}{{/*
 */}}
 {{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}	header{{ goify $name true }} := req.Header["{{ canonicalHeaderKey $name }}"]
 {{ $mustValidate := $.Headers.IsRequired $name }}{{ if $mustValidate }}	if len(header{{ goify $name true }}) == 0 {
@@ -557,13 +1234,17 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	} else {
 {{ else }}	if len(header{{ goify $name true }}) > 0 {
 {{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}		req.Params["{{ $name }}"] = header{{ goify $name true }}
-{{ if eq (arrayAttribute $att).Type.Kind 4 }}		headers := header{{ goify $name true }}
-{{ else }}		headers := make({{ gotypedef $att 2 true false }}, len(header{{ goify $name true }}))
+{{ if eq (arrayAttribute $att).Type.Kind 4 }}{{ if eq (transform $att "v") "v" }}		headers := header{{ goify $name true }}
+{{ else }}		headers := make([]string, len(header{{ goify $name true }}))
+		for i, raw{{ goify $name true}} := range header{{ goify $name true}} {
+			headers[i] = {{ transform $att (printf "raw%s" (goify $name true)) }}
+		}
+{{ end }}{{ else }}		headers := make({{ gotypedef $att 2 true false }}, len(header{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range header{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Headers.IsPrimitivePointer $name) "headers[i]" 3) }}{{/*
 */}}		}
 {{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = headers
-{{ else }}		raw{{ goify $name true}} := header{{ goify $name true}}[0]
+{{ else }}		raw{{ goify $name true}} := {{ transform $att (printf "header%s[0]" (goify $name true)) }}
 		req.Params["{{ $name }}"] = []string{raw{{ goify $name true }}}
 {{ template "Coerce" (newCoerceData $name $att ($.Headers.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Headers.IsNonZero $name) ($.Headers.IsRequired $name) ($.Headers.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
@@ -571,31 +1252,111 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 {{ end }}	}
 {{ end }}{{ end }}{{/* if .Headers }}{{/*
 
-*/}}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
-*/}}	param{{ goify $name true }} := req.Params["{{ $name }}"]
-{{ if and (isPathParam $name) (eq $att.Type.Name "array") }}	if len(param{{ goify $name true }}) > 0 {
+*/}}{{ range .MatrixSegments }}	matrix{{ goify . true }} := goa.ParseMatrixParams(req.Params, "{{ . }}")
+{{ end }}{{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
+*/}}{{ with matrixSegment $att }}	param{{ goify $name true }} := matrix{{ goify . true }}["{{ $name }}"]
+	if len(param{{ goify $name true }}) == 0 {
+		param{{ goify $name true }} = req.Params["{{ $name }}"]
+	}
+{{ else }}{{ with basicAuth $att }}	var param{{ goify $name true }} []string
+	if {{ if eq . "username" }}v, _{{ else }}_, v{{ end }}, ok := r.BasicAuth(); ok {
+		param{{ goify $name true }} = []string{v}
+	}
+{{ else }}{{ with paramSources $att $name }}	var param{{ goify $name true }} []string
+{{ range $i, $src := . }}	{{ if $i }}} else {{ end }}if v := {{ $src }}; len(v) > 0 {
+		param{{ goify $name true }} = v
+{{ end }}	}
+{{ else }}	param{{ goify $name true }} := {{ with queryName $att }}req.URL.Query()["{{ . }}"]{{ else }}req.Params["{{ $name }}"]{{ end }}
+{{ end }}{{ end }}{{ end }}{{ with paramAliases $att }}	if len(param{{ goify $name true }}) == 0 {
+		for _, alias := range {{ printf "%#v" . }} {
+			if v := req.URL.Query()[alias]; len(v) > 0 && v[0] != "" {
+				param{{ goify $name true }} = v
+				break
+			}
+		}
+	}
+{{ end }}{{ with enumMap $att }}	if len(param{{ goify $name true }}) > 0 {
+		if v, ok := {{ . }}[param{{ goify $name true }}[0]]; ok {
+			param{{ goify $name true }} = []string{v}
+		}
+	}
+{{ end }}{{ if flagParam $att }}	if len(param{{ goify $name true }}) > 0 && param{{ goify $name true }}[0] == "" {
+		param{{ goify $name true }} = []string{"true"}
+	}
+{{ end }}{{ if jsonArray $att }}	if elems, err2 := goa.SplitArrayParam(param{{ goify $name true }}); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ $name }}", param{{ goify $name true }}[0], "array"))
+		param{{ goify $name true }} = nil
+	} else {
+		param{{ goify $name true }} = elems
+	}
+{{ else if and (isPathParam $name) (eq $att.Type.Name "array") }}	if len(param{{ goify $name true }}) > 0 {
 		param{{ goify $name true }} = strings.Split(param{{ goify $name true}}, ",")
 	}
 {{ end }}{{ $mustValidate := $.MustValidate $name }}{{ if $mustValidate }}	if len(param{{ goify $name true }}) == 0 {
 		{{ if $.Params.HasDefaultValue $name }}{{printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}{{else}}{{/*
-*/}}err = goa.MergeErrors(err, goa.MissingParamError("{{ $name }}")){{end}}
+*/}}err = goa.MergeErrors(err, {{ if basicAuth $att }}goa.ErrUnauthorized("missing basic auth credentials"){{ else }}goa.MissingParamError("{{ $name }}"){{ end }}){{end}}
 	} else {
 {{ else }}{{ if $.Params.HasDefaultValue $name }}	if len(param{{ goify $name true }}) == 0 {
 		{{printf "rctx.%s" (goifyatt $att $name true) }} = {{ printVal $att.Type $att.DefaultValue }}
 	} else {
 {{ else }}	if len(param{{ goify $name true }}) > 0 {
-{{ end }}{{ end }}{{/* if $mustValidate */}}{{ if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}		params := param{{ goify $name true }}
-{{ else }}		params := make({{ gotypedef $att 2 true false }}, len(param{{ goify $name true }}))
+{{ end }}{{ end }}{{/* if $mustValidate */}}{{ if bigNum $att }}{{ if $att.Type.IsArray }}		params := make({{ bigNumType $att }}, len(param{{ goify $name true }}))
+		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
+{{ template "BigNum" (newCoerceData $name $att false "params[i]" 3) }}{{/*
+*/}}		}
+		{{ printf "rctx.%s" (goifyatt $att $name true) }} = params
+{{ else }}		raw{{ goify $name true}} := {{ transform $att (printf "param%s[0]" (goify $name true)) }}
+{{ template "BigNum" (newCoerceData $name $att false (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
+*/}}{{ else if isDate $att }}{{ if $att.Type.IsArray }}		params := make([]time.Time, len(param{{ goify $name true }}))
+		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
+{{ template "Date" (newCoerceData $name $att false "params[i]" 3) }}{{/*
+*/}}		}
+		{{ printf "rctx.%s" (goifyatt $att $name true) }} = params
+{{ else }}		raw{{ goify $name true}} := {{ transform $att (printf "param%s[0]" (goify $name true)) }}
+{{ template "Date" (newCoerceData $name $att ($.Params.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
+*/}}{{ else }}{{ if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 4 }}{{ if eq (transform $att "v") "v" }}		params := param{{ goify $name true }}
+{{ else }}		params := make([]string, len(param{{ goify $name true }}))
+		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
+			params[i] = {{ transform $att (printf "raw%s" (goify $name true)) }}
+		}
+{{ end }}{{ else }}		params := make({{ gotypedef $att 2 true false }}, len(param{{ goify $name true }}))
 		for i, raw{{ goify $name true}} := range param{{ goify $name true}} {
 {{ template "Coerce" (newCoerceData $name (arrayAttribute $att) ($.Params.IsPrimitivePointer $name) "params[i]" 3) }}{{/*
 */}}		}
 {{ end }}		{{ printf "rctx.%s" (goifyatt $att $name true) }} = params
-{{ else }}		raw{{ goify $name true}} := param{{ goify $name true}}[0]
+{{ else }}		raw{{ goify $name true}} := {{ transform $att (printf "param%s[0]" (goify $name true)) }}
 {{ template "Coerce" (newCoerceData $name $att ($.Params.IsPrimitivePointer $name) (printf "rctx.%s" (goifyatt $att $name true)) 2) }}{{ end }}{{/*
 */}}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goifyatt $att $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
-{{ end }}	}
-{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
+{{ end }}{{ with paramValidator $att }}{{ if $.Params.IsPrimitivePointer $name }}		if rctx.{{ goifyatt $att $name true }} != nil {
+			if err2 := service.ValidateParam({{ printf "%q" . }}, "{{ $name }}", *rctx.{{ goifyatt $att $name true }}); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+{{ else }}		if err2 := service.ValidateParam({{ printf "%q" . }}, "{{ $name }}", rctx.{{ goifyatt $att $name true }}); err2 != nil {
+			err = goa.MergeErrors(err, err2)
+		}
+{{ end }}{{ end }}{{ end }}{{/* if bigNum, isDate */}}	}
+{{ end }}{{ end }}{{/* if .Params */}}{{ range .RequiredOneOfParams }}	if {{ range $i, $n := . }}{{ if $i }} && {{ end }}{{ $.ParamUnset $n }}{{ end }} {
+		err = goa.MergeErrors(err, goa.MissingOneOfParamsError({{ printf "%#v" . }}))
+	}
+{{ end }}	return &rctx, err
+}
+`
+
+	// ctxPayloadT generates the function that returns the payload stored in the request context.
+	// template input: *ContextTemplateData
+	ctxPayloadT = `{{ $name := printf "Context%s%sPayload" (goify .ActionName true) (goify .ResourceName true) }}{{/*
+*/}}{{ $type := gotyperef .Payload nil 0 false }}
+// {{ $name }} returns the payload of the {{ .ActionName }} action of the {{ .ResourceName }} resource
+// stored in the request context once the request body is decoded, the zero value if there is none.
+// It makes the typed payload available to middleware running before the action handler.
+func {{ $name }}(ctx context.Context) {{ $type }} {
+	var p {{ $type }}
+	if req := goa.ContextRequest(ctx); req != nil {
+		p, _ = req.Payload.({{ $type }})
+	}
+	return p
 }
 `
 
@@ -604,11 +1365,33 @@ func New{{ .Name }}(ctx context.Context, r *http.Request, service *goa.Service)
 	ctxMTRespT = `// {{ goify .RespName true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-{{ if .Projected.Type.IsArray }}	if r == nil {
+{{ with .Response.CacheControl }}	ctx.ResponseData.Header().Set("Cache-Control", {{ printf "%q" . }})
+{{ end }}{{ if .Projected.IsError }}	if _, ok := r.(goa.ServiceError); !ok {
+		r = goa.NewErrorClass({{ printf "%q" (errorCode .Response) }}, {{ .Response.Status }})(r)
+	}
+{{ end }}{{ if .Projected.Type.IsArray }}	if r == nil {
 		r = {{ gotyperef .Projected .Projected.AllRequired 0 false }}{}
 	}
+{{ end }}{{ range linkFields .Projected }}	if r != nil && r.{{ .Field }} != {{ if .Pointer }}nil{{ else }}""{{ end }} {
+		ctx.ResponseData.Header().Add("Link", "<"+{{ if .Pointer }}*{{ end }}r.{{ .Field }}+{{ printf "%q" (printf ">; rel=%q" .Rel) }})
+	}
 {{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
+`
+
+	// ctxViewRespT generates the response helper that selects the response view at runtime.
+	// template input: map[string]interface{}
+	ctxViewRespT = `
+// {{ goify .Response.Name true }}View sends a HTTP response with status code {{ .Response.Status }} using the view selected by
+// the "{{ .Param }}" query string parameter or by the "view" parameter of the Accept header media type.
+// The response uses the default view if the request does not select one of the media type views.
+func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}View({{ range $i, $v := .Views }}{{ if $i }}, {{ end }}{{ $v.Arg }} {{ $v.Type }}{{ end }}) error {
+	switch goa.RequestedView(ctx.RequestData.Request, {{ printf "%q" .Param }}) {
+{{ range .Views }}{{ if ne .View "default" }}	case {{ printf "%q" .View }}:
+		return ctx.{{ .RespName }}({{ .Arg }})
+{{ end }}{{ end }}	}
+	return ctx.{{ (index .Views 0).RespName }}({{ (index .Views 0).Arg }})
+}
 `
 
 	// ctxTRespT generates the response helpers for responses with overridden types.
@@ -616,7 +1399,8 @@ func (ctx *{{ .Context.Name }}) {{ goify .RespName true }}(r {{ gotyperef .Proje
 	ctxTRespT = `// {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .Type nil 0 false }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ .ContentType }}")
-	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ with .Response.CacheControl }}	ctx.ResponseData.Header().Set("Cache-Control", {{ printf "%q" . }})
+{{ end }}	return ctx.ResponseData.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
 `
 
@@ -626,20 +1410,38 @@ func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .
 // {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}({{ if .Response.MediaType }}resp []byte{{ end }}) error {
 {{ if .Response.MediaType }}	ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
+{{ end }}{{ with .Response.CacheControl }}	ctx.ResponseData.Header().Set("Cache-Control", {{ printf "%q" . }})
 {{ end }}	ctx.ResponseData.WriteHeader({{ .Response.Status }}){{ if .Response.MediaType }}
 	_, err := ctx.ResponseData.Write(resp)
 	return err{{ else }}
 	return nil{{ end }}
 }
+`
+
+	// ctxRespondT generates the response helper that reads the status code from the response body.
+	// template input: map[string]interface{}
+	ctxRespondT = `
+// Respond sends a HTTP response with the status code given by the {{ .Field }} field of r.
+// The status code defaults to {{ (index .Responses 0).Status }} if the field value is not the status code of one of the
+// action responses.
+func (ctx *{{ .Context.Name }}) Respond(r {{ gotyperef .Projected .Projected.AllRequired 0 false }}) error {
+	if r != nil{{ if .Pointer }} && r.{{ .Field }} != nil{{ end }} {
+		switch {{ if .Pointer }}*{{ end }}r.{{ .Field }} {
+{{ range .Responses }}		case {{ .Status }}:
+			return ctx.{{ goify .Name true }}(r)
+{{ end }}		}
+	}
+	return ctx.{{ goify (index .Responses 0).Name true }}(r)
+}
 `
 
 	// payloadT generates the payload type definition GoGenerator
 	// template input: *ContextTemplateData
 	payloadT = `{{ $payload := .Payload }}{{ if .Payload.IsObject }}// {{ gotypename .Payload nil 0 true }} is the {{ .ResourceName }} {{ .ActionName }} action payload.{{/*
 */}}{{ $privateTypeName := gotypename .Payload nil 1 true }}
-type {{ $privateTypeName }} {{ gotypedef .Payload 0 true true }}
+type {{ $privateTypeName }} {{ gotypedef .Payload 0 true true }}{{ singleValueDecoder .Payload.AttributeDefinition $privateTypeName "payload" }}
 
-{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}// Finalize sets the default values defined in the design.
+{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}// Finalize sets the default values and applies the transforms defined in the design.
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Finalize() {
 {{ $assignment }}
 }{{ end }}
@@ -657,14 +1459,28 @@ func (payload {{ gotyperef .Payload .Payload.AllRequired 0 true }}) Publicize()
 	return &pub
 }{{ end }}
 
-// {{ gotypename .Payload nil 0 false }} is the {{ .ResourceName }} {{ .ActionName }} action payload.
-type {{ gotypename .Payload nil 1 false }} {{ gotypedef .Payload 0 true false }}
-
+// {{ gotypename .Payload nil 0 false }} is the {{ .ResourceName }} {{ .ActionName }} action payload.{{ $presence := trackPresence .Payload }}{{ $raw := keepRawBody .Payload }}{{ $variants := payloadVariants .Payload }}
+type {{ gotypename .Payload nil 1 false }} {{ if or $presence $raw $variants }}struct {
+{{ gotypefields .Payload 0 true false }}{{ if $raw }}	// RawBody contains the exact bytes of the request body the payload was decoded from.
+	RawBody []byte ` + "`" + `form:"-" json:"-" xml:"-"` + "`" + `
+{{ end }}{{ if $variants }}	// Variant contains the request body decoded into the type selected by the value of {{ goify (discriminator .Payload) true }}:
+{{ range $i, $v := $variants }}	// {{ gotyperef $v.Type $v.Type.AllRequired 0 false }} if {{ printf "%q" $v.Value }}{{ if eq (add $i 1) (len $variants) }}.{{ else }},{{ end }}
+{{ end }}	Variant interface{} ` + "`" + `form:"-" json:"-" xml:"-"` + "`" + `
+{{ end }}{{ if $presence }}	// fields lists the names of the fields present in the request body.
+	fields map[string]bool
+{{ end }}}{{ else }}{{ gotypedef .Payload 0 true false }}{{ end }}
+{{ if $presence }}
+// IsSet returns true if the field with the given name was present in the request body, including
+// when its value is null. The name is the name of the attribute in the design.
+func (payload {{ gotyperef .Payload .Payload.AllRequired 0 false }}) IsSet(name string) bool {
+	return payload.fields[name]
+}
+{{ end }}
 {{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}// Validate runs the validation rules defined in the design.
 func (payload {{ gotyperef .Payload .Payload.AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
-}{{ end }}
+}{{ end }}{{ validatedConstructor .Payload.AttributeDefinition (gotypename .Payload nil 0 false) (ne $validation "") }}
 `
 	// ctrlT generates the controller interface for a given resource.
 	// template input: *ControllerTemplateData
@@ -700,14 +1516,36 @@ func initService(service *goa.Service) {
 	// template input: *ControllerTemplateData
 	mountT = `
 // Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given service.
-func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
+// The optional prefix is prepended to the path of each route so that the same controller may be
+// mounted multiple times under different paths. Wildcards in the prefix are matched but their
+// values are not loaded into the action contexts. The prefix is not reflected in the Swagger
+// specification basePath. Actions rejected by the service MountFilter are not mounted.
+func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller, prefix ...string) {
 	initService(service)
 	var h goa.Handler
+	var p string
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
 {{ $res := .Resource }}{{ if .Origins }}{{ range .PreflightPaths }}{{/*
-*/}}	service.Mux.Handle("OPTIONS", {{ printf "%q" . }}, ctrl.MuxHandler("preflight", handle{{ $res }}Origin(cors.HandlePreflight()), nil))
+*/}}	service.Mux.Handle("OPTIONS", p+{{ printf "%q" . }}, ctrl.MuxHandler("preflight", handle{{ $res }}Origin(cors.HandlePreflight()), nil))
 {{ end }}{{ end }}{{ range .Actions }}{{ $action := . }}
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
-		// Check if there was an error loading the request
+{{ if .Deprecated }}		// Warn clients that the action is deprecated
+		rw.Header().Set("Warning", "299 - \"Deprecated\"")
+{{ end }}{{ if .AggregateErrors }}		// Build the context and collect the errors of the request and of the params
+		rctx, err := New{{ .Context }}(ctx, req, service)
+		if reqErr := goa.ContextError(ctx); reqErr != nil {
+			// Only bad requests are merged, other errors such as a too large body keep their status
+			if e, ok := reqErr.(goa.ServiceError); !ok || e.ResponseStatus() != http.StatusBadRequest {
+				return reqErr
+			}
+			err = goa.MergeErrors(reqErr, err)
+		}
+		if err != nil {
+			return err
+		}
+{{ else }}		// Check if there was an error loading the request
 		if err := goa.ContextError(ctx); err != nil {
 			return err
 		}
@@ -716,7 +1554,7 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 		if err != nil {
 			return err
 		}
-{{ if .Payload }}		// Build the payload
+{{ end }}{{ if .Payload }}		// Build the payload
 		if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
 			rctx.Payload = rawPayload.({{ gotyperef .Payload nil 1 false }})
 {{ if not .PayloadOptional }}		} else {
@@ -726,14 +1564,16 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 	}
 {{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
-{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
-	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
-{{ end }}{{ end }}{{ range .FileServers }}
+{{ end }}	if service.Mounts({{ printf "%q" .Endpoint }}) {
+{{ range .Routes }}		service.Mux.Handle("{{ .Verb }}", p+{{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.DesignName }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
+		service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s " .Verb) }}+p+{{ printf "%q" .FullPath }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}	}
+{{ end }}{{ range .FileServers }}
 	h = ctrl.FileHandler({{ printf "%q" .RequestPath }}, {{ printf "%q" .FilePath }})
 {{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
 {{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
-{{ end }}	service.Mux.Handle("GET", "{{ .RequestPath }}", ctrl.MuxHandler("serve", h, nil))
-	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", {{ printf "%q" (printf "GET %s" .RequestPath) }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
+{{ end }}	service.Mux.Handle("GET", p+"{{ .RequestPath }}", ctrl.MuxHandler("serve", h, nil))
+	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "files", {{ printf "%q" .FilePath }}, "route", "GET "+p+{{ printf "%q" .RequestPath }}{{ with .Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
 {{ end }}}
 `
 
@@ -767,28 +1607,129 @@ func handle{{ .Resource }}Origin(h goa.Handler) goa.Handler {
 		return h(ctx, rw, req)
 	}
 }
+`
+
+	// validateEndpointPayloadT generates the ValidateEndpointPayload function.
+	// template input: []map[string]interface{}
+	validateEndpointPayloadT = `// ValidateEndpointPayload runs the validations defined in the design on the payload of the
+// action identified by endpointName. The endpoint name is made of the resource and action design
+// names separated with a dot, e.g. "bottle.create". ValidateEndpointPayload makes it possible to
+// validate payloads in generic code such as middleware without knowing the concrete payload types.
+func ValidateEndpointPayload(endpointName string, payload interface{}) error {
+	switch endpointName {
+{{ range . }}	case {{ printf "%q" .Endpoint }}:
+{{ if hasValidate .Payload }}		p, ok := payload.({{ gotyperef .Payload .Payload.AllRequired 0 false }})
+		if !ok {
+			return fmt.Errorf("invalid payload type %T for endpoint %q", payload, endpointName)
+		}
+		return p.Validate()
+{{ else }}		if _, ok := payload.({{ gotyperef .Payload .Payload.AllRequired 0 false }}); !ok {
+			return fmt.Errorf("invalid payload type %T for endpoint %q", payload, endpointName)
+		}
+		return nil
+{{ end }}{{ end }}	}
+	return fmt.Errorf("unknown endpoint %q", endpointName)
+}
+`
+
+	// mapServiceErrorT generates the function that maps errors to HTTP responses.
+	// template input: []int
+	mapServiceErrorT = `
+// MapServiceError returns the HTTP status code and body of the response corresponding to err.
+// Errors created with goa error classes keep their status code if it is below 500, if it is 500 or
+// if it is one of the server error status codes declared in the design, other errors produce
+// internal errors. MapServiceError can be used with the middleware.ErrorMappingHandler middleware.
+func MapServiceError(err error) (int, interface{}) {
+	if e, ok := err.(goa.ServiceError); ok {
+		status := e.ResponseStatus()
+		if status < http.StatusInternalServerError {
+			return status, e
+		}
+		switch status {
+		case {{ range $i, $s := . }}{{ if $i }}, {{ end }}{{ $s }}{{ end }}:
+			return status, e
+		}
+	}
+	return http.StatusInternalServerError, goa.ErrInternal(err)
+}
 `
 
 	// unmarshalT generates the code for an action payload unmarshal function.
 	// template input: *ControllerTemplateData
 	unmarshalT = `{{ range .Actions }}{{ if .Payload }}
 // {{ .Unmarshal }} unmarshals the request body into the context request data Payload field.
-func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Request) error {
-	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
-	if err := service.DecodeRequest(req, payload); err != nil {
+func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Request) {{ if .MaxBodySize }}(err error){{ else }}error{{ end }} {
+{{ if .MaxBodySize }}	if req.ContentLength > {{ .MaxBodySize }} {
+		return goa.ErrRequestBodyTooLarge("request body length exceeds {{ .MaxBodySize }} bytes")
+	}
+	// The length of chunked bodies is unknown (-1), stop reading them past the limit
+	req.Body = http.MaxBytesReader(goa.ContextResponse(ctx), req.Body, {{ .MaxBodySize }})
+	defer func() {
+		if err != nil && strings.HasSuffix(err.Error(), "http: request body too large") {
+			err = goa.ErrRequestBodyTooLarge("request body length exceeds {{ .MaxBodySize }} bytes")
+		}
+	}()
+{{ end }}{{ $presence := trackPresence .Payload }}{{ $raw := keepRawBody .Payload }}{{ $variants := payloadVariants .Payload }}{{ if or $presence $raw $variants }}	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+{{ end }}{{ if $presence }}	var fields map[string]interface{}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err := service.DecodeRequest(req, &fields); err != nil {
+		return err
+	}
+{{ end }}{{ if or $presence $raw $variants }}	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+{{ end }}	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	if null, err := service.DecodeNullableRequest(req, payload); err != nil || null {
+		// A null body is handled as a missing payload
 		return err
 	}{{ $assignment := finalizeCode .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
 	payload.Finalize(){{ end }}{{ else }}var payload {{ gotypename .Payload nil 1 false }}
 	if err := service.DecodeRequest(req, &payload); err != nil {
 		return err
-	}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}
+	}{{ if or .Payload.IsArray .Payload.IsHash }}
+	if payload == nil {
+		// A null body is handled as a missing payload
+		return nil
+	}{{ end }}{{ end }}{{ $validation := validationCode .Payload.AttributeDefinition false false false "payload" "raw" 1 false }}{{ if $validation }}
 	if err := payload.Validate(); err != nil {
 		// Initialize payload with private data structure so it can be logged
 		goa.ContextRequest(ctx).Payload = payload
 		return err
 	}{{ end }}
-	goa.ContextRequest(ctx).Payload = payload{{ if .Payload.IsObject }}.Publicize(){{ end }}
-	return nil
+{{ if $variants }}{{ $disc := discriminator .Payload }}	// Decode the request body again into the variant selected by the discriminator
+	var variant interface{}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	switch *payload.{{ goify $disc true }} {
+{{ range $variants }}	case {{ printf "%q" .Value }}:
+		v := &{{ gotypename .Type .Type.AllRequired 0 true }}{}
+		if err := service.DecodeRequest(req, &v); err != nil {
+			return err
+		}{{ if finalizeCode .Type.AttributeDefinition "ut" 1 }}
+		v.Finalize(){{ end }}{{ if validationCode .Type.AttributeDefinition false false false "ut" "response" 1 true }}
+		if err := v.Validate(); err != nil {
+			return err
+		}{{ end }}
+		variant = v.Publicize()
+{{ end }}	default:
+		return goa.InvalidEnumValueError(` + "`" + `raw.{{ $disc }}` + "`" + `, *payload.{{ goify $disc true }}, []interface{}{{ "{" }}{{ range $i, $v := $variants }}{{ if $i }}, {{ end }}{{ printf "%q" $v.Value }}{{ end }}})
+	}
+{{ end }}{{ $hook := and .Payload.IsObject (not $validation) }}{{ if or $presence $raw $variants $hook }}	pub := payload.Publicize()
+{{ if $presence }}	pub.fields = make(map[string]bool, len(fields))
+	for n := range fields {
+		pub.fields[n] = true
+	}
+{{ end }}{{ if $raw }}	pub.RawBody = body
+{{ end }}{{ if $variants }}	pub.Variant = variant
+{{ end }}	goa.ContextRequest(ctx).Payload = pub
+{{ if $hook }}	// Run the validations of the Validate method hand-written for the payload type, if any
+	if v, ok := interface{}(pub).(interface {
+		Validate() error
+	}); ok {
+		return v.Validate()
+	}
+{{ end }}{{ else }}	goa.ContextRequest(ctx).Payload = payload{{ if .Payload.IsObject }}.Publicize(){{ end }}
+{{ end }}	return nil
 }
 {{ end }}
 {{ end }}`
@@ -815,8 +1756,7 @@ func (mt {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
 }
-{{ end }}
-`
+{{ end }}{{ validatedConstructor .AttributeDefinition $typeName (ne $validation "") }}`
 
 	// mediaTypeLinkT generates the code for a media type link.
 	// template input: MediaTypeLinkTemplateData
@@ -832,8 +1772,8 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 	// userTypeT generates the code for a user type.
 	// template input: UserTypeTemplateData
 	userTypeT = `// {{ gotypedesc . false }}{{ $privateTypeName := gotypename . .AllRequired 0 true }}
-type {{ $privateTypeName }} {{ gotypedef . 0 true true }}
-{{ $assignment := finalizeCode .AttributeDefinition "ut" 1 }}{{ if $assignment }}// Finalize sets the default values for {{$privateTypeName}} type instance.
+type {{ $privateTypeName }} {{ gotypedef . 0 true true }}{{ singleValueDecoder .AttributeDefinition $privateTypeName "ut" }}
+{{ $assignment := finalizeCode .AttributeDefinition "ut" 1 }}{{ if $assignment }}// Finalize sets the default values and applies the transforms for {{$privateTypeName}} type instance.
 func (ut {{ gotyperef . .AllRequired 0 true }}) Finalize() {
 {{ $assignment }}
 }{{ end }}
@@ -856,9 +1796,49 @@ type {{ $typeName }} {{ gotypedef . 0 true false }}
 func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return
-}{{ end }}
+}{{ end }}{{ validatedConstructor .AttributeDefinition $typeName (ne $validation "") }}
 `
 
+	// validatedCtorT generates the constructor of a public type that runs the validations.
+	// template input: map[string]interface{}
+	validatedCtorT = `
+
+// NewValidated{{ .TypeName }} creates a {{ .TypeName }} initialized with the given values of the
+// required attributes and the default values defined in the design{{ if .Validates }} then runs the
+// validations defined in the design{{ end }}.
+func NewValidated{{ .TypeName }}({{ range $i, $f := .Args }}{{ if $i }}, {{ end }}{{ $f.VarName }} {{ $f.TypeRef }}{{ end }}) (*{{ .TypeName }}, error) {
+	{{ .VarName }} := &{{ .TypeName }}{
+{{ range .Fields }}		{{ .FieldName }}: {{ .Value }},
+{{ end }}	}
+{{ if .Validates }}	if err := {{ .VarName }}.Validate(); err != nil {
+		return nil, err
+	}
+{{ end }}	return {{ .VarName }}, nil
+}`
+
+	// singleValueT generates the UnmarshalJSON method of a private type with array fields that
+	// accept a single value.
+	// template input: map[string]interface{}
+	singleValueT = `
+
+// UnmarshalJSON decodes the JSON representation of {{ .TypeName }}. A single value given in place
+// of an array for the {{ range $i, $f := .Fields }}{{ if $i }}, {{ end }}{{ $f.JSONName }}{{ end }} field{{ if gt (len .Fields) 1 }}s{{ end }} is decoded into a slice with one element.
+func ({{ .Receiver }} *{{ .TypeName }}) UnmarshalJSON(data []byte) error {
+	type alias {{ .TypeName }}
+	var raw struct {
+		alias
+{{ range .Fields }}		{{ .FieldName }} json.RawMessage ` + "`" + `json:"{{ .JSONName }}"` + "`" + `
+{{ end }}	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*{{ .Receiver }} = {{ .TypeName }}(raw.alias)
+{{ range .Fields }}	if err := goa.UnmarshalSingleValue(raw.{{ .FieldName }}, &{{ $.Receiver }}.{{ .FieldName }}); err != nil {
+		return err
+	}
+{{ end }}	return nil
+}`
+
 	// securitySchemesT generates the code for the security module.
 	// template input: []*design.SecuritySchemeDefinition
 	securitySchemesT = `
@@ -3,6 +3,7 @@ package genapp_test
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/design/apidsl"
@@ -92,6 +93,44 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with an idempotency key", func() {
+				var idempotencyKey string
+
+				JustBeforeEach(func() {
+					data.IdempotencyKey = idempotencyKey
+				})
+
+				Context("that is optional", func() {
+					BeforeEach(func() {
+						idempotencyKey = "optional"
+					})
+
+					It("stores the header value in the context if present", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(optionalIdempotencyKeyContextFactory))
+					})
+				})
+
+				Context("that is required", func() {
+					BeforeEach(func() {
+						idempotencyKey = "required"
+					})
+
+					It("reports a missing header error if absent", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(requiredIdempotencyKeyContextFactory))
+					})
+				})
+			})
+
 			Context("with a media type setting a ContentType", func() {
 				var contentType = "application/json"
 
@@ -134,6 +173,258 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with multiple responses and no result", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK":       {Name: "OK", Status: 200},
+						"Accepted": {Name: "Accepted", Status: 202},
+					}
+				})
+
+				It("writes status only response helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(emptyOKResp))
+					Ω(written).Should(ContainSubstring(emptyAcceptedResp))
+					Ω(written).ShouldNot(ContainSubstring("Write("))
+				})
+			})
+
+			Context("with a response defining a cache policy", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK":       {Name: "OK", Status: 200, CacheControl: "public, max-age=60"},
+						"Accepted": {Name: "Accepted", Status: 202},
+					}
+				})
+
+				It("sets the Cache-Control header of that response only", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(cacheControlOKResp))
+					Ω(written).Should(ContainSubstring(emptyAcceptedResp))
+				})
+			})
+
+			Context("with error responses", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(design.ErrorMedia.Identifier): design.ErrorMedia,
+					}
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"BadRequest": {Name: "BadRequest", Status: 400, MediaType: design.ErrorMedia.Identifier},
+						"Conflict": {
+							Name:      "Conflict",
+							Status:    409,
+							MediaType: design.ErrorMedia.Identifier,
+							Metadata:  dslengine.MetadataDefinition{"error:code": {"already_exists"}},
+						},
+					}
+				})
+
+				It("sends errors with a code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(badRequestErrorResp))
+					Ω(written).Should(ContainSubstring(`r = goa.NewErrorClass("already_exists", 409)(r)`))
+				})
+			})
+
+			Context("with a media type holding the response status code", func() {
+				BeforeEach(func() {
+					mediaType := &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"foo": {Type: design.String},
+									"status": {
+										Type:     design.Integer,
+										Metadata: dslengine.MetadataDefinition{"http:status": nil},
+									},
+								},
+							},
+							TypeName: "Status",
+						},
+						Identifier: "application/vnd.goa.status",
+					}
+					defView := &design.ViewDefinition{
+						AttributeDefinition: mediaType.AttributeDefinition,
+						Name:                "default",
+						Parent:              mediaType,
+					}
+					mediaType.Views = map[string]*design.ViewDefinition{"default": defView}
+					design.Design = new(design.APIDefinition)
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(mediaType.Identifier): mediaType,
+					}
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:      "OK",
+							Status:    200,
+							MediaType: mediaType.Identifier,
+						},
+						"Created": {
+							Name:      "Created",
+							Status:    201,
+							MediaType: mediaType.Identifier,
+						},
+					}
+				})
+
+				It("writes the Respond helper", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(respondCode))
+				})
+
+				Context("with two status attributes", func() {
+					BeforeEach(func() {
+						mt := design.Design.MediaTypes[design.CanonicalIdentifier("application/vnd.goa.status")]
+						mt.Type.ToObject()["code"] = &design.AttributeDefinition{
+							Type:     design.Integer,
+							Metadata: dslengine.MetadataDefinition{"http:status": nil},
+						}
+					})
+
+					It("uses the first attribute in alphabetical order", func() {
+						for i := 0; i < 10; i++ {
+							err := writer.Execute(data)
+							Ω(err).ShouldNot(HaveOccurred())
+							b, err := ioutil.ReadFile(filename)
+							Ω(err).ShouldNot(HaveOccurred())
+							written := string(b)
+							Ω(written).Should(ContainSubstring("status code given by the Code field of r"))
+							Ω(written).Should(ContainSubstring("switch *r.Code {"))
+						}
+					})
+				})
+			})
+
+			Context("with a media type holding pagination links", func() {
+				BeforeEach(func() {
+					mediaType := &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"first": {
+										Type:     design.String,
+										Metadata: dslengine.MetadataDefinition{"http:link": {"first"}},
+									},
+									"next": {
+										Type:     design.String,
+										Metadata: dslengine.MetadataDefinition{"http:link": {"next"}},
+									},
+									"total": {
+										Type:     design.Integer,
+										Metadata: dslengine.MetadataDefinition{"http:link": {"last"}},
+									},
+								},
+								Validation: &dslengine.ValidationDefinition{Required: []string{"first"}},
+							},
+							TypeName: "Page",
+						},
+						Identifier: "application/vnd.goa.page",
+					}
+					defView := &design.ViewDefinition{
+						AttributeDefinition: mediaType.AttributeDefinition,
+						Name:                "default",
+						Parent:              mediaType,
+					}
+					mediaType.Views = map[string]*design.ViewDefinition{"default": defView}
+					design.Design = new(design.APIDefinition)
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(mediaType.Identifier): mediaType,
+					}
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:      "OK",
+							Status:    200,
+							MediaType: mediaType.Identifier,
+						},
+					}
+				})
+
+				It("sets the Link header", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(linkOKResp))
+				})
+			})
+
+			Context("with a response selecting the view at runtime", func() {
+				BeforeEach(func() {
+					mediaType := &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id":   {Type: design.Integer},
+									"name": {Type: design.String},
+								},
+							},
+							TypeName: "Bottle",
+						},
+						Identifier: "application/vnd.goa.bottle",
+					}
+					mediaType.Views = map[string]*design.ViewDefinition{
+						"default": {
+							AttributeDefinition: mediaType.AttributeDefinition,
+							Name:                "default",
+							Parent:              mediaType,
+						},
+						"tiny": {
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"id": {Type: design.Integer}},
+							},
+							Name:   "tiny",
+							Parent: mediaType,
+						},
+					}
+					design.Design = new(design.APIDefinition)
+					design.Design.MediaTypes = map[string]*design.MediaTypeDefinition{
+						design.CanonicalIdentifier(mediaType.Identifier): mediaType,
+					}
+					design.ProjectedMediaTypes = make(map[string]*design.MediaTypeDefinition)
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {
+							Name:      "OK",
+							Status:    200,
+							MediaType: mediaType.Identifier,
+							Metadata:  dslengine.MetadataDefinition{"view:param": {"fields"}},
+						},
+					}
+				})
+
+				It("writes the view selecting response helper", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(viewOKResp))
+				})
+			})
+
 			Context("with a collection media type", func() {
 				BeforeEach(func() {
 					elemType := &design.MediaTypeDefinition{
@@ -178,6 +469,31 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a number param with a minimum", func() {
+				BeforeEach(func() {
+					min := 0.0
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"param": &design.AttributeDefinition{
+								Type:       design.Number,
+								Validation: &dslengine.ValidationDefinition{Minimum: &min},
+							},
+						},
+					}
+				})
+
+				It("rejects NaN and positive infinity", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`if math.IsNaN(*rctx.Param) || math.IsInf(*rctx.Param, 1) {
+				err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`param`" + `, *rctx.Param, 0, true))
+			}`))
+				})
+			})
+
 			Context("with an integer param", func() {
 				var (
 					intParam   *design.AttributeDefinition
@@ -290,75 +606,165 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(written).Should(ContainSubstring(strContextFactory))
 				})
 
-				Context("with a default value", func() {
+				Context("with trim metadata", func() {
 					BeforeEach(func() {
-						strParam.SetDefault("foo")
+						strParam.Metadata = dslengine.MetadataDefinition{"transform:trim": nil}
 					})
 
-					It("writes the string contexts code", func() {
+					It("trims the raw value before using it", func() {
 						err := writer.Execute(data)
 						Ω(err).ShouldNot(HaveOccurred())
 						b, err := ioutil.ReadFile(filename)
 						Ω(err).ShouldNot(HaveOccurred())
 						written := string(b)
 						Ω(written).ShouldNot(BeEmpty())
-						Ω(written).Should(ContainSubstring(strNonOptionalContext))
-						Ω(written).Should(ContainSubstring(strDefaultContextFactory))
+						Ω(written).Should(ContainSubstring(strTrimContextFactory))
 					})
 				})
 
-				Context("with required attribute", func() {
+				Context("with trim metadata and a maximum length", func() {
 					BeforeEach(func() {
-						validation.Required = []string{"param"}
+						maxLength := 5
+						strParam.Metadata = dslengine.MetadataDefinition{"transform:trim": nil}
+						strParam.Validation = &dslengine.ValidationDefinition{MaxLength: &maxLength}
 					})
 
-					It("writes the String contexts code", func() {
+					It("validates the length of the trimmed value", func() {
 						err := writer.Execute(data)
 						Ω(err).ShouldNot(HaveOccurred())
 						b, err := ioutil.ReadFile(filename)
 						Ω(err).ShouldNot(HaveOccurred())
 						written := string(b)
 						Ω(written).ShouldNot(BeEmpty())
-						Ω(written).Should(ContainSubstring(strNonOptionalContext))
-						Ω(written).Should(ContainSubstring(strRequiredContextFactory))
+						Ω(written).Should(ContainSubstring(strTrimMaxLengthContextFactory))
 					})
+				})
 
-					Context("with a default value", func() {
-						BeforeEach(func() {
-							strParam.SetDefault("foo")
-						})
+				Context("with trim and lower metadata", func() {
+					BeforeEach(func() {
+						strParam.Metadata = dslengine.MetadataDefinition{
+							"transform:trim":  nil,
+							"transform:lower": nil,
+						}
+					})
 
-						It("writes the integer contexts code", func() {
-							err := writer.Execute(data)
-							Ω(err).ShouldNot(HaveOccurred())
-							b, err := ioutil.ReadFile(filename)
-							Ω(err).ShouldNot(HaveOccurred())
-							written := string(b)
-							Ω(written).ShouldNot(BeEmpty())
-							Ω(written).Should(ContainSubstring(strNonOptionalContext))
-							Ω(written).Should(ContainSubstring(strDefaultContextFactory))
-						})
+					It("trims then lowercases the raw value before using it", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(strLowerContextFactory))
 					})
 				})
-			})
 
-			Context("with a number param", func() {
-				var (
-					numParam   *design.AttributeDefinition
-					dataType   design.Object
-					validation *dslengine.ValidationDefinition
-				)
+				Context("with a list of sources", func() {
+					BeforeEach(func() {
+						strParam.Metadata = dslengine.MetadataDefinition{
+							"param:sources": {"header:X-Tenant-Id", "query:tenant", "path"},
+						}
+						validation.Required = []string{"param"}
+					})
 
-				BeforeEach(func() {
-					numParam = &design.AttributeDefinition{Type: design.Number}
-					dataType = design.Object{
-						"param": numParam,
-					}
-					validation = &dslengine.ValidationDefinition{}
-					params = &design.AttributeDefinition{
-						Type:       dataType,
-						Validation: validation,
-					}
+					It("reads the value from the first source that defines it", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(strSourcesContextFactory))
+					})
+				})
+
+				Context("with a param validator", func() {
+					BeforeEach(func() {
+						strParam.Metadata = dslengine.MetadataDefinition{
+							"validation:func": {"region"},
+						}
+					})
+
+					It("validates the value with the validator registered in the service", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(strParamValidatorContextFactory))
+					})
+				})
+
+				Context("with a default value", func() {
+					BeforeEach(func() {
+						strParam.SetDefault("foo")
+					})
+
+					It("writes the string contexts code", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(strNonOptionalContext))
+						Ω(written).Should(ContainSubstring(strDefaultContextFactory))
+					})
+				})
+
+				Context("with required attribute", func() {
+					BeforeEach(func() {
+						validation.Required = []string{"param"}
+					})
+
+					It("writes the String contexts code", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(strNonOptionalContext))
+						Ω(written).Should(ContainSubstring(strRequiredContextFactory))
+					})
+
+					Context("with a default value", func() {
+						BeforeEach(func() {
+							strParam.SetDefault("foo")
+						})
+
+						It("writes the integer contexts code", func() {
+							err := writer.Execute(data)
+							Ω(err).ShouldNot(HaveOccurred())
+							b, err := ioutil.ReadFile(filename)
+							Ω(err).ShouldNot(HaveOccurred())
+							written := string(b)
+							Ω(written).ShouldNot(BeEmpty())
+							Ω(written).Should(ContainSubstring(strNonOptionalContext))
+							Ω(written).Should(ContainSubstring(strDefaultContextFactory))
+						})
+					})
+				})
+			})
+
+			Context("with a number param", func() {
+				var (
+					numParam   *design.AttributeDefinition
+					dataType   design.Object
+					validation *dslengine.ValidationDefinition
+				)
+
+				BeforeEach(func() {
+					numParam = &design.AttributeDefinition{Type: design.Number}
+					dataType = design.Object{
+						"param": numParam,
+					}
+					validation = &dslengine.ValidationDefinition{}
+					params = &design.AttributeDefinition{
+						Type:       dataType,
+						Validation: validation,
+					}
 				})
 
 				It("writes the number contexts code", func() {
@@ -454,6 +860,38 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(written).Should(ContainSubstring(boolContextFactory))
 				})
 
+				Context("with custom true and false strings", func() {
+					BeforeEach(func() {
+						boolParam.Metadata = dslengine.MetadataDefinition{"param:bool-values": {"on", "off"}}
+					})
+
+					It("compares the raw value with the strings", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(boolValuesContextFactory))
+					})
+				})
+
+				Context("with flag metadata", func() {
+					BeforeEach(func() {
+						boolParam.Metadata = dslengine.MetadataDefinition{"param:flag": nil}
+					})
+
+					It("handles an empty value as true", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(boolFlagContextFactory))
+					})
+				})
+
 				Context("with a default value", func() {
 					BeforeEach(func() {
 						boolParam.SetDefault(true)
@@ -536,6 +974,22 @@ var _ = Describe("ContextsWriter", func() {
 					Ω(written).Should(ContainSubstring(arrayContextFactory))
 				})
 
+				Context("with trim metadata", func() {
+					BeforeEach(func() {
+						arrayParam.Metadata = dslengine.MetadataDefinition{"transform:trim": nil}
+					})
+
+					It("trims each raw value before using it", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(BeEmpty())
+						Ω(written).Should(ContainSubstring(arrayTrimContextFactory))
+					})
+				})
+
 				Context("using a path param", func() {
 					BeforeEach(func() {
 						route := &design.RouteDefinition{Path: "/:param"}
@@ -737,6 +1191,46 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with required one of params", func() {
+				BeforeEach(func() {
+					dataType := design.Object{
+						"name":  &design.AttributeDefinition{Type: design.String},
+						"email": &design.AttributeDefinition{Type: design.String},
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+						Validation: &dslengine.ValidationDefinition{
+							RequiredOneOf: [][]string{{"name", "email"}},
+						},
+					}
+				})
+
+				It("checks that at least one of the params is present", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(requiredOneOfContextFactory))
+				})
+
+				Context("including a param with a default value", func() {
+					BeforeEach(func() {
+						params.Type.(design.Object)["email"].DefaultValue = "a@b.c"
+					})
+
+					It("does not check the group", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).ShouldNot(ContainSubstring("MissingOneOfParamsError"))
+					})
+				})
+			})
+
 			Context("with a custom name param", func() {
 				BeforeEach(func() {
 					intParam := &design.AttributeDefinition{
@@ -814,109 +1308,479 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
-			Context("with a simple payload", func() {
+			Context("with a query param sharing the name of a path param", func() {
 				BeforeEach(func() {
-					design.Design = new(design.APIDefinition)
-					payload = &design.UserTypeDefinition{
-						AttributeDefinition: &design.AttributeDefinition{Type: design.String},
-						TypeName:            "ListBottlePayload",
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"id": &design.AttributeDefinition{Type: design.Integer},
+							"idFilter": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"param:query": {"id"}},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"id"}},
 					}
 				})
 
-				It("writes the contexts code", func() {
+				It("reads the query param from the query string", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(payloadContext))
-					Ω(written).Should(ContainSubstring(payloadContextFactory))
+					Ω(written).Should(ContainSubstring(queryPathParamContextFactory))
 				})
 			})
 
-			Context("with a object payload", func() {
+			Context("with a param with aliases", func() {
 				BeforeEach(func() {
-					design.Design = new(design.APIDefinition)
-					intParam := &design.AttributeDefinition{Type: design.Integer}
-					strParam := &design.AttributeDefinition{Type: design.String}
-					dataType := design.Object{
-						"int": intParam,
-						"str": strParam,
-					}
-					required := &dslengine.ValidationDefinition{
-						Required: []string{"int"},
-					}
-					payload = &design.UserTypeDefinition{
-						AttributeDefinition: &design.AttributeDefinition{
-							Type:       dataType,
-							Validation: required,
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"query": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"param:alias": {"q", "search"}},
+							},
 						},
-						TypeName: "ListBottlePayload",
+						Validation: &dslengine.ValidationDefinition{Required: []string{"query"}},
 					}
 				})
 
-				It("writes the contexts code", func() {
+				It("reads the param from the aliases", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(payloadObjContext))
+					Ω(written).Should(ContainSubstring(aliasParamContextFactory))
 				})
+			})
 
-				var _ = Describe("IterateResponses", func() {
-					var resps []*design.ResponseDefinition
-					var testIt = func(r *design.ResponseDefinition) error {
-						resps = append(resps, r)
-						return nil
+			Context("with an array param accepting JSON", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"ids": &design.AttributeDefinition{
+								Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+								Metadata: dslengine.MetadataDefinition{"param:json": nil},
+							},
+						},
 					}
-					Context("with responses", func() {
-						BeforeEach(func() {
-							responses = map[string]*design.ResponseDefinition{
-								"OK":      {Status: 200},
-								"Created": {Status: 201},
-							}
-						})
-						It("iterates responses in order", func() {
-							data.IterateResponses(testIt)
-							Ω(resps).Should(Equal([]*design.ResponseDefinition{
-								responses["OK"],
-								responses["Created"],
-							}))
-						})
-					})
 				})
-			})
-		})
-	})
-})
 
-var _ = Describe("ControllersWriter", func() {
-	var writer *genapp.ControllersWriter
-	var workspace *codegen.Workspace
-	var filename string
+				It("splits the param value before coercing the elements", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(jsonArrayParamContextFactory))
+				})
+			})
 
-	BeforeEach(func() {
-		var err error
-		workspace, err = codegen.NewWorkspace("test")
-		Ω(err).ShouldNot(HaveOccurred())
-		pkg, err := workspace.NewPackage("controllers")
-		Ω(err).ShouldNot(HaveOccurred())
-		src := pkg.CreateSourceFile("test.go")
-		filename = src.Abs()
-	})
+			Context("with params read from the basic auth credentials", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"pass": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"param:basic-auth": []string{"password"}},
+							},
+							"user": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"param:basic-auth": []string{"username"}},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"user"}},
+					}
+				})
 
-	JustBeforeEach(func() {
-		var err error
-		writer, err = genapp.NewControllersWriter(filename)
-		Ω(err).ShouldNot(HaveOccurred())
-	})
+				It("reads the params from the request credentials", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(basicAuthParamContextFactory))
+				})
+			})
 
-	AfterEach(func() {
-		workspace.Delete()
-	})
+			Context("with required params read from the basic auth credentials", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"pass": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"param:basic-auth": []string{"password"}},
+							},
+							"user": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"param:basic-auth": []string{"username"}},
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"user", "pass"}},
+					}
+				})
+
+				It("reports the missing credentials once", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(strings.Count(written, "goa.ErrUnauthorized(")).Should(Equal(1))
+					Ω(written).Should(ContainSubstring(requiredBasicAuthParamContextFactory))
+				})
+			})
+
+			Context("with a param mapping wire values", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"status": &design.AttributeDefinition{
+								Type:     design.Integer,
+								Metadata: dslengine.MetadataDefinition{"param:enum-map": {"inactive=0", "active=1"}},
+							},
+						},
+					}
+				})
+
+				It("maps the wire value before coercing it", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(enumMapParamContextFactory))
+				})
+			})
+
+			Context("with matrix params", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"item": &design.AttributeDefinition{Type: design.String},
+							"size": &design.AttributeDefinition{
+								Type:     design.Integer,
+								Metadata: dslengine.MetadataDefinition{"param:matrix": {"item"}},
+							},
+						},
+					}
+				})
+
+				It("reads the params from the path param value", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(matrixParamContextFactory))
+				})
+			})
+
+			Context("with big number params", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"amount": &design.AttributeDefinition{
+								Type:     design.String,
+								Metadata: dslengine.MetadataDefinition{"param:bignum": nil},
+							},
+							"ratios": &design.AttributeDefinition{
+								Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+								Metadata: dslengine.MetadataDefinition{"param:bignum": {"rat"}},
+							},
+						},
+					}
+				})
+
+				It("parses the params into math/big numbers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(bigNumContext))
+					Ω(written).Should(ContainSubstring(bigNumContextFactory))
+				})
+			})
+
+			Context("with date params", func() {
+				BeforeEach(func() {
+					date := &dslengine.ValidationDefinition{Format: "date"}
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"day": &design.AttributeDefinition{
+								Type:       design.String,
+								Validation: date,
+							},
+							"days": &design.AttributeDefinition{
+								Type: &design.Array{ElemType: &design.AttributeDefinition{
+									Type:       design.String,
+									Validation: date,
+								}},
+							},
+						},
+					}
+				})
+
+				It("parses the params into time values", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(dateContext))
+					Ω(written).Should(ContainSubstring(dateContextFactory))
+				})
+			})
+
+			Context("with date time params", func() {
+				BeforeEach(func() {
+					params = &design.AttributeDefinition{
+						Type: design.Object{
+							"expiresAt": &design.AttributeDefinition{
+								Type: design.DateTime,
+							},
+							"since": &design.AttributeDefinition{
+								Type: &design.Array{ElemType: &design.AttributeDefinition{
+									Type: design.DateTime,
+								}},
+							},
+						},
+					}
+				})
+
+				It("parses the params with the RFC3339 layout", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(dateTimeContext))
+					Ω(written).Should(ContainSubstring(dateTimeContextFactory))
+				})
+			})
+
+			Context("with a simple payload", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					payload = &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{Type: design.String},
+						TypeName:            "ListBottlePayload",
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(payloadContext))
+					Ω(written).Should(ContainSubstring(payloadContextFactory))
+				})
+			})
+
+			Context("with a object payload", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					intParam := &design.AttributeDefinition{Type: design.Integer}
+					strParam := &design.AttributeDefinition{Type: design.String}
+					dataType := design.Object{
+						"int": intParam,
+						"str": strParam,
+					}
+					required := &dslengine.ValidationDefinition{
+						Required: []string{"int"},
+					}
+					payload = &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type:       dataType,
+							Validation: required,
+						},
+						TypeName: "ListBottlePayload",
+					}
+				})
+
+				It("writes the contexts code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(payloadObjContext))
+				})
+
+				It("writes the typed payload accessor", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadAccessor))
+				})
+
+				Context("with presence tracking", func() {
+					BeforeEach(func() {
+						payload.Metadata = dslengine.MetadataDefinition{"payload:presence": nil}
+					})
+
+					It("records the fields present in the request body", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(payloadPresenceContext))
+					})
+				})
+
+				Context("with raw body", func() {
+					BeforeEach(func() {
+						payload.Metadata = dslengine.MetadataDefinition{"payload:raw": nil}
+					})
+
+					It("adds the RawBody field", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(payloadRawContext))
+					})
+				})
+
+				Context("with an array field accepting a single value", func() {
+					BeforeEach(func() {
+						payload.Type.ToObject()["tags"] = &design.AttributeDefinition{
+							Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+							Metadata: dslengine.MetadataDefinition{"json:single-value": nil},
+						}
+					})
+
+					It("generates an UnmarshalJSON method wrapping single values", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(payloadSingleValueContext))
+					})
+				})
+
+				Context("with a trimmed field with a maximum length", func() {
+					BeforeEach(func() {
+						maxLength := 5
+						str := payload.Type.ToObject()["str"]
+						str.Validation = &dslengine.ValidationDefinition{MaxLength: &maxLength}
+						str.Metadata = dslengine.MetadataDefinition{"transform:trim": nil}
+					})
+
+					It("trims the field before validating its length", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(payloadTrimMaxLengthContext))
+					})
+				})
+
+				Context("with a lowercased email field", func() {
+					BeforeEach(func() {
+						str := payload.Type.ToObject()["str"]
+						str.Validation = &dslengine.ValidationDefinition{Format: "email"}
+						str.Metadata = dslengine.MetadataDefinition{"transform:lower": nil}
+					})
+
+					It("lowercases the field before validating it", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(payloadTransformContext))
+					})
+				})
+
+				Context("with variants", func() {
+					BeforeEach(func() {
+						dog := &design.UserTypeDefinition{
+							TypeName: "Dog",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"str": &design.AttributeDefinition{Type: design.String}},
+							},
+						}
+						design.Design.Types = map[string]*design.UserTypeDefinition{"Dog": dog}
+						payload.Metadata = dslengine.MetadataDefinition{
+							"payload:discriminator": []string{"str"},
+							"payload:variants":      []string{"dog=Dog"},
+						}
+					})
+
+					It("adds the Variant field", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(payloadVariantContext))
+					})
+				})
+
+				var _ = Describe("IterateResponses", func() {
+					var resps []*design.ResponseDefinition
+					var testIt = func(r *design.ResponseDefinition) error {
+						resps = append(resps, r)
+						return nil
+					}
+					Context("with responses", func() {
+						BeforeEach(func() {
+							responses = map[string]*design.ResponseDefinition{
+								"OK":      {Status: 200},
+								"Created": {Status: 201},
+							}
+						})
+						It("iterates responses in order", func() {
+							data.IterateResponses(testIt)
+							Ω(resps).Should(Equal([]*design.ResponseDefinition{
+								responses["OK"],
+								responses["Created"],
+							}))
+						})
+					})
+				})
+			})
+		})
+	})
+})
+
+var _ = Describe("ControllersWriter", func() {
+	var writer *genapp.ControllersWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("controllers")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewControllersWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
 
 	Context("correctly configured", func() {
 		BeforeEach(func() {
@@ -994,123 +1858,405 @@ var _ = Describe("ControllersWriter", func() {
 			var payloads []*design.UserTypeDefinition
 			var encoders, decoders []*genapp.EncoderTemplateData
 			var origins []*design.CORSDefinition
+			var deprecated, aggregate bool
+			var maxBodySize int64
 
 			var data []*genapp.ControllerTemplateData
 
-			BeforeEach(func() {
-				actions = nil
-				verbs = nil
-				paths = nil
-				contexts = nil
-				unmarshals = nil
-				payloads = nil
-				encoders = nil
-				decoders = nil
-				origins = nil
+			BeforeEach(func() {
+				actions = nil
+				verbs = nil
+				paths = nil
+				contexts = nil
+				unmarshals = nil
+				payloads = nil
+				encoders = nil
+				decoders = nil
+				origins = nil
+				deprecated = false
+				aggregate = false
+				maxBodySize = 0
+			})
+
+			JustBeforeEach(func() {
+				codegen.TempCount = 0
+				api := &design.APIDefinition{}
+				d := &genapp.ControllerTemplateData{
+					Resource: "Bottles",
+					Origins:  origins,
+				}
+				as := make([]map[string]interface{}, len(actions))
+				for i, a := range actions {
+					var unmarshal string
+					var payload *design.UserTypeDefinition
+					if i < len(unmarshals) {
+						unmarshal = unmarshals[i]
+					}
+					if i < len(payloads) {
+						payload = payloads[i]
+					}
+					as[i] = map[string]interface{}{
+						"Name":       codegen.Goify(a, true),
+						"DesignName": a,
+						"Endpoint":   "bottles." + a,
+						"Routes": []*design.RouteDefinition{
+							{
+								Verb: verbs[i],
+								Path: paths[i],
+							}},
+						"Context":         contexts[i],
+						"Unmarshal":       unmarshal,
+						"Payload":         payload,
+						"Deprecated":      deprecated,
+						"AggregateErrors": aggregate,
+						"MaxBodySize":     maxBodySize,
+					}
+				}
+				if len(as) > 0 {
+					d.API = api
+					d.Actions = as
+					d.Encoders = encoders
+					d.Decoders = decoders
+					data = []*genapp.ControllerTemplateData{d}
+				} else {
+					data = nil
+				}
+			})
+
+			Context("with missing data", func() {
+				It("returns an empty string", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(BeEmpty())
+				})
+			})
+
+			Context("with a simple controller", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("writes the controller code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(simpleController))
+					Ω(written).Should(ContainSubstring(simpleMount))
+				})
+			})
+
+			Context("with actions declaring error responses", func() {
+				BeforeEach(func() {
+					actions = []string{"show"}
+					verbs = []string{"GET"}
+					paths = []string{"/bottles/:id"}
+					contexts = []string{"ShowBottlesContext"}
+				})
+
+				JustBeforeEach(func() {
+					api := &design.APIDefinition{Resources: map[string]*design.ResourceDefinition{}}
+					res := &design.ResourceDefinition{Name: "bottles", Actions: map[string]*design.ActionDefinition{}}
+					res.Actions["show"] = &design.ActionDefinition{
+						Name:   "show",
+						Parent: res,
+						Responses: map[string]*design.ResponseDefinition{
+							"OK":          {Name: "OK", Status: 200},
+							"NotFound":    {Name: "NotFound", Status: 404},
+							"Conflict":    {Name: "Conflict", Status: 409},
+							"Internal":    {Name: "Internal", Status: 500},
+							"Unavailable": {Name: "Unavailable", Status: 503},
+						},
+					}
+					api.Resources["bottles"] = res
+					data[0].API = api
+				})
+
+				It("writes the MapServiceError function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(mapServiceErrorCode))
+				})
+			})
+
+			Context("with a deprecated action", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					deprecated = true
+				})
+
+				It("writes the Warning header", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(deprecatedHandler))
+				})
+			})
+
+			Context("with an action that aggregates errors", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					aggregate = true
+				})
+
+				It("merges the request and params errors", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(aggregateHandler))
+					Ω(written).ShouldNot(ContainSubstring("// Check if there was an error loading the request"))
+				})
+			})
+
+			Context("with actions that take a payload", func() {
+				BeforeEach(func() {
+					actions = []string{"list"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+					unmarshals = []string{"unmarshalListBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ListBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"id": &design.AttributeDefinition{
+										Type: design.String,
+									},
+								},
+							},
+						},
+					}
+				})
+
+				It("writes the payload unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadNoValidationsObjUnmarshal))
+				})
+
+				It("decodes the GET request body with the payload unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(getPayloadMount))
+				})
+			})
+			Context("with actions that take a payload tracking field presence", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					actions = []string{"update"}
+					verbs = []string{"PATCH"}
+					paths = []string{"/accounts/:accountID/bottles/:id"}
+					contexts = []string{"UpdateBottleContext"}
+					unmarshals = []string{"unmarshalUpdateBottlePayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "UpdateBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"name": &design.AttributeDefinition{
+										Type: design.String,
+									},
+								},
+								Metadata: dslengine.MetadataDefinition{"payload:presence": nil},
+							},
+						},
+					}
+				})
+
+				It("writes the payload unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadPresenceUnmarshal))
+				})
+			})
+
+			Context("with actions that take a payload keeping the raw body", func() {
+				BeforeEach(func() {
+					design.Design = new(design.APIDefinition)
+					actions = []string{"receive"}
+					verbs = []string{"POST"}
+					paths = []string{"/hooks"}
+					contexts = []string{"ReceiveHookContext"}
+					unmarshals = []string{"unmarshalReceiveHookPayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "ReceiveHookPayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"event": &design.AttributeDefinition{
+										Type: design.String,
+									},
+								},
+								Metadata: dslengine.MetadataDefinition{"payload:raw": nil},
+							},
+						},
+					}
+				})
+
+				It("writes the payload unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadRawUnmarshal))
+				})
 			})
 
-			JustBeforeEach(func() {
-				codegen.TempCount = 0
-				api := &design.APIDefinition{}
-				d := &genapp.ControllerTemplateData{
-					Resource: "Bottles",
-					Origins:  origins,
-				}
-				as := make([]map[string]interface{}, len(actions))
-				for i, a := range actions {
-					var unmarshal string
-					var payload *design.UserTypeDefinition
-					if i < len(unmarshals) {
-						unmarshal = unmarshals[i]
+			Context("with actions that take a payload with variants", func() {
+				BeforeEach(func() {
+					cat := &design.UserTypeDefinition{
+						TypeName: "Cat",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"kind":  &design.AttributeDefinition{Type: design.String},
+								"lives": &design.AttributeDefinition{Type: design.Integer},
+							},
+							Validation: &dslengine.ValidationDefinition{Required: []string{"kind", "lives"}},
+						},
 					}
-					if i < len(payloads) {
-						payload = payloads[i]
+					dog := &design.UserTypeDefinition{
+						TypeName: "Dog",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"kind": &design.AttributeDefinition{Type: design.String},
+							},
+						},
 					}
-					as[i] = map[string]interface{}{
-						"Name":       codegen.Goify(a, true),
-						"DesignName": a,
-						"Routes": []*design.RouteDefinition{
-							{
-								Verb: verbs[i],
-								Path: paths[i],
-							}},
-						"Context":   contexts[i],
-						"Unmarshal": unmarshal,
-						"Payload":   payload,
+					design.Design = &design.APIDefinition{
+						Types: map[string]*design.UserTypeDefinition{"Cat": cat, "Dog": dog},
 					}
-				}
-				if len(as) > 0 {
-					d.API = api
-					d.Actions = as
-					d.Encoders = encoders
-					d.Decoders = decoders
-					data = []*genapp.ControllerTemplateData{d}
-				} else {
-					data = nil
-				}
-			})
+					actions = []string{"create"}
+					verbs = []string{"POST"}
+					paths = []string{"/pets"}
+					contexts = []string{"CreatePetsContext"}
+					unmarshals = []string{"unmarshalCreatePetsPayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "CreatePetsPayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"kind": &design.AttributeDefinition{Type: design.String},
+								},
+								Validation: &dslengine.ValidationDefinition{Required: []string{"kind"}},
+								Metadata: dslengine.MetadataDefinition{
+									"payload:discriminator": []string{"kind"},
+									"payload:variants":      []string{"cat=Cat", "dog=Dog"},
+								},
+							},
+						},
+					}
+				})
 
-			Context("with missing data", func() {
-				It("returns an empty string", func() {
+				It("decodes the variant selected by the discriminator", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).Should(BeEmpty())
+					Ω(written).Should(ContainSubstring(payloadVariantsUnmarshal))
 				})
 			})
 
-			Context("with a simple controller", func() {
+			Context("with actions that take payloads", func() {
 				BeforeEach(func() {
-					actions = []string{"list"}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts/:accountID/bottles"}
-					contexts = []string{"ListBottleContext"}
+					design.Design = new(design.APIDefinition)
+					actions = []string{"create", "rate"}
+					verbs = []string{"POST", "PUT"}
+					paths = []string{"/bottles", "/bottles/:id/rating"}
+					contexts = []string{"CreateBottlesContext", "RateBottlesContext"}
+					unmarshals = []string{"unmarshalCreateBottlesPayload", "unmarshalRateBottlesPayload"}
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "CreateBottlesPayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"name": &design.AttributeDefinition{Type: design.String},
+								},
+								Validation: &dslengine.ValidationDefinition{Required: []string{"name"}},
+							},
+						},
+						{
+							TypeName: "RateBottlesPayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: &design.Array{ElemType: &design.AttributeDefinition{Type: design.Integer}},
+							},
+						},
+					}
 				})
 
-				It("writes the controller code", func() {
+				It("writes the ValidateEndpointPayload dispatcher", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).ShouldNot(BeEmpty())
-					Ω(written).Should(ContainSubstring(simpleController))
-					Ω(written).Should(ContainSubstring(simpleMount))
+					Ω(written).Should(ContainSubstring(validateEndpointPayloadCode))
 				})
 			})
 
-			Context("with actions that take a payload", func() {
+			Context("with an action that limits the body size", func() {
 				BeforeEach(func() {
-					actions = []string{"list"}
-					verbs = []string{"GET"}
-					paths = []string{"/accounts/:accountID/bottles"}
-					contexts = []string{"ListBottleContext"}
-					unmarshals = []string{"unmarshalListBottlePayload"}
+					design.Design = new(design.APIDefinition)
+					actions = []string{"create"}
+					verbs = []string{"POST"}
+					paths = []string{"/bottles"}
+					contexts = []string{"CreateBottleContext"}
+					unmarshals = []string{"unmarshalCreateBottlePayload"}
 					payloads = []*design.UserTypeDefinition{
 						{
-							TypeName: "ListBottlePayload",
+							TypeName: "CreateBottlePayload",
 							AttributeDefinition: &design.AttributeDefinition{
 								Type: design.Object{
-									"id": &design.AttributeDefinition{
-										Type: design.String,
-									},
+									"name": &design.AttributeDefinition{Type: design.String},
 								},
 							},
 						},
 					}
+					maxBodySize = 1024
 				})
 
-				It("writes the payload unmarshal function", func() {
+				It("checks the content length before decoding the body", func() {
 					err := writer.Execute(data)
 					Ω(err).ShouldNot(HaveOccurred())
 					b, err := ioutil.ReadFile(filename)
 					Ω(err).ShouldNot(HaveOccurred())
 					written := string(b)
-					Ω(written).Should(ContainSubstring(payloadNoValidationsObjUnmarshal))
+					Ω(written).Should(ContainSubstring(maxBodySizeUnmarshal))
 				})
 			})
+
 			Context("with actions that take a payload with a required validation", func() {
 				BeforeEach(func() {
 					actions = []string{"list"}
@@ -1472,6 +2618,61 @@ var _ = Describe("UserTypesWriter", func() {
 				})
 			})
 
+			Context("with a user type generating a validated constructor", func() {
+				BeforeEach(func() {
+					minLength := 2
+					attDef = &design.AttributeDefinition{
+						Type: design.Object{
+							"name": &design.AttributeDefinition{
+								Type: design.String,
+								Validation: &dslengine.ValidationDefinition{
+									MinLength: &minLength,
+								},
+							},
+							"vintage": &design.AttributeDefinition{
+								Type: design.Integer,
+							},
+							"color": &design.AttributeDefinition{
+								Type:         design.String,
+								DefaultValue: "red",
+							},
+							"notes": &design.AttributeDefinition{
+								Type: design.String,
+							},
+						},
+						Validation: &dslengine.ValidationDefinition{
+							Required: []string{"name", "vintage"},
+						},
+						Metadata: dslengine.MetadataDefinition{"struct:constructor": nil},
+					}
+					typeName = "BottlePayload"
+				})
+				It("writes the constructor code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(validatedCtorUserType))
+				})
+
+				Context("with a required attribute named v", func() {
+					BeforeEach(func() {
+						attDef.Type.(design.Object)["v"] = &design.AttributeDefinition{Type: design.Integer}
+						attDef.Validation.Required = append(attDef.Validation.Required, "v")
+					})
+
+					It("does not shadow the constructor argument", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(validatedCtorArgV))
+					})
+				})
+			})
+
 			Context("with a user type including hash", func() {
 				BeforeEach(func() {
 					attDef = &design.AttributeDefinition{
@@ -1689,6 +2890,160 @@ func NewListBottleContext(ctx context.Context, r *http.Request, service *goa.Ser
 	}
 	return &rctx, err
 }
+`
+
+	strTrimContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := strings.TrimSpace(paramParam[0])
+		rctx.Param = &rawParam
+	}
+`
+
+	optionalIdempotencyKeyContextFactory = `
+	req.Request = r
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = goa.WithIdempotencyKey(ctx, key)
+	}
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+`
+
+	requiredIdempotencyKeyContextFactory = `
+	req.Request = r
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		ctx = goa.WithIdempotencyKey(ctx, key)
+	} else {
+		err = goa.MergeErrors(err, goa.MissingHeaderError("Idempotency-Key"))
+	}
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+`
+
+	strTrimMaxLengthContextFactory = `
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := strings.TrimSpace(paramParam[0])
+		rctx.Param = &rawParam
+		if rctx.Param != nil {
+			if utf8.RuneCountInString(*rctx.Param) > 5 {
+				err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `param` + "`" + `, *rctx.Param, utf8.RuneCountInString(*rctx.Param), 5, false))
+			}
+		}
+	}
+	return &rctx, err
+`
+
+	arrayTrimContextFactory = `
+	if len(paramParam) > 0 {
+		params := make([]string, len(paramParam))
+		for i, rawParam := range paramParam {
+			params[i] = strings.TrimSpace(rawParam)
+		}
+		rctx.Param = params
+	}
+	return &rctx, err
+`
+
+	payloadTrimMaxLengthContext = `
+// Finalize sets the default values and applies the transforms defined in the design.
+func (payload *listBottlePayload) Finalize() {
+	if payload.Str != nil {
+		*payload.Str = strings.TrimSpace(*payload.Str)
+	}
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *listBottlePayload) Validate() (err error) {
+	if payload.Int == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `raw` + "`" + `, "int"))
+	}
+	if payload.Str != nil {
+		if utf8.RuneCountInString(*payload.Str) > 5 {
+			err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `raw.str` + "`" + `, *payload.Str, utf8.RuneCountInString(*payload.Str), 5, false))
+		}
+	}
+	return
+}
+`
+
+	boolValuesContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if rawParam == "on" || rawParam == "off" {
+			param := rawParam == "on"
+			tmp1 := &param
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "boolean"))
+		}
+	}
+	return &rctx, err
+`
+
+	boolFlagContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 && paramParam[0] == "" {
+		paramParam = []string{"true"}
+	}
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := strconv.ParseBool(rawParam); err2 == nil {
+			tmp1 := &param
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "boolean"))
+		}
+	}
+`
+
+	strLowerContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := strings.ToLower(strings.TrimSpace(paramParam[0]))
+		rctx.Param = &rawParam
+	}
+`
+
+	strSourcesContextFactory = `
+	var paramParam []string
+	if v := req.Header["X-Tenant-Id"]; len(v) > 0 {
+		paramParam = v
+	} else if v := req.URL.Query()["tenant"]; len(v) > 0 {
+		paramParam = v
+	} else if v := req.Params["param"]; len(v) > 0 {
+		paramParam = v
+	}
+	if len(paramParam) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("param"))
+	} else {
+		rawParam := paramParam[0]
+		rctx.Param = rawParam
+	}
+	return &rctx, err
+`
+
+	strParamValidatorContextFactory = `
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		rctx.Param = &rawParam
+		if rctx.Param != nil {
+			if err2 := service.ValidateParam("region", "param", *rctx.Param); err2 != nil {
+				err = goa.MergeErrors(err, err2)
+			}
+		}
+	}
+	return &rctx, err
+}
+`
+
+	requiredOneOfContextFactory = `
+	if rctx.Name == nil && rctx.Email == nil {
+		err = goa.MergeErrors(err, goa.MissingOneOfParamsError([]string{"name", "email"}))
+	}
+	return &rctx, err
+}
 `
 
 	strNonOptionalContext = `
@@ -2257,7 +3612,8 @@ type ListBottleContext struct {
 	payloadObjUnmarshal = `
 func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
 	payload := &listBottlePayload{}
-	if err := service.DecodeRequest(req, payload); err != nil {
+	if null, err := service.DecodeNullableRequest(req, payload); err != nil || null {
+		// A null body is handled as a missing payload
 		return err
 	}
 	if err := payload.Validate(); err != nil {
@@ -2272,10 +3628,18 @@ func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *
 	payloadNoValidationsObjUnmarshal = `
 func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
 	payload := &listBottlePayload{}
-	if err := service.DecodeRequest(req, payload); err != nil {
+	if null, err := service.DecodeNullableRequest(req, payload); err != nil || null {
+		// A null body is handled as a missing payload
 		return err
 	}
-	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	pub := payload.Publicize()
+	goa.ContextRequest(ctx).Payload = pub
+	// Run the validations of the Validate method hand-written for the payload type, if any
+	if v, ok := interface{}(pub).(interface {
+		Validate() error
+	}); ok {
+		return v.Validate()
+	}
 	return nil
 }
 `
@@ -2287,7 +3651,7 @@ type PublicController interface {
 }
 `
 
-	fileServerOptionsHandler = `service.Mux.Handle("OPTIONS", "/public/star\\*star/*filepath", ctrl.MuxHandler("preflight", handlePublicOrigin(cors.HandlePreflight()), nil))`
+	fileServerOptionsHandler = `service.Mux.Handle("OPTIONS", p+"/public/star\\*star/*filepath", ctrl.MuxHandler("preflight", handlePublicOrigin(cors.HandlePreflight()), nil))`
 
 	simpleController = `// BottlesController is the controller interface for the Bottles actions.
 type BottlesController interface {
@@ -2298,7 +3662,8 @@ type BottlesController interface {
 
 	originsIntegration = `}
 	h = handleBottlesOrigin(h)
-	service.Mux.Handle`
+	if service.Mounts("bottles.list") {
+		service.Mux.Handle`
 
 	originsHandler = `// handleBottlesOrigin applies the CORS response headers corresponding to the origin.
 func handleBottlesOrigin(h goa.Handler) goa.Handler {
@@ -2383,9 +3748,17 @@ func handleBottlesOrigin(h goa.Handler) goa.Handler {
 
 	encoderController = `
 // MountBottlesController "mounts" a Bottles resource controller on the given service.
-func MountBottlesController(service *goa.Service, ctrl BottlesController) {
+// The optional prefix is prepended to the path of each route so that the same controller may be
+// mounted multiple times under different paths. Wildcards in the prefix are matched but their
+// values are not loaded into the action contexts. The prefix is not reflected in the Swagger
+// specification basePath. Actions rejected by the service MountFilter are not mounted.
+func MountBottlesController(service *goa.Service, ctrl BottlesController, prefix ...string) {
 	initService(service)
 	var h goa.Handler
+	var p string
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -2399,14 +3772,20 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		}
 		return ctrl.List(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
-	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
+	if service.Mounts("bottles.list") {
+		service.Mux.Handle("GET", p+"/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
+		service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET "+p+"/accounts/:accountID/bottles")
+	}
 }
 `
 
-	simpleMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController) {
+	simpleMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController, prefix ...string) {
 	initService(service)
 	var h goa.Handler
+	var p string
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -2420,11 +3799,16 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 		}
 		return ctrl.List(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
-	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
+	if service.Mounts("bottles.list") {
+		service.Mux.Handle("GET", p+"/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
+		service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET "+p+"/accounts/:accountID/bottles")
+	}
 }
 `
 
+	getPayloadMount = `		service.Mux.Handle("GET", p+"/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, unmarshalListBottlePayload))
+`
+
 	multiController = `// BottlesController is the controller interface for the Bottles actions.
 type BottlesController interface {
 	goa.Muxer
@@ -2433,9 +3817,13 @@ type BottlesController interface {
 }
 `
 
-	multiMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController) {
+	multiMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController, prefix ...string) {
 	initService(service)
 	var h goa.Handler
+	var p string
+	if len(prefix) > 0 {
+		p = prefix[0]
+	}
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -2449,8 +3837,10 @@ type BottlesController interface {
 		}
 		return ctrl.List(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
-	service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET /accounts/:accountID/bottles")
+	if service.Mounts("bottles.list") {
+		service.Mux.Handle("GET", p+"/accounts/:accountID/bottles", ctrl.MuxHandler("list", h, nil))
+		service.LogInfo("mount", "ctrl", "Bottles", "action", "List", "route", "GET "+p+"/accounts/:accountID/bottles")
+	}
 
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 		// Check if there was an error loading the request
@@ -2464,8 +3854,10 @@ type BottlesController interface {
 		}
 		return ctrl.Show(rctx)
 	}
-	service.Mux.Handle("GET", "/accounts/:accountID/bottles/:id", ctrl.MuxHandler("show", h, nil))
-	service.LogInfo("mount", "ctrl", "Bottles", "action", "Show", "route", "GET /accounts/:accountID/bottles/:id")
+	if service.Mounts("bottles.show") {
+		service.Mux.Handle("GET", p+"/accounts/:accountID/bottles/:id", ctrl.MuxHandler("show", h, nil))
+		service.LogInfo("mount", "ctrl", "Bottles", "action", "Show", "route", "GET "+p+"/accounts/:accountID/bottles/:id")
+	}
 }
 `
 
@@ -2534,5 +3926,646 @@ type ComplexPayload struct {
 	Misc map[int]*MiscPayload ` + "`" + `form:"misc,omitempty" json:"misc,omitempty" xml:"misc,omitempty"` + "`" + `
 	Name *string ` + "`" + `form:"name,omitempty" json:"name,omitempty" xml:"name,omitempty"` + "`" + `
 }
+`
+
+	payloadPresenceContext = `// ListBottlePayload is the bottles list action payload.
+type ListBottlePayload struct {
+	Int int ` + "`" + `form:"int" json:"int" xml:"int"` + "`" + `
+	Str *string ` + "`" + `form:"str,omitempty" json:"str,omitempty" xml:"str,omitempty"` + "`" + `
+	// fields lists the names of the fields present in the request body.
+	fields map[string]bool
+}
+
+// IsSet returns true if the field with the given name was present in the request body, including
+// when its value is null. The name is the name of the attribute in the design.
+func (payload *ListBottlePayload) IsSet(name string) bool {
+	return payload.fields[name]
+}
+`
+
+	validatedCtorArgV = `func NewValidatedBottlePayload(name string, v int, vintage int) (*BottlePayload, error) {
+	v_ := &BottlePayload{
+		Color: "red",
+		Name: name,
+		V: v,
+		Vintage: vintage,
+	}
+	if err := v_.Validate(); err != nil {
+		return nil, err
+	}
+	return v_, nil
+}`
+
+	validatedCtorUserType = `	return
+}
+
+// NewValidatedBottlePayload creates a BottlePayload initialized with the given values of the
+// required attributes and the default values defined in the design then runs the
+// validations defined in the design.
+func NewValidatedBottlePayload(name string, vintage int) (*BottlePayload, error) {
+	v := &BottlePayload{
+		Color: "red",
+		Name: name,
+		Vintage: vintage,
+	}
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+`
+
+	badRequestErrorResp = `	if _, ok := r.(goa.ServiceError); !ok {
+		r = goa.NewErrorClass("bad_request", 400)(r)
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 400, r)
+}`
+
+	payloadAccessor = `
+// ContextListBottlesPayload returns the payload of the list action of the bottles resource
+// stored in the request context once the request body is decoded, the zero value if there is none.
+// It makes the typed payload available to middleware running before the action handler.
+func ContextListBottlesPayload(ctx context.Context) *ListBottlePayload {
+	var p *ListBottlePayload
+	if req := goa.ContextRequest(ctx); req != nil {
+		p, _ = req.Payload.(*ListBottlePayload)
+	}
+	return p
+}
+`
+
+	payloadRawContext = `// ListBottlePayload is the bottles list action payload.
+type ListBottlePayload struct {
+	Int int ` + "`" + `form:"int" json:"int" xml:"int"` + "`" + `
+	Str *string ` + "`" + `form:"str,omitempty" json:"str,omitempty" xml:"str,omitempty"` + "`" + `
+	// RawBody contains the exact bytes of the request body the payload was decoded from.
+	RawBody []byte ` + "`" + `form:"-" json:"-" xml:"-"` + "`" + `
+}
+`
+
+	payloadSingleValueContext = `
+// UnmarshalJSON decodes the JSON representation of listBottlePayload. A single value given in place
+// of an array for the tags field is decoded into a slice with one element.
+func (payload *listBottlePayload) UnmarshalJSON(data []byte) error {
+	type alias listBottlePayload
+	var raw struct {
+		alias
+		Tags json.RawMessage ` + "`" + `json:"tags"` + "`" + `
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*payload = listBottlePayload(raw.alias)
+	if err := goa.UnmarshalSingleValue(raw.Tags, &payload.Tags); err != nil {
+		return err
+	}
+	return nil
+}
+`
+
+	payloadTransformContext = `
+// Finalize sets the default values and applies the transforms defined in the design.
+func (payload *listBottlePayload) Finalize() {
+	if payload.Str != nil {
+		*payload.Str = strings.ToLower(*payload.Str)
+	}
+}
+
+// Validate runs the validation rules defined in the design.
+func (payload *listBottlePayload) Validate() (err error) {
+	if payload.Int == nil {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `raw` + "`" + `, "int"))
+	}
+	if payload.Str != nil {
+		if err2 := goa.ValidateFormat(goa.FormatEmail, *payload.Str); err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidFormatError(` + "`" + `raw.str` + "`" + `, *payload.Str, goa.FormatEmail, err2))
+		}
+	}
+	return
+}
+`
+
+	payloadVariantContext = `// ListBottlePayload is the bottles list action payload.
+type ListBottlePayload struct {
+	Int int ` + "`" + `form:"int" json:"int" xml:"int"` + "`" + `
+	Str *string ` + "`" + `form:"str,omitempty" json:"str,omitempty" xml:"str,omitempty"` + "`" + `
+	// Variant contains the request body decoded into the type selected by the value of Str:
+	// *Dog if "dog".
+	Variant interface{} ` + "`" + `form:"-" json:"-" xml:"-"` + "`" + `
+}
+`
+
+	validateEndpointPayloadCode = `// ValidateEndpointPayload runs the validations defined in the design on the payload of the
+// action identified by endpointName. The endpoint name is made of the resource and action design
+// names separated with a dot, e.g. "bottle.create". ValidateEndpointPayload makes it possible to
+// validate payloads in generic code such as middleware without knowing the concrete payload types.
+func ValidateEndpointPayload(endpointName string, payload interface{}) error {
+	switch endpointName {
+	case "bottles.create":
+		p, ok := payload.(*CreateBottlesPayload)
+		if !ok {
+			return fmt.Errorf("invalid payload type %T for endpoint %q", payload, endpointName)
+		}
+		return p.Validate()
+	case "bottles.rate":
+		if _, ok := payload.(RateBottlesPayload); !ok {
+			return fmt.Errorf("invalid payload type %T for endpoint %q", payload, endpointName)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown endpoint %q", endpointName)
+}
+`
+
+	maxBodySizeUnmarshal = `
+// unmarshalCreateBottlePayload unmarshals the request body into the context request data Payload field.
+func unmarshalCreateBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) (err error) {
+	if req.ContentLength > 1024 {
+		return goa.ErrRequestBodyTooLarge("request body length exceeds 1024 bytes")
+	}
+	// The length of chunked bodies is unknown (-1), stop reading them past the limit
+	req.Body = http.MaxBytesReader(goa.ContextResponse(ctx), req.Body, 1024)
+	defer func() {
+		if err != nil && strings.HasSuffix(err.Error(), "http: request body too large") {
+			err = goa.ErrRequestBodyTooLarge("request body length exceeds 1024 bytes")
+		}
+	}()
+	payload := &createBottlePayload{}
+	if null, err := service.DecodeNullableRequest(req, payload); err != nil || null {
+		// A null body is handled as a missing payload
+		return err
+	}
+`
+
+	payloadPresenceUnmarshal = `
+// unmarshalUpdateBottlePayload unmarshals the request body into the context request data Payload field.
+func unmarshalUpdateBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err := service.DecodeRequest(req, &fields); err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	payload := &updateBottlePayload{}
+	if null, err := service.DecodeNullableRequest(req, payload); err != nil || null {
+		// A null body is handled as a missing payload
+		return err
+	}
+	pub := payload.Publicize()
+	pub.fields = make(map[string]bool, len(fields))
+	for n := range fields {
+		pub.fields[n] = true
+	}
+	goa.ContextRequest(ctx).Payload = pub
+	// Run the validations of the Validate method hand-written for the payload type, if any
+	if v, ok := interface{}(pub).(interface {
+		Validate() error
+	}); ok {
+		return v.Validate()
+	}
+	return nil
+}
+`
+
+	payloadRawUnmarshal = `
+// unmarshalReceiveHookPayload unmarshals the request body into the context request data Payload field.
+func unmarshalReceiveHookPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	payload := &receiveHookPayload{}
+	if null, err := service.DecodeNullableRequest(req, payload); err != nil || null {
+		// A null body is handled as a missing payload
+		return err
+	}
+	pub := payload.Publicize()
+	pub.RawBody = body
+	goa.ContextRequest(ctx).Payload = pub
+	// Run the validations of the Validate method hand-written for the payload type, if any
+	if v, ok := interface{}(pub).(interface {
+		Validate() error
+	}); ok {
+		return v.Validate()
+	}
+	return nil
+}
+`
+
+	emptyOKResp = `
+// OK sends a HTTP response with status code 200.
+func (ctx *ListBottleContext) OK() error {
+	ctx.ResponseData.WriteHeader(200)
+	return nil
+}
+`
+
+	emptyAcceptedResp = `
+// Accepted sends a HTTP response with status code 202.
+func (ctx *ListBottleContext) Accepted() error {
+	ctx.ResponseData.WriteHeader(202)
+	return nil
+}
+`
+
+	queryPathParamContextFactory = `
+	paramID := req.Params["id"]
+	if len(paramID) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("id"))
+	} else {
+		rawID := paramID[0]
+		if id, err2 := strconv.Atoi(rawID); err2 == nil {
+			rctx.ID = id
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("id", rawID, "integer"))
+		}
+	}
+	paramIDFilter := req.URL.Query()["id"]
+	if len(paramIDFilter) > 0 {
+		rawIDFilter := paramIDFilter[0]
+		rctx.IDFilter = &rawIDFilter
+	}
+	return &rctx, err
+}
+`
+
+	deprecatedHandler = `	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Warn clients that the action is deprecated
+		rw.Header().Set("Warning", "299 - \"Deprecated\"")
+		// Check if there was an error loading the request
+		if err := goa.ContextError(ctx); err != nil {
+			return err
+		}
+`
+
+	aggregateHandler = `	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		// Build the context and collect the errors of the request and of the params
+		rctx, err := NewListBottleContext(ctx, req, service)
+		if reqErr := goa.ContextError(ctx); reqErr != nil {
+			// Only bad requests are merged, other errors such as a too large body keep their status
+			if e, ok := reqErr.(goa.ServiceError); !ok || e.ResponseStatus() != http.StatusBadRequest {
+				return reqErr
+			}
+			err = goa.MergeErrors(reqErr, err)
+		}
+		if err != nil {
+			return err
+		}
+		return ctrl.List(rctx)
+	}
+`
+
+	matrixParamContextFactory = `
+	rctx := ListBottleContext{Context: ctx, ResponseData: resp, RequestData: req}
+	matrixItem := goa.ParseMatrixParams(req.Params, "item")
+	paramItem := req.Params["item"]
+	if len(paramItem) > 0 {
+		rawItem := paramItem[0]
+		rctx.Item = &rawItem
+	}
+	paramSize := matrixItem["size"]
+	if len(paramSize) == 0 {
+		paramSize = req.Params["size"]
+	}
+	if len(paramSize) > 0 {
+		rawSize := paramSize[0]
+		if size, err2 := strconv.Atoi(rawSize); err2 == nil {
+			tmp2 := size
+			tmp1 := &tmp2
+			rctx.Size = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("size", rawSize, "integer"))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	respondCode = `
+// Respond sends a HTTP response with the status code given by the Status field of r.
+// The status code defaults to 200 if the field value is not the status code of one of the
+// action responses.
+func (ctx *ListBottleContext) Respond(r *Status) error {
+	if r != nil && r.Status != nil {
+		switch *r.Status {
+		case 200:
+			return ctx.OK(r)
+		case 201:
+			return ctx.Created(r)
+		}
+	}
+	return ctx.OK(r)
+}
+`
+
+	bigNumContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Amount *big.Int
+	Ratios []*big.Rat
+}
+`
+
+	bigNumContextFactory = `
+	paramAmount := req.Params["amount"]
+	if len(paramAmount) > 0 {
+		rawAmount := paramAmount[0]
+		if amount, ok := new(big.Int).SetString(rawAmount, 10); ok {
+			rctx.Amount = amount
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("amount", rawAmount, "big integer"))
+		}
+	}
+	paramRatios := req.Params["ratios"]
+	if len(paramRatios) > 0 {
+		params := make([]*big.Rat, len(paramRatios))
+		for i, rawRatios := range paramRatios {
+			if ratios, ok := new(big.Rat).SetString(rawRatios); ok {
+				params[i] = ratios
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("ratios", rawRatios, "big rational"))
+			}
+		}
+		rctx.Ratios = params
+	}
+	return &rctx, err
+`
+
+	dateContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Day *time.Time
+	Days []time.Time
+}
+`
+
+	dateTimeContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	ExpiresAt *time.Time
+	Since []time.Time
+}
+`
+
+	dateTimeContextFactory = `
+	paramExpiresAt := req.Params["expiresAt"]
+	if len(paramExpiresAt) > 0 {
+		rawExpiresAt := paramExpiresAt[0]
+		if expiresAt, err2 := time.Parse(time.RFC3339, rawExpiresAt); err2 == nil {
+			tmp1 := &expiresAt
+			rctx.ExpiresAt = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("expiresAt", rawExpiresAt, "datetime"))
+		}
+	}
+	paramSince := req.Params["since"]
+	if len(paramSince) > 0 {
+		params := make([]time.Time, len(paramSince))
+		for i, rawSince := range paramSince {
+			if since, err2 := time.Parse(time.RFC3339, rawSince); err2 == nil {
+				params[i] = since
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("since", rawSince, "datetime"))
+			}
+		}
+		rctx.Since = params
+	}
+`
+
+	viewOKResp = `
+// OKView sends a HTTP response with status code 200 using the view selected by
+// the "fields" query string parameter or by the "view" parameter of the Accept header media type.
+// The response uses the default view if the request does not select one of the media type views.
+func (ctx *ListBottleContext) OKView(r *Bottle, tiny *BottleTiny) error {
+	switch goa.RequestedView(ctx.RequestData.Request, "fields") {
+	case "tiny":
+		return ctx.OKTiny(tiny)
+	}
+	return ctx.OK(r)
+}
+`
+
+	linkOKResp = `
+func (ctx *ListBottleContext) OK(r *Page) error {
+	ctx.ResponseData.Header().Set("Content-Type", "")
+	if r != nil && r.First != "" {
+		ctx.ResponseData.Header().Add("Link", "<"+r.First+">; rel=\"first\"")
+	}
+	if r != nil && r.Next != nil {
+		ctx.ResponseData.Header().Add("Link", "<"+*r.Next+">; rel=\"next\"")
+	}
+	return ctx.ResponseData.Service.Send(ctx.Context, 200, r)
+}
+`
+
+	dateContextFactory = `
+	paramDay := req.Params["day"]
+	if len(paramDay) > 0 {
+		rawDay := paramDay[0]
+		if day, err2 := time.Parse("2006-01-02", rawDay); err2 == nil {
+			tmp1 := &day
+			rctx.Day = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("day", rawDay, "date"))
+		}
+	}
+	paramDays := req.Params["days"]
+	if len(paramDays) > 0 {
+		params := make([]time.Time, len(paramDays))
+		for i, rawDays := range paramDays {
+			if days, err2 := time.Parse("2006-01-02", rawDays); err2 == nil {
+				params[i] = days
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("days", rawDays, "date"))
+			}
+		}
+		rctx.Days = params
+	}
+	return &rctx, err
+`
+
+	cacheControlOKResp = `
+// OK sends a HTTP response with status code 200.
+func (ctx *ListBottleContext) OK() error {
+	ctx.ResponseData.Header().Set("Cache-Control", "public, max-age=60")
+	ctx.ResponseData.WriteHeader(200)
+	return nil
+}
+`
+
+	jsonArrayParamContextFactory = `
+	paramIds := req.Params["ids"]
+	if elems, err2 := goa.SplitArrayParam(paramIds); err2 != nil {
+		err = goa.MergeErrors(err, goa.InvalidParamTypeError("ids", paramIds[0], "array"))
+		paramIds = nil
+	} else {
+		paramIds = elems
+	}
+	if len(paramIds) > 0 {
+		params := make([]int, len(paramIds))
+		for i, rawIds := range paramIds {
+			if ids, err2 := strconv.Atoi(rawIds); err2 == nil {
+				params[i] = ids
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("ids", rawIds, "integer"))
+			}
+		}
+		rctx.Ids = params
+	}
+	return &rctx, err
+}
+`
+
+	enumMapParamContextFactory = `
+	paramStatus := req.Params["status"]
+	if len(paramStatus) > 0 {
+		if v, ok := map[string]string{"inactive": "0", "active": "1"}[paramStatus[0]]; ok {
+			paramStatus = []string{v}
+		}
+	}
+	if len(paramStatus) > 0 {
+		rawStatus := paramStatus[0]
+`
+
+	aliasParamContextFactory = `
+	paramQuery := req.Params["query"]
+	if len(paramQuery) == 0 {
+		for _, alias := range []string{"q", "search"} {
+			if v := req.URL.Query()[alias]; len(v) > 0 && v[0] != "" {
+				paramQuery = v
+				break
+			}
+		}
+	}
+	if len(paramQuery) == 0 {
+		err = goa.MergeErrors(err, goa.MissingParamError("query"))
+	} else {
+		rawQuery := paramQuery[0]
+		rctx.Query = rawQuery
+	}
+	return &rctx, err
+}
+`
+
+	mapServiceErrorCode = `
+// MapServiceError returns the HTTP status code and body of the response corresponding to err.
+// Errors created with goa error classes keep their status code if it is below 500, if it is 500 or
+// if it is one of the server error status codes declared in the design, other errors produce
+// internal errors. MapServiceError can be used with the middleware.ErrorMappingHandler middleware.
+func MapServiceError(err error) (int, interface{}) {
+	if e, ok := err.(goa.ServiceError); ok {
+		status := e.ResponseStatus()
+		if status < http.StatusInternalServerError {
+			return status, e
+		}
+		switch status {
+		case 500, 503:
+			return status, e
+		}
+	}
+	return http.StatusInternalServerError, goa.ErrInternal(err)
+}
+`
+
+	payloadVariantsUnmarshal = `// unmarshalCreatePetsPayload unmarshals the request body into the context request data Payload field.
+func unmarshalCreatePetsPayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	payload := &createPetsPayload{}
+	if null, err := service.DecodeNullableRequest(req, payload); err != nil || null {
+		// A null body is handled as a missing payload
+		return err
+	}
+	if err := payload.Validate(); err != nil {
+		// Initialize payload with private data structure so it can be logged
+		goa.ContextRequest(ctx).Payload = payload
+		return err
+	}
+	// Decode the request body again into the variant selected by the discriminator
+	var variant interface{}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	switch *payload.Kind {
+	case "cat":
+		v := &cat{}
+		if err := service.DecodeRequest(req, &v); err != nil {
+			return err
+		}
+		if err := v.Validate(); err != nil {
+			return err
+		}
+		variant = v.Publicize()
+	case "dog":
+		v := &dog{}
+		if err := service.DecodeRequest(req, &v); err != nil {
+			return err
+		}
+		variant = v.Publicize()
+	default:
+		return goa.InvalidEnumValueError(` + "`" + `raw.kind` + "`" + `, *payload.Kind, []interface{}{"cat", "dog"})
+	}
+	pub := payload.Publicize()
+	pub.Variant = variant
+	goa.ContextRequest(ctx).Payload = pub
+	return nil
+}`
+
+	requiredBasicAuthParamContextFactory = `
+	var paramPass []string
+	if _, v, ok := r.BasicAuth(); ok {
+		paramPass = []string{v}
+	}
+	if len(paramPass) == 0 {
+		err = goa.MergeErrors(err, goa.ErrUnauthorized("missing basic auth credentials"))
+	} else {
+		rawPass := paramPass[0]
+		rctx.Pass = rawPass
+	}
+	var paramUser []string
+	if v, _, ok := r.BasicAuth(); ok {
+		paramUser = []string{v}
+	}
+	if len(paramUser) > 0 {
+		rawUser := paramUser[0]
+		rctx.User = rawUser
+	}
+	return &rctx, err
+}
+`
+
+	basicAuthParamContextFactory = `
+	var paramPass []string
+	if _, v, ok := r.BasicAuth(); ok {
+		paramPass = []string{v}
+	}
+	if len(paramPass) > 0 {
+		rawPass := paramPass[0]
+		rctx.Pass = &rawPass
+	}
+	var paramUser []string
+	if v, _, ok := r.BasicAuth(); ok {
+		paramUser = []string{v}
+	}
+	if len(paramUser) == 0 {
+		err = goa.MergeErrors(err, goa.ErrUnauthorized("missing basic auth credentials"))
+	} else {
+		rawUser := paramUser[0]
+		rctx.User = rawUser
+	}
+	return &rctx, err
+}
 `
 )
@@ -1,8 +1,10 @@
 package genclient
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -63,6 +65,8 @@ func Generate() (files []string, err error) {
 	set.String("design", "", "")
 	set.Bool("force", false, "")
 	set.Bool("notest", false, "")
+	set.Bool("fuzz", false, "")
+	set.Bool("json-pointer", false, "")
 	set.Parse(os.Args[1:])
 
 	// First check compatibility
@@ -142,9 +146,13 @@ func (g *Generator) Generate() (_ []string, err error) {
 		funcs = template.FuncMap{
 			"add":                func(a, b int) int { return a + b },
 			"cmdFieldType":       cmdFieldType,
+			"decodegotypename":   decodeGoTypeName,
+			"decodegotyperef":    decodeGoTypeRef,
 			"defaultPath":        defaultPath,
 			"escapeBackticks":    escapeBackticks,
 			"goify":              codegen.Goify,
+			"headerField":        headerField,
+			"headerFromString":   headerFromString,
 			"gotypedef":          codegen.GoTypeDef,
 			"gotypedesc":         codegen.GoTypeDesc,
 			"gotypename":         codegen.GoTypeName,
@@ -328,6 +336,7 @@ func (g *Generator) generateResourceClient(pkgDir string, res *design.ResourceDe
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
 	}
@@ -470,8 +479,38 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		}
 		return append(reqData, optData...)
 	}
-	queryParams = initParamsScoped(action.QueryParams)
-	headers = initParamsScoped(action.Headers)
+	var basicAuth []*basicAuthParam
+	var sourceHeaders []*paramData
+	for _, p := range initParamsScoped(action.QueryParams) {
+		if v, ok := p.Attribute.Metadata["param:basic-auth"]; ok && len(v) > 0 {
+			index := 0
+			if v[0] == "password" {
+				index = 1
+			}
+			basicAuth = append(basicAuth, &basicAuthParam{paramData: p, Index: index})
+			continue
+		}
+		if key, ok := p.Attribute.Metadata["param:query"]; ok && len(key) > 0 {
+			p.Name = key[0]
+		}
+		if srcs := p.Attribute.Metadata["param:sources"]; len(srcs) > 0 {
+			// Send the value to the first source the param is read from.
+			in, n := srcs[0], p.Name
+			if i := strings.Index(in, ":"); i > 0 {
+				in, n = in[:i], in[i+1:]
+			}
+			if in == "header" {
+				p.Name = n
+				sourceHeaders = append(sourceHeaders, p)
+				continue
+			}
+			if in == "query" {
+				p.Name = n
+			}
+		}
+		queryParams = append(queryParams, p)
+	}
+	headers = append(initParamsScoped(action.Headers), sourceHeaders...)
 
 	if action.Security != nil {
 		signer = codegen.Goify(action.Security.Scheme.SchemeName, true)
@@ -490,6 +529,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Signer             string
 		QueryParams        []*paramData
 		Headers            []*paramData
+		BasicAuth          []*basicAuthParam
 	}{
 		Name:               action.Name,
 		ResourceName:       action.Parent.Name,
@@ -504,6 +544,7 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 		Signer:             signer,
 		QueryParams:        queryParams,
 		Headers:            headers,
+		BasicAuth:          basicAuth,
 	}
 	if action.WebSocket() {
 		return clientsWSTmpl.Execute(file, data)
@@ -511,7 +552,53 @@ func (g *Generator) generateActionClient(action *design.ActionDefinition, file *
 	if err := clientsTmpl.Execute(file, data); err != nil {
 		return err
 	}
-	return requestsTmpl.Execute(file, data)
+	if err := requestsTmpl.Execute(file, data); err != nil {
+		return err
+	}
+	return g.generateActionResults(action, file, funcs)
+}
+
+// generateActionResults generates the result types and constructors for the action responses
+// that define headers. The result types aggregate the decoded response body and headers.
+func (g *Generator) generateActionResults(action *design.ActionDefinition, file *codegen.SourceFile, funcs template.FuncMap) error {
+	resultTmpl := template.Must(template.New("result").Funcs(funcs).Parse(resultTmpl))
+	return action.IterateResponses(func(resp *design.ResponseDefinition) error {
+		if resp.Headers == nil || len(resp.Headers.Type.ToObject()) == 0 {
+			return nil
+		}
+		var body *design.MediaTypeDefinition
+		mt, ok := resp.Type.(*design.MediaTypeDefinition)
+		if !ok {
+			mt = design.Design.MediaTypeWithIdentifier(resp.MediaType)
+		}
+		if mt != nil {
+			view := resp.ViewName
+			if view == "" {
+				view = design.DefaultView
+			}
+			p, _, err := mt.Project(view)
+			if err != nil {
+				return err
+			}
+			body = p
+		}
+		data := struct {
+			Name         string
+			ActionName   string
+			ResourceName string
+			Response     *design.ResponseDefinition
+			Body         *design.MediaTypeDefinition
+			Headers      *design.AttributeDefinition
+		}{
+			Name:         codegen.Goify(action.Name+strings.Title(action.Parent.Name), true) + codegen.Goify(resp.Name, true) + "Result",
+			ActionName:   action.Name,
+			ResourceName: action.Parent.Name,
+			Response:     resp,
+			Body:         body,
+			Headers:      resp.Headers,
+		}
+		return resultTmpl.Execute(file, data)
+	})
 }
 
 // fileServerMethod returns the name of the client method for downloading assets served by the given
@@ -547,8 +634,6 @@ func (g *Generator) fileServerMethod(fs *design.FileServerDefinition) string {
 // generateMediaTypes iterates through the media types and generate the data structures and
 // marshaling code.
 func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) error {
-	funcs["decodegotyperef"] = decodeGoTypeRef
-	funcs["decodegotypename"] = decodeGoTypeName
 	typeDecodeTmpl := template.Must(template.New("typeDecode").Funcs(funcs).Parse(typeDecodeTmpl))
 	mtFile := filepath.Join(pkgDir, "media_types.go")
 	mtWr, err := genapp.NewMediaTypesWriter(mtFile)
@@ -558,8 +643,11 @@ func (g *Generator) generateMediaTypes(pkgDir string, funcs template.FuncMap) er
 	title := fmt.Sprintf("%s: Application Media Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("math"),
 		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
@@ -604,7 +692,10 @@ func (g *Generator) generateUserTypes(pkgDir string) error {
 	title := fmt.Sprintf("%s: Application User Types", g.API.Context())
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("math"),
+		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("unicode/utf8"),
 		codegen.NewImport("uuid", "github.com/goadesign/goa/uuid"),
@@ -750,6 +841,9 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 		case design.IntegerKind:
 			return fmt.Sprintf("%s := strconv.Itoa(%s)", target, name)
 		case design.BooleanKind:
+			if vals, ok := att.Metadata["param:bool-values"]; ok && len(vals) == 2 {
+				return fmt.Sprintf("%s := %q\n\tif %s {\n\t\t%s = %q\n\t}", target, vals[1], name, target, vals[0])
+			}
 			return fmt.Sprintf("%s := strconv.FormatBool(%s)", target, name)
 		case design.NumberKind:
 			return fmt.Sprintf("%s := strconv.FormatFloat(%s, 'f', -1, 64)", target, name)
@@ -776,6 +870,73 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 	}
 }
 
+// headerField returns the Go type of the result struct field that holds the value of the given
+// response header.
+func headerField(att *design.AttributeDefinition, required bool) string {
+	t := codegen.GoTypeRef(att.Type, nil, 0, false)
+	if !required && att.Type.IsPrimitive() && att.Type.Kind() != design.AnyKind {
+		t = "*" + t
+	}
+	return t
+}
+
+// headerFromString returns the Go code that parses the raw value of the response header with the
+// given name and stores the result in target. The generated code assumes that there is a
+// pre-existing "err" variable of type error. It merges any parsing error into that variable.
+func headerFromString(name, target string, att *design.AttributeDefinition, required bool) string {
+	var buf bytes.Buffer
+	if arr := att.Type.ToArray(); arr != nil {
+		fmt.Fprintf(&buf, "\tif raw := header[%q]; len(raw) > 0 {\n", http.CanonicalHeaderKey(name))
+		fmt.Fprintf(&buf, "\t\t%s = make(%s, len(raw))\n", target, codegen.GoTypeRef(att.Type, nil, 0, false))
+		buf.WriteString("\t\tfor i, rv := range raw {\n")
+		buf.WriteString(parseHeader(name, "rv", target+"[i]", arr.ElemType, false, 3))
+		buf.WriteString("\t\t}\n")
+	} else {
+		fmt.Fprintf(&buf, "\tif raw := header.Get(%q); raw != \"\" {\n", name)
+		buf.WriteString(parseHeader(name, "raw", target, att, !required, 2))
+	}
+	if required {
+		buf.WriteString("\t} else {\n")
+		fmt.Fprintf(&buf, "\t\terr = goa.MergeErrors(err, goa.MissingHeaderError(%q))\n", name)
+	}
+	buf.WriteString("\t}")
+	return buf.String()
+}
+
+// parseHeader returns the Go code that parses the header value held by the raw variable and
+// stores it in target.
+func parseHeader(name, raw, target string, att *design.AttributeDefinition, pointer bool, depth int) string {
+	var parse, typeName string
+	switch att.Type.Kind() {
+	case design.BooleanKind:
+		parse, typeName = fmt.Sprintf("strconv.ParseBool(%s)", raw), "boolean"
+	case design.IntegerKind:
+		parse, typeName = fmt.Sprintf("strconv.Atoi(%s)", raw), "integer"
+	case design.NumberKind:
+		parse, typeName = fmt.Sprintf("strconv.ParseFloat(%s, 64)", raw), "number"
+	case design.DateTimeKind:
+		parse, typeName = fmt.Sprintf("time.Parse(time.RFC3339, %s)", raw), "datetime"
+	case design.UUIDKind:
+		parse, typeName = fmt.Sprintf("uuid.FromString(%s)", raw), "uuid"
+	case design.StringKind, design.AnyKind:
+	default:
+		panic("cannot convert header of type " + att.Type.Name()) // bug
+	}
+	ref := ""
+	if pointer && att.Type.Kind() != design.AnyKind {
+		ref = "&"
+	}
+	tabs := codegen.Tabs(depth)
+	if parse == "" {
+		return fmt.Sprintf("%s%s = %s%s\n", tabs, target, ref, raw)
+	}
+	return fmt.Sprintf("%sif v, err2 := %s; err2 == nil {\n", tabs, parse) +
+		fmt.Sprintf("%s\t%s = %sv\n", tabs, target, ref) +
+		fmt.Sprintf("%s} else {\n", tabs) +
+		fmt.Sprintf("%s\terr = goa.MergeErrors(err, goa.InvalidParamTypeError(%q, %s, %q))\n", tabs, name, raw, typeName) +
+		fmt.Sprintf("%s}\n", tabs)
+}
+
 // defaultPath returns the first route path for the given action that does not take any wildcard,
 // empty string if none.
 func defaultPath(action *design.ActionDefinition) string {
@@ -883,6 +1044,13 @@ type paramData struct {
 	CheckNil      bool
 }
 
+// basicAuthParam describes a param sent in the HTTP Basic auth credentials of the request.
+type basicAuthParam struct {
+	*paramData
+	// Index is 0 for the username and 1 for the password.
+	Index int
+}
+
 type byParamName []*paramData
 
 func (b byParamName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -907,6 +1075,36 @@ func (c *Client) {{ $funcName }}(resp *http.Response) ({{ decodegotyperef . .All
 	err := c.Decoder.Decode(&decoded, resp.Body, resp.Header.Get("Content-Type"))
 	return {{ if .IsObject }}&{{ end }}decoded, err
 }
+`
+
+	resultTmpl = `{{ $name := .Name }}// {{ $name }} is the result of the {{ .ActionName }} action of the {{ .ResourceName }} resource for
+// the {{ .Response.Name }} response.
+type {{ $name }} struct {
+{{ if .Body }}	// Body is the decoded response body.
+	Body {{ decodegotyperef .Body .Body.AllRequired 0 false }}
+{{ end }}{{ range $hname, $att := .Headers.Type.ToObject }}	// {{ goify $hname true }} is the value of the {{ printf "%q" $hname }} response header.
+	{{ goify $hname true }} {{ headerField $att ($.Headers.IsRequired $hname) }}
+{{ end }}}
+
+// New{{ $name }} builds a {{ $name }} from the decoded response body and the response headers.
+// It returns an error if a required header is missing or if a header value cannot be converted to
+// the type defined in the design.
+func New{{ $name }}({{ if .Body }}body {{ decodegotyperef .Body .Body.AllRequired 0 false }}, {{ end }}header http.Header) (*{{ $name }}, error) {
+	var err error
+	res := &{{ $name }}{{ "{" }}{{ if .Body }}Body: body{{ end }}}
+{{ range $hname, $att := .Headers.Type.ToObject }}{{ headerFromString $hname (printf "res.%s" (goify $hname true)) $att ($.Headers.IsRequired $hname) }}
+{{ end }}	return res, err
+}
+
+// Decode{{ $name }} decodes the response body and headers into a {{ $name }}.
+func (c *Client) Decode{{ $name }}(resp *http.Response) (*{{ $name }}, error) {
+{{ if .Body }}	body, err := c.Decode{{ typeName .Body }}(resp)
+	if err != nil {
+		return nil, err
+	}
+	return New{{ $name }}(body, resp.Header)
+{{ else }}	return New{{ $name }}(resp.Header)
+{{ end }}}
 `
 
 	pathTmpl = `{{ $funcName := printf "%sPath%s" (goify (printf "%s%s" .Route.Parent.Name (title .Route.Parent.Parent.Name)) true) ((or (and .Index (add .Index 1)) "") | printf "%v") }}{{/*
@@ -1061,7 +1259,13 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Params }
 	header.Set("{{ .Name }}", {{ $tmp }}){{ else }}
 	header.Set("{{ .Name }}", {{ .ValueName }})
 {{ end }}{{ if .CheckNil }}	}{{ end }}
-{{ end }}{{ end }}{{ if .Signer }}	if c.{{ .Signer }}Signer != nil {
+{{ end }}{{ end }}{{ with .BasicAuth }}	var basicAuth [2]string
+{{ range . }}{{ if .CheckNil }}	if {{ .VarName }} != nil {
+		basicAuth[{{ .Index }}] = {{ .ValueName }}
+	}
+{{ else }}	basicAuth[{{ .Index }}] = {{ .ValueName }}
+{{ end }}{{ end }}	req.SetBasicAuth(basicAuth[0], basicAuth[1])
+{{ end }}{{ if .Signer }}	if c.{{ .Signer }}Signer != nil {
 		c.{{ .Signer }}Signer.Sign(req)
 	}
 {{ end }}	return req, nil
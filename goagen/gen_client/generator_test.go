@@ -300,6 +300,93 @@ var _ = Describe("Generate", func() {
 		})
 	})
 
+	Context("with a query param read from a query string parameter with a different name", func() {
+		BeforeEach(func() {
+			o := design.Object{
+				"idFilter": &design.AttributeDefinition{
+					Type:     design.String,
+					Metadata: dslengine.MetadataDefinition{"param:query": {"id"}},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name:     "testapi",
+				Consumes: design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "GET",
+										Path: "",
+									},
+								},
+								QueryParams: &design.AttributeDefinition{Type: o},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("sets the query string parameter", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(c)).Should(ContainSubstring(`values.Set("id", *idFilter)`))
+		})
+	})
+
+	Context("with a query param read from several sources", func() {
+		BeforeEach(func() {
+			o := design.Object{
+				"tenant": &design.AttributeDefinition{
+					Type:     design.String,
+					Metadata: dslengine.MetadataDefinition{"param:sources": {"header:X-Tenant-Id", "query"}},
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name:     "testapi",
+				Consumes: design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "GET",
+										Path: "",
+									},
+								},
+								QueryParams: &design.AttributeDefinition{Type: o},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("sends the param to the first source", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(c)).Should(ContainSubstring(`header.Set("X-Tenant-Id", *tenant)`))
+			Ω(string(c)).ShouldNot(ContainSubstring(`values.Set(`))
+		})
+	})
+
 	Context("with an action using websocket", func() {
 		BeforeEach(func() {
 			codegen.TempCount = 0
@@ -561,6 +648,172 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(ContainSubstring("uuid \"github.com/goadesign/goa/uuid\""))
 		})
 	})
+
+	Context("with an action with a user type payload using a transform", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			testType := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"name": &design.AttributeDefinition{
+							Type:     design.String,
+							Metadata: dslengine.MetadataDefinition{"transform:trim": nil},
+						},
+					},
+				},
+				TypeName: "TestType",
+			}
+			design.Design = &design.APIDefinition{
+				Types: map[string]*design.UserTypeDefinition{
+					"TestType": testType,
+				},
+				Name:     "testapi",
+				Consumes: design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{Verb: "GET", Path: ""}},
+								Payload: testType,
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("imports the strings package", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "user_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("strings.TrimSpace("))
+			Ω(content).Should(ContainSubstring("\t\"strings\"\n"))
+		})
+	})
+
+	Context("with an action with a user type payload accepting a single value", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			testType := &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type: design.Object{
+						"tags": &design.AttributeDefinition{
+							Type:     &design.Array{ElemType: &design.AttributeDefinition{Type: design.String}},
+							Metadata: dslengine.MetadataDefinition{"json:single-value": nil},
+						},
+					},
+				},
+				TypeName: "TestType",
+			}
+			design.Design = &design.APIDefinition{
+				Types: map[string]*design.UserTypeDefinition{
+					"TestType": testType,
+				},
+				Name:     "testapi",
+				Consumes: design.DefaultEncoders,
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{Verb: "GET", Path: ""}},
+								Payload: testType,
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("imports the encoding/json package", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "user_types.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("UnmarshalJSON("))
+			Ω(content).Should(ContainSubstring("\t\"encoding/json\"\n"))
+		})
+	})
+
+	Context("with an action response that defines headers", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			mt := &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type: design.Object{"name": {Type: design.String}},
+					},
+					TypeName: "Bottle",
+				},
+				Identifier: "application/vnd.bottle",
+			}
+			mt.Views = map[string]*design.ViewDefinition{
+				"default": {AttributeDefinition: mt.AttributeDefinition, Name: "default", Parent: mt},
+			}
+			design.ProjectedMediaTypes = make(design.MediaTypeRoot)
+			design.Design = &design.APIDefinition{
+				Name:       "testapi",
+				Consumes:   design.DefaultEncoders,
+				MediaTypes: map[string]*design.MediaTypeDefinition{"application/vnd.bottle": mt},
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{Verb: "GET", Path: ""},
+								},
+								Responses: map[string]*design.ResponseDefinition{
+									"OK": {
+										Name:      "OK",
+										Status:    200,
+										MediaType: "application/vnd.bottle",
+										Headers: &design.AttributeDefinition{
+											Type: design.Object{
+												"X-Count":      {Type: design.Integer},
+												"X-Request-Id": {Type: design.String},
+											},
+											Validation: &dslengine.ValidationDefinition{
+												Required: []string{"X-Count"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+		})
+
+		It("generates the result builder", func() {
+			Ω(genErr).Should(BeNil())
+			c, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(c)
+			Ω(content).Should(ContainSubstring(resultBuilder))
+			Ω(content).Should(ContainSubstring(resultDecoder))
+			Ω(content).Should(ContainSubstring("XCount int\n"))
+			Ω(content).Should(ContainSubstring("XRequestID *string\n"))
+		})
+	})
 })
 
 var _ = Describe("NewGenerator", func() {
@@ -619,3 +872,29 @@ const clientHeaderTmpl = `// Code generated by goagen {{ .version }}, DO NOT EDI
 // --design={{.design}}
 // --version={{.version}}
 `
+
+const resultBuilder = `func NewShowFooOKResult(body *Bottle, header http.Header) (*ShowFooOKResult, error) {
+	var err error
+	res := &ShowFooOKResult{Body: body}
+	if raw := header.Get("X-Count"); raw != "" {
+		if v, err2 := strconv.Atoi(raw); err2 == nil {
+			res.XCount = v
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("X-Count", raw, "integer"))
+		}
+	} else {
+		err = goa.MergeErrors(err, goa.MissingHeaderError("X-Count"))
+	}
+	if raw := header.Get("X-Request-Id"); raw != "" {
+		res.XRequestID = &raw
+	}
+	return res, err
+}`
+
+const resultDecoder = `func (c *Client) DecodeShowFooOKResult(resp *http.Response) (*ShowFooOKResult, error) {
+	body, err := c.DecodeBottle(resp)
+	if err != nil {
+		return nil, err
+	}
+	return NewShowFooOKResult(body, resp.Header)
+}`
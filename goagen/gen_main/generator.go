@@ -57,6 +57,8 @@ func Generate() (files []string, err error) {
 	set.BoolVar(&force, "force", false, "")
 	set.BoolVar(&regen, "regen", false, "")
 	set.Bool("notest", false, "")
+	set.Bool("fuzz", false, "")
+	set.Bool("json-pointer", false, "")
 	set.Parse(os.Args[1:])
 
 	if err := codegen.CheckVersion(ver); err != nil {
@@ -144,17 +146,9 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 		return "", err
 	}
 
-	elems := strings.Split(appPkg, "/")
-	pkgName := elems[len(elems)-1]
-	var imp string
-	if _, err := codegen.PackageSourcePath(appPkg); err == nil {
-		imp = appPkg
-	} else {
-		imp, err = codegen.PackagePath(outDir)
-		if err != nil {
-			return "", err
-		}
-		imp = path.Join(filepath.ToSlash(imp), appPkg)
+	pkgName, imp, err := appImport(appPkg, outDir)
+	if err != nil {
+		return "", err
 	}
 
 	imports := []*codegen.ImportSpec{
@@ -197,6 +191,21 @@ func GenerateController(force, regen bool, appPkg, outDir, pkg, name string, r *
 	return filename, nil
 }
 
+// appImport returns the name and import path of the generated "app" package. appPkg is either
+// the import path of the package or its path relative to outDir.
+func appImport(appPkg, outDir string) (string, string, error) {
+	elems := strings.Split(appPkg, "/")
+	name := elems[len(elems)-1]
+	if _, err := codegen.PackageSourcePath(appPkg); err == nil {
+		return name, appPkg, nil
+	}
+	imp, err := codegen.PackagePath(outDir)
+	if err != nil {
+		return "", "", err
+	}
+	return name, path.Join(filepath.ToSlash(imp), appPkg), nil
+}
+
 // Generate produces the skeleton main.
 func (g *Generator) Generate() (_ []string, err error) {
 	if g.API == nil {
@@ -227,7 +236,7 @@ func (g *Generator) Generate() (_ []string, err error) {
 		if err := os.MkdirAll(g.OutDir, 0755); err != nil {
 			return nil, err
 		}
-		if err = g.createMainFile(mainFile, funcMap(g.Target, nil)); err != nil {
+		if err = g.createMainFile(mainFile); err != nil {
 			return nil, err
 		}
 	}
@@ -256,12 +265,17 @@ func (g *Generator) Cleanup() {
 	g.genfiles = nil
 }
 
-func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) error {
+func (g *Generator) createMainFile(mainFile string) error {
 	g.genfiles = append(g.genfiles, mainFile)
 	file, err := codegen.SourceFileFor(mainFile)
 	if err != nil {
 		return err
 	}
+	pkgName, appPkg, err := appImport(g.Target, g.OutDir)
+	if err != nil {
+		return err
+	}
+	funcs := funcMap(pkgName, nil)
 	funcs["getPort"] = func(hostport string) string {
 		_, port, err := net.SplitHostPort(hostport)
 		if err != nil {
@@ -269,11 +283,6 @@ func (g *Generator) createMainFile(mainFile string, funcs template.FuncMap) erro
 		}
 		return port
 	}
-	outPkg, err := codegen.PackagePath(g.OutDir)
-	if err != nil {
-		return err
-	}
-	appPkg := path.Join(outPkg, "app")
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
@@ -185,6 +185,24 @@ var _ = Describe("Generate", func() {
 			})
 		})
 
+		Context("with a custom app package", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--pkg=myapp")
+			})
+
+			It("references the custom package", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`"` + testgenPackagePath + `/myapp"`))
+				Ω(string(content)).Should(MatchRegexp(`myapp\.MountFirstController\(service, c[0-9]*\)`))
+				content, err = ioutil.ReadFile(filepath.Join(outDir, "first.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(`"` + testgenPackagePath + `/myapp"`))
+				Ω(string(content)).Should(ContainSubstring("ctx *myapp.AlphaFirstContext"))
+			})
+		})
+
 	})
 })
 
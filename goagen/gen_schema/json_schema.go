@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/goadesign/goa/design"
 )
@@ -42,6 +43,8 @@ type (
 		Maximum              *float64      `json:"maximum,omitempty"`
 		MinLength            *int          `json:"minLength,omitempty"`
 		MaxLength            *int          `json:"maxLength,omitempty"`
+		MinItems             *int          `json:"minItems,omitempty"`
+		MaxItems             *int          `json:"maxItems,omitempty"`
 		Required             []string      `json:"required,omitempty"`
 		AdditionalProperties bool          `json:"additionalProperties,omitempty"`
 
@@ -97,11 +100,16 @@ const SchemaRef = "http://json-schema.org/draft-04/hyper-schema"
 var (
 	// Definitions contains the generated JSON schema definitions
 	Definitions map[string]*JSONSchema
+
+	// requestTypes maps the names of the definitions referred to by the schemas produced by
+	// RequestTypeSchema to the types they are derived from.
+	requestTypes map[string]*design.UserTypeDefinition
 )
 
 // Initialize the global variables
 func init() {
 	Definitions = make(map[string]*JSONSchema)
+	requestTypes = make(map[string]*design.UserTypeDefinition)
 }
 
 // NewJSONSchema instantiates a new JSON schema.
@@ -277,6 +285,69 @@ func GenerateTypeDefinition(api *design.APIDefinition, ut *design.UserTypeDefini
 	buildAttributeSchema(api, s, ut.AttributeDefinition)
 }
 
+// RequestTypeSchema produces the JSON schema of request bodies of the given type. It is the same
+// as the schema produced by TypeSchema unless the type requires read-only attributes. In this
+// case the schema refers to a separate definition named after the type with the "Request" suffix
+// that does not require the read-only attributes. The definition is added to Definitions by
+// GenerateRequestDefinitions.
+func RequestTypeSchema(api *design.APIDefinition, ut *design.UserTypeDefinition) *JSONSchema {
+	s := TypeSchema(api, ut)
+	obj := ut.ToObject()
+	if obj == nil || s.Ref != TypeRef(api, ut) {
+		return s
+	}
+	if len(requestRequired(ut)) == len(Definitions[ut.TypeName].Required) {
+		return s
+	}
+	name := ut.TypeName + "Request"
+	requestTypes[name] = ut
+	s.Ref = fmt.Sprintf("#/definitions/%s", name)
+	return s
+}
+
+// GenerateRequestDefinitions adds the definitions referred to by the schemas produced by
+// RequestTypeSchema to Definitions. It must be called once all the other definitions have been
+// generated so that it can check that the names of the request definitions are not already used
+// by the definitions of other types.
+func GenerateRequestDefinitions() error {
+	defer func() { requestTypes = make(map[string]*design.UserTypeDefinition) }()
+	names := make([]string, 0, len(requestTypes))
+	for name := range requestTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ut := requestTypes[name]
+		src, ok := Definitions[ut.TypeName]
+		if !ok {
+			// Definitions was reset since the schema was produced.
+			continue
+		}
+		if _, ok := Definitions[name]; ok {
+			return fmt.Errorf("the definition %q of the request bodies of type %q conflicts with the definition of another type with the same name", name, ut.TypeName)
+		}
+		rs := *src
+		rs.Title = name
+		rs.Required = requestRequired(ut)
+		Definitions[name] = &rs
+	}
+	return nil
+}
+
+// requestRequired returns the names of the attributes required by the definition of the given
+// type that are not read-only.
+func requestRequired(ut *design.UserTypeDefinition) []string {
+	obj := ut.ToObject()
+	var required []string
+	for _, n := range Definitions[ut.TypeName].Required {
+		if att, ok := obj[n]; ok && isReadOnly(att) {
+			continue
+		}
+		required = append(required, n)
+	}
+	return required
+}
+
 // TypeSchema produces the JSON schema corresponding to the given data type.
 func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	s := NewJSONSchema()
@@ -302,6 +373,9 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	case design.Object:
 		s.Type = JSONObject
 		for n, at := range actual {
+			if isExcluded(at) {
+				continue
+			}
 			prop := NewJSONSchema()
 			buildAttributeSchema(api, prop, at)
 			s.Properties[n] = prop
@@ -358,6 +432,16 @@ func (s *JSONSchema) createMergeItems(other *JSONSchema) mergeItems {
 			needed: (s.MaxLength == nil && other.MaxLength != nil) ||
 				(s.MaxLength != nil && other.MaxLength != nil && *s.MaxLength > *other.MaxLength),
 		},
+		{
+			a: s.MinItems, b: other.MinItems,
+			needed: (s.MinItems == nil && other.MinItems != nil) ||
+				(s.MinItems != nil && other.MinItems != nil && *s.MinItems > *other.MinItems),
+		},
+		{
+			a: s.MaxItems, b: other.MaxItems,
+			needed: (s.MaxItems == nil && other.MaxItems != nil) ||
+				(s.MaxItems != nil && other.MaxItems != nil && *s.MaxItems > *other.MaxItems),
+		},
 	}
 }
 
@@ -415,6 +499,8 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Maximum:              s.Maximum,
 		MinLength:            s.MinLength,
 		MaxLength:            s.MaxLength,
+		MinItems:             s.MinItems,
+		MaxItems:             s.MaxItems,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
 	}
@@ -445,6 +531,7 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	}
 	s.DefaultValue = toStringMap(at.DefaultValue)
 	s.Description = at.Description
+	s.ReadOnly = isReadOnly(at)
 	s.Example = at.GenerateExample(api.RandomGenerator(), nil)
 	val := at.Validation
 	if val == nil {
@@ -460,15 +547,45 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 		s.Maximum = val.Maximum
 	}
 	if val.MinLength != nil {
-		s.MinLength = val.MinLength
+		if at.Type.IsArray() {
+			s.MinItems = val.MinLength
+		} else {
+			s.MinLength = val.MinLength
+		}
 	}
 	if val.MaxLength != nil {
-		s.MaxLength = val.MaxLength
+		if at.Type.IsArray() {
+			s.MaxItems = val.MaxLength
+		} else {
+			s.MaxLength = val.MaxLength
+		}
 	}
 	s.Required = val.Required
+	if obj := at.Type.ToObject(); obj != nil && len(val.Required) > 0 {
+		s.Required = nil
+		for _, r := range val.Required {
+			if att, ok := obj[r]; ok && isExcluded(att) {
+				continue
+			}
+			s.Required = append(s.Required, r)
+		}
+	}
 	return s
 }
 
+// isReadOnly returns true if the attribute is only sent in responses.
+func isReadOnly(at *design.AttributeDefinition) bool {
+	_, ok := at.Metadata["swagger:read-only"]
+	return ok
+}
+
+// isExcluded returns true if the attribute is never serialized, that is if its JSON struct tag
+// is "-".
+func isExcluded(at *design.AttributeDefinition) bool {
+	tag, ok := at.Metadata["struct:tag:json"]
+	return ok && strings.Join(tag, ",") == "-"
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -46,6 +46,88 @@ var _ = Describe("TypeSchema", func() {
 		})
 	})
 
+	Context("with an attribute excluded from serialization", func() {
+		var ut *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			ut = Type("Versioned", func() {
+				Attribute("name", design.String)
+				Attribute("version", design.Integer, func() {
+					Metadata("struct:tag:json", "-")
+				})
+				Required("name", "version")
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = ut.Type
+		})
+
+		It("omits the attribute from the schema", func() {
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Properties).Should(HaveKey("name"))
+			Ω(s.Properties).ShouldNot(HaveKey("version"))
+		})
+
+		It("does not list the attribute as required", func() {
+			genschema.GenerateTypeDefinition(design.Design, ut)
+			Ω(genschema.Definitions).Should(HaveKey("Versioned"))
+			Ω(genschema.Definitions["Versioned"].Required).Should(Equal([]string{"name"}))
+		})
+	})
+
+	Context("with a required read-only attribute", func() {
+		var ut *design.UserTypeDefinition
+
+		BeforeEach(func() {
+			ut = Type("Account", func() {
+				Attribute("id", design.Integer, func() {
+					Metadata("swagger:read-only")
+				})
+				Attribute("name", design.String)
+				Required("id", "name")
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = ut.Type
+		})
+
+		It("marks the attribute as read-only", func() {
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Properties).Should(HaveKey("id"))
+			Ω(s.Properties["id"].ReadOnly).Should(BeTrue())
+			Ω(s.Properties["name"].ReadOnly).Should(BeFalse())
+		})
+
+		It("does not require the attribute in request bodies", func() {
+			rs := genschema.RequestTypeSchema(design.Design, ut)
+			Ω(rs.Ref).Should(Equal("#/definitions/AccountRequest"))
+			Ω(genschema.GenerateRequestDefinitions()).ShouldNot(HaveOccurred())
+			Ω(genschema.Definitions["AccountRequest"].Required).Should(Equal([]string{"name"}))
+			Ω(genschema.Definitions["Account"].Required).Should(Equal([]string{"id", "name"}))
+		})
+	})
+
+	Context("with an array attribute with length validations", func() {
+		BeforeEach(func() {
+			ut := Type("Tagged", func() {
+				Attribute("tags", ArrayOf(design.String), func() {
+					MinLength(1)
+					MaxLength(3)
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			typ = ut.Type
+		})
+
+		It("describes the number of items", func() {
+			Ω(s).ShouldNot(BeNil())
+			Ω(s.Properties).Should(HaveKey("tags"))
+			tags := s.Properties["tags"]
+			Ω(tags.MinLength).Should(BeNil())
+			Ω(tags.MaxLength).Should(BeNil())
+			Ω(*tags.MinItems).Should(Equal(1))
+			Ω(*tags.MaxItems).Should(Equal(3))
+		})
+	})
+
 	Context("with a media type with self-referencing attributes", func() {
 		BeforeEach(func() {
 			MediaType("application/vnd.menu+json", func() {
@@ -0,0 +1,17 @@
+/*
+Package genstatus provides a generator for the API status table.
+The status table maps each endpoint identified by the resource and action design names separated
+with a dot (e.g. "bottle.show") to the list of HTTP status codes the endpoint may respond with,
+successes and errors alike, sorted by status code. The table is written as JSON to the file
+status/status.json under the output directory, for example:
+
+	{
+	  "bottle.show": [
+	    {"status": 200, "name": "OK", "description": "OK", "media_type": "application/vnd.bottle"},
+	    {"status": 404, "name": "NotFound", "description": "Not Found"}
+	  ]
+	}
+
+The table is meant to be consumed by tools such as changelog or SLA documentation generators.
+*/
+package genstatus
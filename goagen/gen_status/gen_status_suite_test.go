@@ -0,0 +1,13 @@
+package genstatus_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenStatus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenStatus Suite")
+}
@@ -0,0 +1,142 @@
+package genstatus
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// NewGenerator returns an initialized instance of a status table generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+type (
+	// Generator is the status table generator.
+	Generator struct {
+		API      *design.APIDefinition // The API definition
+		OutDir   string                // Path to output directory
+		genfiles []string              // Generated files
+	}
+
+	// StatusInfo describes a HTTP status code an endpoint may respond with.
+	StatusInfo struct {
+		// Status is the HTTP status code.
+		Status int `json:"status"`
+		// Name is the name of the response in the design.
+		Name string `json:"name"`
+		// Description is the response description, defaults to the status text.
+		Description string `json:"description"`
+		// MediaType is the identifier of the response media type if any.
+		MediaType string `json:"media_type,omitempty"`
+	}
+
+	// byStatus makes it possible to sort status infos by status code.
+	byStatus []*StatusInfo
+)
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, ver string
+	set := flag.NewFlagSet("status", flag.PanicOnError)
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.String("design", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{OutDir: outDir, API: design.Design}
+
+	return g.Generate()
+}
+
+// Generate produces the status table file.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	js, err := json.MarshalIndent(StatusTable(g.API), "", "  ")
+	if err != nil {
+		return
+	}
+
+	g.OutDir = filepath.Join(g.OutDir, "status")
+	os.RemoveAll(g.OutDir)
+	os.MkdirAll(g.OutDir, 0755)
+	g.genfiles = append(g.genfiles, g.OutDir)
+	statusFile := filepath.Join(g.OutDir, "status.json")
+	if err = ioutil.WriteFile(statusFile, js, 0644); err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, statusFile)
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// StatusTable returns the HTTP status codes of the responses of each API endpoint indexed by
+// endpoint name. The endpoint name is made of the resource and action design names separated
+// with a dot. The status codes of each endpoint are sorted in ascending order.
+func StatusTable(api *design.APIDefinition) map[string][]*StatusInfo {
+	table := make(map[string][]*StatusInfo)
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			var infos []*StatusInfo
+			a.IterateResponses(func(r *design.ResponseDefinition) error {
+				desc := r.Description
+				if desc == "" {
+					desc = http.StatusText(r.Status)
+				}
+				infos = append(infos, &StatusInfo{
+					Status:      r.Status,
+					Name:        r.Name,
+					Description: desc,
+					MediaType:   r.MediaType,
+				})
+				return nil
+			})
+			sort.Stable(byStatus(infos))
+			table[res.Name+"."+a.Name] = infos
+			return nil
+		})
+	})
+	return table
+}
+
+func (b byStatus) Len() int           { return len(b) }
+func (b byStatus) Less(i, j int) bool { return b[i].Status < b[j].Status }
+func (b byStatus) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
@@ -0,0 +1,89 @@
+package genstatus_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_status"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var files []string
+	var genErr error
+	var workspace *codegen.Workspace
+	var testPkg *codegen.Package
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		testPkg, err = workspace.NewPackage("statustest")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + testPkg.Abs(), "--design=foo", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genstatus.Generate()
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with an API defining responses", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			API("test api", func() {})
+			BottleMedia := MediaType("application/vnd.bottle", func() {
+				Attributes(func() {
+					Attribute("name", String)
+				})
+				View("default", func() {
+					Attribute("name")
+				})
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/:id"))
+					Response(NotFound, func() {
+						Description("Bottle not found")
+					})
+					Response(OK, BottleMedia)
+					Response(BadRequest, ErrorMedia)
+				})
+				Action("delete", func() {
+					Routing(DELETE("/:id"))
+					Response(NoContent)
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("writes the status table", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(2))
+			content, err := ioutil.ReadFile(filepath.Join(testPkg.Abs(), "status", "status.json"))
+			Ω(err).ShouldNot(HaveOccurred())
+			var table map[string][]*genstatus.StatusInfo
+			Ω(json.Unmarshal(content, &table)).ShouldNot(HaveOccurred())
+			Ω(table).Should(HaveLen(2))
+			Ω(table["bottle.delete"]).Should(Equal([]*genstatus.StatusInfo{
+				{Status: 204, Name: "NoContent", Description: "No Content"},
+			}))
+			Ω(table["bottle.show"]).Should(Equal([]*genstatus.StatusInfo{
+				{Status: 200, Name: "OK", Description: "OK", MediaType: "application/vnd.bottle"},
+				{Status: 400, Name: "BadRequest", Description: "Bad Request", MediaType: "application/vnd.goa.error"},
+				{Status: 404, Name: "NotFound", Description: "Bottle not found"},
+			}))
+		})
+	})
+})
@@ -3,6 +3,7 @@ package genswagger
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"sort"
 	"strconv"
 	"strings"
@@ -12,6 +13,16 @@ import (
 	"github.com/goadesign/goa/goagen/gen_schema"
 )
 
+const (
+	// DefaultVersion is the version written in the info object of the specification of APIs
+	// that do not define a version.
+	DefaultVersion = "1.0"
+
+	// DefaultResponseDescription is the description of the default response added to the
+	// operations of actions that do not define a success response.
+	DefaultResponseDescription = "Response not described in the design"
+)
+
 type (
 	// Swagger represents an instance of a swagger object.
 	// See https://swagger.io/specification/
@@ -86,7 +97,8 @@ type (
 		OperationID string `json:"operationId,omitempty"`
 		// Consumes is a list of MIME types the operation can consume.
 		Consumes []string `json:"consumes,omitempty"`
-		// Produces is a list of MIME types the operation can produce.
+		// Produces is a list of MIME types the operation can produce. An empty non-nil list
+		// clears the list defined at the top level of the specification.
 		Produces []string `json:"produces,omitempty"`
 		// Parameters is a list of parameters that are applicable for this operation.
 		Parameters []*Parameter `json:"parameters,omitempty"`
@@ -335,7 +347,16 @@ func (p Path) MarshalJSON() ([]byte, error) {
 
 // MarshalJSON returns the JSON encoding of o.
 func (o Operation) MarshalJSON() ([]byte, error) {
-	return marshalJSON(_Operation(o), o.Extensions)
+	ext := o.Extensions
+	if o.Produces != nil && len(o.Produces) == 0 {
+		// An empty list overrides the MIME types defined at the top level.
+		ext = make(map[string]interface{}, len(o.Extensions)+1)
+		for k, v := range o.Extensions {
+			ext[k] = v
+		}
+		ext["produces"] = []string{}
+	}
+	return marshalJSON(_Operation(o), ext)
 }
 
 // MarshalJSON returns the JSON encoding of p.
@@ -387,6 +408,11 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 	for _, p := range api.Produces {
 		produces = append(produces, p.MIMETypes...)
 	}
+	version := api.Version
+	if version == "" {
+		// The version is required by the specification
+		version = DefaultVersion
+	}
 	s := &Swagger{
 		Swagger: "2.0",
 		Info: &Info{
@@ -395,7 +421,7 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 			TermsOfService: api.TermsOfService,
 			Contact:        api.Contact,
 			License:        api.License,
-			Version:        api.Version,
+			Version:        version,
 			Extensions:     extensionsFromDefinition(api.Metadata),
 		},
 		Host:                api.Host,
@@ -425,6 +451,9 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 		return nil, err
 	}
 	err = api.IterateResources(func(res *design.ResourceDefinition) error {
+		if res.Docs != nil {
+			s.Tags = withResourceTag(s.Tags, res)
+		}
 		for k, v := range extensionsFromDefinition(res.Metadata) {
 			s.Paths[k] = v
 		}
@@ -452,6 +481,9 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := genschema.GenerateRequestDefinitions(); err != nil {
+		return nil, err
+	}
 	if len(genschema.Definitions) > 0 {
 		s.Definitions = make(map[string]*genschema.JSONSchema)
 		for n, d := range genschema.Definitions {
@@ -605,6 +637,28 @@ func tagsFromDefinition(mdata dslengine.MetadataDefinition) (tags []*Tag) {
 	return
 }
 
+// withResourceTag adds the tag named after the resource that operations are tagged with by default
+// to tags. The tag description and external docs are initialized from the resource definition
+// unless already set by a tag of the same name defined in the API.
+func withResourceTag(tags []*Tag, res *design.ResourceDefinition) []*Tag {
+	for _, t := range tags {
+		if t.Name == res.Name {
+			if t.Description == "" {
+				t.Description = res.Description
+			}
+			if t.ExternalDocs == nil {
+				t.ExternalDocs = docsFromDefinition(res.Docs)
+			}
+			return tags
+		}
+	}
+	return append(tags, &Tag{
+		Name:         res.Name,
+		Description:  res.Description,
+		ExternalDocs: docsFromDefinition(res.Docs),
+	})
+}
+
 func tagNamesFromDefinitions(mdatas ...dslengine.MetadataDefinition) (tagNames []string) {
 	for _, mdata := range mdatas {
 		tags := tagsFromDefinition(mdata)
@@ -659,10 +713,13 @@ func paramsFromDefinition(params *design.AttributeDefinition, path string) ([]*P
 	if obj == nil {
 		return nil, fmt.Errorf("invalid parameters definition, not an object")
 	}
-	res := make([]*Parameter, len(obj))
-	i := 0
+	res := make([]*Parameter, 0, len(obj))
 	wildcards := design.ExtractWildcards(path)
 	obj.IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		if _, ok := at.Metadata["param:basic-auth"]; ok {
+			// Described as a basic security requirement of the operation
+			return nil
+		}
 		in := "query"
 		required := params.IsRequired(n)
 		for _, w := range wildcards {
@@ -672,21 +729,62 @@ func paramsFromDefinition(params *design.AttributeDefinition, path string) ([]*P
 				break
 			}
 		}
-		param := paramFor(at, n, in, required)
-		res[i] = param
-		i++
+		if sources, ok := at.Metadata["param:sources"]; ok && len(sources) > 0 {
+			res = append(res, paramsFromSources(at, n, sources, in == "path", required)...)
+			return nil
+		}
+		if q, ok := at.Metadata["param:query"]; ok && len(q) > 0 && in == "query" {
+			n = q[0]
+		}
+		res = append(res, paramFor(at, n, in, required))
 		return nil
 	})
 	return res, nil
 }
 
+// paramsFromSources describes the param with the given name whose value is read from the sources
+// listed in its "param:sources" metadata, one parameter per source. isPath indicates whether the
+// route defines a path parameter with the name of the param, such a parameter is always described.
+// A required param is only described as required when it has a single source, with several
+// sources any of them may provide it.
+func paramsFromSources(at *design.AttributeDefinition, name string, sources []string, isPath, required bool) []*Parameter {
+	var params []*Parameter
+	if isPath {
+		params = append(params, paramFor(at, name, "path", true))
+	}
+	for _, s := range sources {
+		in, n := s, name
+		if i := strings.Index(s, ":"); i > 0 {
+			in, n = s[:i], s[i+1:]
+		}
+		if in == "path" {
+			continue
+		}
+		params = append(params, paramFor(at, n, in, required && len(sources) == 1))
+	}
+	return params
+}
+
 func paramsFromHeaders(action *design.ActionDefinition) []*Parameter {
 	params := []*Parameter{}
+	idempotencyKey := true
 	action.IterateHeaders(func(name string, required bool, header *design.AttributeDefinition) error {
 		p := paramFor(header, name, "header", required)
 		params = append(params, p)
+		if http.CanonicalHeaderKey(name) == "Idempotency-Key" {
+			idempotencyKey = false
+		}
 		return nil
 	})
+	if v, ok := action.Metadata["idempotency:key"]; ok && idempotencyKey {
+		params = append(params, &Parameter{
+			In:          "header",
+			Name:        "Idempotency-Key",
+			Description: "Key identifying the request so that retries are processed only once",
+			Required:    len(v) > 0 && v[0] == "required",
+			Type:        "string",
+		})
+	}
 	return params
 }
 
@@ -704,10 +802,109 @@ func paramFor(at *design.AttributeDefinition, name, in string, required bool) *P
 		p.CollectionFormat = "multi"
 	}
 	p.Extensions = extensionsFromDefinition(at.Metadata)
+	// Swagger 2.0 parameters have no example field, tools read the x-example extension instead.
+	var example interface{}
+	if at.Example != nil && at.Example != "-" {
+		example = toStringMap(at.Example)
+	}
 	initValidations(at, p)
+	if p.Format == "" {
+		p.Format = typeFormat(at.Type)
+	}
+	if f := bigNumFormat(at); f != "" {
+		if p.Items != nil {
+			p.Items.Format = f
+		} else {
+			p.Format = f
+		}
+	}
+	if vals, ok := at.Metadata["param:bool-values"]; ok && len(vals) == 2 && at.Type == design.Boolean {
+		// The param is sent as one of the two strings.
+		p.Type = "string"
+		p.Enum = []interface{}{vals[0], vals[1]}
+		if b, ok := p.Default.(bool); ok {
+			if b {
+				p.Default = vals[0]
+			} else {
+				p.Default = vals[1]
+			}
+		}
+		if b, ok := example.(bool); ok {
+			if b {
+				example = vals[0]
+			} else {
+				example = vals[1]
+			}
+		}
+	}
+	if _, ok := at.Metadata["param:flag"]; ok && in == "query" {
+		p.AllowEmptyValue = true
+	}
+	if vals, ok := at.Metadata["param:enum-map"]; ok && at.Type.IsPrimitive() {
+		// The param is sent as one of the wire values which are strings. The generated decoder
+		// also accepts the internal values so list them after the wire values.
+		p.Type = "string"
+		p.Format = ""
+		p.Minimum = nil
+		p.Maximum = nil
+		wires := make([]string, 0, len(vals))
+		internals := make([]string, 0, len(vals))
+		for _, v := range vals {
+			kv := strings.SplitN(v, "=", 2)
+			wires = append(wires, kv[0])
+			if len(kv) == 2 {
+				internals = append(internals, kv[1])
+			}
+		}
+		p.Enum = nil
+		seen := make(map[string]bool)
+		for _, v := range append(wires, internals...) {
+			if !seen[v] {
+				seen[v] = true
+				p.Enum = append(p.Enum, v)
+			}
+		}
+		if p.Default != nil {
+			p.Default = wireValue(vals, p.Default)
+		}
+		if example != nil {
+			example = wireValue(vals, example)
+		}
+	}
+	if _, ok := p.Extensions["x-example"]; example != nil && !ok {
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions["x-example"] = example
+	}
 	return p
 }
 
+// wireValue returns the wire value mapped to the given internal value by the "param:enum-map"
+// metadata values, the internal value itself if there is none.
+func wireValue(vals []string, internal interface{}) interface{} {
+	for _, v := range vals {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) == 2 && kv[1] == fmt.Sprintf("%v", internal) {
+			return kv[0]
+		}
+	}
+	return internal
+}
+
+// bigNumFormat returns the format of params whose values are parsed into math/big numbers, the
+// empty string for other params.
+func bigNumFormat(at *design.AttributeDefinition) string {
+	n, ok := at.Metadata["param:bignum"]
+	if !ok {
+		return ""
+	}
+	if len(n) > 0 && n[0] == "rat" {
+		return "big-rational"
+	}
+	return "big-integer"
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -747,15 +944,30 @@ func toString(val interface{}) string {
 func itemsFromDefinition(at *design.AttributeDefinition) *Items {
 	items := &Items{Type: at.Type.Name()}
 	initValidations(at, items)
+	if items.Format == "" {
+		items.Format = typeFormat(at.Type)
+	}
 	if at.Type.IsArray() {
 		items.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
 	}
 	return items
 }
 
+// typeFormat returns the format of the string values of the given type, the empty string if the
+// type is not described by a format.
+func typeFormat(t design.DataType) string {
+	switch t.Kind() {
+	case design.DateTimeKind:
+		return "date-time"
+	case design.UUIDKind:
+		return "uuid"
+	}
+	return ""
+}
+
 func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design.ResponseDefinition) (*Response, error) {
 	var schema *genschema.JSONSchema
-	if r.MediaType != "" {
+	if hasBody(r) {
 		if mt, ok := api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]; ok {
 			view := r.ViewName
 			if view == "" {
@@ -777,6 +989,26 @@ func responseSpecFromDefinition(s *Swagger, api *design.APIDefinition, r *design
 	}, nil
 }
 
+// hasBody returns true if the response described by r has a body, that is if it defines a media
+// type and its status code allows for a body.
+func hasBody(r *design.ResponseDefinition) bool {
+	if r.MediaType == "" {
+		return false
+	}
+	return r.Status >= 200 && r.Status != http.StatusNoContent && r.Status != http.StatusNotModified
+}
+
+// hasSuccess returns true if the action defines at least one response whose status code is not an
+// error status code.
+func hasSuccess(action *design.ActionDefinition) bool {
+	for _, r := range action.Responses {
+		if r.Status < 400 {
+			return true
+		}
+	}
+	return false
+}
+
 func responseFromDefinition(s *Swagger, api *design.APIDefinition, r *design.ResponseDefinition) (*Response, error) {
 	var (
 		response *Response
@@ -907,9 +1139,15 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		}
 		responses[strconv.Itoa(r.Status)] = resp
 	}
+	if !hasSuccess(action) {
+		// The DSL does not require actions to define responses while the specification
+		// requires at least one and the response of successful operations should be
+		// described.
+		responses["default"] = &Response{Description: DefaultResponseDescription}
+	}
 
 	if action.Payload != nil {
-		payloadSchema := genschema.TypeSchema(api, action.Payload)
+		payloadSchema := genschema.RequestTypeSchema(api, action.Payload)
 		pp := &Parameter{
 			Name:        "payload",
 			In:          "body",
@@ -932,10 +1170,7 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		operationID = fmt.Sprintf("%s#%d", operationID, index)
 	}
 
-	schemes := action.Schemes
-	if len(schemes) == 0 {
-		schemes = api.Schemes
-	}
+	schemes := action.EffectiveSchemes()
 
 	operation := &Operation{
 		Tags:         tagNames,
@@ -946,12 +1181,13 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		Parameters:   params,
 		Responses:    responses,
 		Schemes:      schemes,
-		Deprecated:   false,
+		Deprecated:   action.IsDeprecated(),
 		Extensions:   extensionsFromDefinition(route.Metadata),
 	}
 
 	computeProduces(operation, s, action)
 	applySecurity(operation, action.Security)
+	applyBasicAuthParams(operation, s, api, action)
 
 	key := design.WildcardRegex.ReplaceAllStringFunc(
 		route.FullPath(),
@@ -1002,12 +1238,17 @@ func computeProduces(operation *Operation, s *Swagger, action *design.ActionDefi
 	produces := make(map[string]bool)
 	producesSorted := make([]string, 0)
 	action.IterateResponses(func(resp *design.ResponseDefinition) error {
-		if resp.MediaType != "" {
+		if hasBody(resp) {
 			produces[resp.MediaType] = true
 			producesSorted = append(producesSorted, resp.MediaType)
 		}
 		return nil
 	})
+	if len(produces) == 0 && len(action.Responses) > 0 && len(s.Produces) > 0 {
+		// None of the responses carry a body, clear the MIME types defined at the top level.
+		operation.Produces = []string{}
+		return
+	}
 	subset := true
 	for p := range produces {
 		found := false
@@ -1049,6 +1290,39 @@ func applySecurity(operation *Operation, security *design.SecurityDefinition) {
 	}
 }
 
+// applyBasicAuthParams describes the HTTP Basic auth credentials read by the action params with
+// the param:basic-auth metadata as a basic security requirement of the operation. It uses the
+// basic auth security scheme of the API if there is one.
+func applyBasicAuthParams(operation *Operation, s *Swagger, api *design.APIDefinition, action *design.ActionDefinition) {
+	if action.Params == nil || len(operation.Security) > 0 {
+		return
+	}
+	found := false
+	for _, p := range action.Params.Type.ToObject() {
+		if _, ok := p.Metadata["param:basic-auth"]; ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+	name := "basic"
+	for _, scheme := range api.SecuritySchemes {
+		if scheme.Kind == design.BasicAuthSecurityKind {
+			name = scheme.SchemeName
+			break
+		}
+	}
+	if _, ok := s.SecurityDefinitions[name]; !ok {
+		if s.SecurityDefinitions == nil {
+			s.SecurityDefinitions = make(map[string]*SecurityDefinition)
+		}
+		s.SecurityDefinitions[name] = &SecurityDefinition{Type: "basic"}
+	}
+	operation.Security = []map[string][]string{{name: {}}}
+}
+
 func scopesList(scopes []string) string {
 	sort.Strings(scopes)
 
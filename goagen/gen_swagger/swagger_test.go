@@ -54,6 +54,18 @@ var _ = Describe("New", func() {
 		swagger, newErr = genswagger.New(Design)
 	})
 
+	Context("with an API that does not define a version", func() {
+		BeforeEach(func() {
+			API("test", func() {})
+		})
+
+		It("uses the default version", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(swagger.Info.Version).Should(Equal(genswagger.DefaultVersion))
+			validateSwagger(swagger)
+		})
+	})
+
 	Context("with a valid API definition", func() {
 		const (
 			title        = "title"
@@ -70,6 +82,7 @@ var _ = Describe("New", func() {
 			tag          = "tag"
 			docDesc      = "doc description"
 			docURL       = "http://docURL.com"
+			version      = "1.2.0"
 		)
 
 		BeforeEach(func() {
@@ -97,6 +110,7 @@ var _ = Describe("New", func() {
 				Host(host)
 				Scheme(scheme)
 				BasePath(basePath)
+				Version(version)
 			})
 		})
 
@@ -117,7 +131,7 @@ var _ = Describe("New", func() {
 						Name: license,
 						URL:  licenseURL,
 					},
-					Version: "",
+					Version: version,
 				},
 				Host:     host,
 				BasePath: basePath,
@@ -214,6 +228,31 @@ var _ = Describe("New", func() {
 			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
 		})
 
+		Context("with an action that only responds without content", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							DELETE("/items"),
+						)
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("clears the produces MIME types and omits the response schema", func() {
+				p := swagger.Paths["/items"].(*genswagger.Path)
+				Ω(p.Delete).ShouldNot(BeNil())
+				Ω(p.Delete.Produces).ShouldNot(BeNil())
+				Ω(p.Delete.Produces).Should(BeEmpty())
+				Ω(p.Delete.Responses["204"].Schema).Should(BeNil())
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"produces":[]`),
+				})
+			})
+
+		})
+
 		Context("with required payload", func() {
 			BeforeEach(func() {
 				p := Type("RequiredPayload", func() {
@@ -236,6 +275,718 @@ var _ = Describe("New", func() {
 			})
 		})
 
+		Context("with a payload requiring a read-only attribute", func() {
+			BeforeEach(func() {
+				p := Type("Bottle", func() {
+					Attribute("id", Integer, func() {
+						Metadata("swagger:read-only")
+					})
+					Attribute("name", String)
+					Required("id", "name")
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Payload(p)
+					})
+				})
+			})
+
+			It("does not require the attribute in the request schema", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				put := swagger.Paths[""].(*genswagger.Path).Put
+				Ω(put).ShouldNot(BeNil())
+				Ω(put.Parameters).Should(HaveLen(1))
+				Ω(put.Parameters[0].Schema.Ref).Should(Equal("#/definitions/BottleRequest"))
+				Ω(swagger.Definitions).Should(HaveKey("BottleRequest"))
+				Ω(swagger.Definitions["BottleRequest"].Required).Should(Equal([]string{"name"}))
+				Ω(swagger.Definitions["BottleRequest"].Properties["id"].ReadOnly).Should(BeTrue())
+				Ω(swagger.Definitions["Bottle"].Required).Should(Equal([]string{"id", "name"}))
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"readOnly":true`),
+				})
+			})
+		})
+
+		Context("with a type named like the request definition of another type", func() {
+			BeforeEach(func() {
+				p := Type("Bottle", func() {
+					Attribute("id", Integer, func() {
+						Metadata("swagger:read-only")
+					})
+					Required("id")
+				})
+				other := Type("BottleRequest", func() {
+					Attribute("name", String)
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Payload(p)
+					})
+					Action("other", func() {
+						Routing(
+							POST("/"),
+						)
+						Payload(other)
+					})
+				})
+			})
+
+			It("reports the conflict", func() {
+				Ω(newErr).Should(HaveOccurred())
+				Ω(newErr.Error()).Should(ContainSubstring(`"BottleRequest"`))
+			})
+		})
+
+		Context("with a GET payload", func() {
+			BeforeEach(func() {
+				p := Type("FilterPayload", func() {
+					Member("m1", String)
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/"),
+						)
+						Payload(p)
+					})
+				})
+			})
+
+			It("describes the body parameter", func() {
+				Ω(swagger.Paths[""]).ShouldNot(BeNil())
+				get := swagger.Paths[""].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(1))
+				Ω(get.Parameters[0].In).Should(Equal("body"))
+				Ω(get.Parameters[0].Required).Should(BeTrue())
+			})
+		})
+
+		Context("with a deprecated action", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/"),
+						)
+						Metadata("swagger:deprecated")
+					})
+				})
+			})
+
+			It("marks the operation as deprecated", func() {
+				Ω(swagger.Paths[""]).ShouldNot(BeNil())
+				get := swagger.Paths[""].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Deprecated).Should(BeTrue())
+			})
+		})
+
+		Context("with two versions of the same resource", func() {
+			BeforeEach(func() {
+				for _, v := range []string{"v1", "v2"} {
+					v := v
+					Resource("bottle_"+v, func() {
+						Version(v)
+						BasePath("/bottles")
+						Action("show", func() {
+							Routing(GET("/:id"))
+							Params(func() {
+								Param("id", Integer)
+							})
+						})
+					})
+				}
+			})
+
+			It("prefixes the paths with the resource versions", func() {
+				Ω(swagger.BasePath).Should(Equal(basePath))
+				Ω(swagger.Paths).Should(HaveLen(2))
+				for _, v := range []string{"v1", "v2"} {
+					key := "/" + v + "/bottles/{id}"
+					Ω(swagger.Paths).Should(HaveKey(key))
+					get := swagger.Paths[key].(*genswagger.Path).Get
+					Ω(get).ShouldNot(BeNil())
+					Ω(get.Parameters).Should(HaveLen(1))
+					Ω(get.Parameters[0].In).Should(Equal("path"))
+					Ω(get.Parameters[0].Name).Should(Equal("id"))
+				}
+			})
+		})
+
+		Context("with a resource declaring multiple schemes", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Scheme("http", "https", "http")
+					Action("secure", func() {
+						Routing(GET("/secure"))
+					})
+				})
+				Resource("other", func() {
+					Action("act", func() {
+						Routing(GET("/other"))
+					})
+				})
+			})
+
+			It("lists the schemes of each operation", func() {
+				Ω(swagger.Schemes).Should(Equal([]string{"https"}))
+				Ω(swagger.Paths["/secure"].(*genswagger.Path).Get.Schemes).Should(Equal([]string{"http", "https"}))
+				Ω(swagger.Paths["/other"].(*genswagger.Path).Get.Schemes).Should(Equal([]string{"https"}))
+			})
+		})
+
+		Context("with a query param sharing the name of a path param", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/:id"),
+						)
+						Params(func() {
+							Param("id", Integer)
+							Param("idFilter", String, func() {
+								Metadata("param:query", "id")
+							})
+						})
+					})
+				})
+			})
+
+			It("uses the query string name", func() {
+				Ω(swagger.Paths["/{id}"]).ShouldNot(BeNil())
+				get := swagger.Paths["/{id}"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(2))
+				ins := map[string]string{}
+				for _, p := range get.Parameters {
+					ins[p.In] = p.Name
+				}
+				Ω(ins).Should(Equal(map[string]string{"path": "id", "query": "id"}))
+			})
+		})
+
+		Context("with params read from several sources", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/:id"),
+						)
+						Params(func() {
+							Param("id", String, func() {
+								Metadata("param:sources", "path", "query:item")
+							})
+							Param("tenant", String, func() {
+								Metadata("param:sources", "header:X-Tenant-Id")
+							})
+							Required("tenant")
+						})
+					})
+				})
+			})
+
+			It("describes one parameter per source", func() {
+				get := swagger.Paths["/{id}"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(3))
+				Ω(get.Parameters[0].In).Should(Equal("path"))
+				Ω(get.Parameters[0].Name).Should(Equal("id"))
+				Ω(get.Parameters[0].Required).Should(BeTrue())
+				Ω(get.Parameters[1].In).Should(Equal("query"))
+				Ω(get.Parameters[1].Name).Should(Equal("item"))
+				Ω(get.Parameters[1].Required).Should(BeFalse())
+				Ω(get.Parameters[2].In).Should(Equal("header"))
+				Ω(get.Parameters[2].Name).Should(Equal("X-Tenant-Id"))
+				Ω(get.Parameters[2].Required).Should(BeTrue())
+			})
+		})
+
+		Context("with big number params", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/amounts"),
+						)
+						Params(func() {
+							Param("amount", String, func() {
+								Metadata("param:bignum")
+							})
+							Param("ratios", ArrayOf(String), func() {
+								Metadata("param:bignum", "rat")
+							})
+						})
+					})
+				})
+			})
+
+			It("describes the params as formatted strings", func() {
+				Ω(swagger.Paths["/amounts"]).ShouldNot(BeNil())
+				get := swagger.Paths["/amounts"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(2))
+				params := map[string]*genswagger.Parameter{}
+				for _, p := range get.Parameters {
+					params[p.Name] = p
+				}
+				Ω(params["amount"].Type).Should(Equal("string"))
+				Ω(params["amount"].Format).Should(Equal("big-integer"))
+				Ω(params["ratios"].Type).Should(Equal("array"))
+				Ω(params["ratios"].Items.Type).Should(Equal("string"))
+				Ω(params["ratios"].Items.Format).Should(Equal("big-rational"))
+			})
+		})
+
+		Context("with date params", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/days"),
+						)
+						Params(func() {
+							Param("day", String, func() {
+								Format("date")
+							})
+							Param("days", ArrayOf(String, func() {
+								Format("date")
+							}))
+						})
+					})
+				})
+			})
+
+			It("describes the params as date strings", func() {
+				Ω(swagger.Paths["/days"]).ShouldNot(BeNil())
+				get := swagger.Paths["/days"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				params := map[string]*genswagger.Parameter{}
+				for _, p := range get.Parameters {
+					params[p.Name] = p
+				}
+				Ω(params["day"].Type).Should(Equal("string"))
+				Ω(params["day"].Format).Should(Equal("date"))
+				Ω(params["days"].Type).Should(Equal("array"))
+				Ω(params["days"].Items.Type).Should(Equal("string"))
+				Ω(params["days"].Items.Format).Should(Equal("date"))
+			})
+		})
+
+		Context("with resource and action docs", func() {
+			BeforeEach(func() {
+				Resource("bottle", func() {
+					Description("A wine bottle")
+					Docs(func() {
+						Description("Bottle guide")
+						URL("http://example.com/bottles")
+					})
+					Action("show", func() {
+						Routing(GET("/bottles/:id"))
+						Docs(func() {
+							Description("Show guide")
+							URL("http://example.com/bottles/show")
+						})
+					})
+				})
+			})
+
+			It("sets the resource tag external docs", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				Ω(swagger.Tags).Should(HaveLen(2))
+				Ω(swagger.Tags[1]).Should(Equal(&genswagger.Tag{
+					Name:        "bottle",
+					Description: "A wine bottle",
+					ExternalDocs: &genswagger.ExternalDocs{
+						Description: "Bottle guide",
+						URL:         "http://example.com/bottles",
+					},
+				}))
+			})
+
+			It("sets the operation external docs", func() {
+				get := swagger.Paths["/bottles/{id}"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Tags).Should(Equal([]string{"bottle"}))
+				Ω(get.ExternalDocs).Should(Equal(&genswagger.ExternalDocs{
+					Description: "Show guide",
+					URL:         "http://example.com/bottles/show",
+				}))
+			})
+		})
+
+		Context("with a param mapping wire values", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/items"),
+						)
+						Params(func() {
+							Param("status", Integer, func() {
+								Enum(0, 1)
+								Default(1)
+								Metadata("param:enum-map", "inactive=0", "active=1")
+							})
+						})
+					})
+				})
+			})
+
+			It("describes the wire values and the internal values", func() {
+				get := swagger.Paths["/items"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(1))
+				Ω(get.Parameters[0].Type).Should(Equal("string"))
+				Ω(get.Parameters[0].Enum).Should(Equal([]interface{}{"inactive", "active", "0", "1"}))
+				Ω(get.Parameters[0].Default).Should(Equal("active"))
+			})
+		})
+
+		Context("with a boolean param using custom strings", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/items"),
+						)
+						Params(func() {
+							Param("notify", Boolean, func() {
+								Default(false)
+								Metadata("param:bool-values", "on", "off")
+							})
+						})
+					})
+				})
+			})
+
+			It("describes the strings", func() {
+				get := swagger.Paths["/items"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(1))
+				Ω(get.Parameters[0].Type).Should(Equal("string"))
+				Ω(get.Parameters[0].Enum).Should(Equal([]interface{}{"on", "off"}))
+				Ω(get.Parameters[0].Default).Should(Equal("off"))
+			})
+		})
+
+		Context("with a flag param", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/items"),
+						)
+						Params(func() {
+							Param("verbose", Boolean, func() {
+								Metadata("param:flag")
+							})
+							Param("strict", Boolean)
+						})
+					})
+				})
+			})
+
+			It("allows empty values", func() {
+				get := swagger.Paths["/items"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(2))
+				for _, p := range get.Parameters {
+					Ω(p.AllowEmptyValue).Should(Equal(p.Name == "verbose"))
+				}
+			})
+		})
+
+		Context("with params that define an example", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/items/:id"),
+						)
+						Params(func() {
+							Param("id", Integer, func() {
+								Example(42)
+							})
+							Param("sort", String, func() {
+								Example("name")
+							})
+							Param("limit", Integer)
+						})
+					})
+				})
+			})
+
+			It("sets the x-example extension", func() {
+				get := swagger.Paths["/items/{id}"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(3))
+				params := make(map[string]*genswagger.Parameter)
+				for _, p := range get.Parameters {
+					params[p.Name] = p
+				}
+				Ω(params["id"].Extensions).Should(Equal(map[string]interface{}{"x-example": 42}))
+				Ω(params["sort"].Extensions).Should(Equal(map[string]interface{}{"x-example": "name"}))
+				Ω(params["limit"].Extensions).Should(BeNil())
+				b, err := json.Marshal(params["sort"])
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(b)).Should(ContainSubstring(`"x-example":"name"`))
+			})
+		})
+
+		Context("with array query params", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/items"),
+						)
+						Params(func() {
+							Param("ids", ArrayOf(Integer, func() {
+								Enum(1, 2, 3)
+							}))
+							Param("since", ArrayOf(DateTime))
+						})
+					})
+				})
+			})
+
+			It("describes the elements with an items schema", func() {
+				get := swagger.Paths["/items"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(2))
+				params := make(map[string]*genswagger.Parameter)
+				for _, p := range get.Parameters {
+					params[p.Name] = p
+				}
+				Ω(params["ids"].Type).Should(Equal("array"))
+				Ω(params["ids"].CollectionFormat).Should(Equal("multi"))
+				Ω(params["ids"].Items).Should(Equal(&genswagger.Items{Type: "integer", Enum: []interface{}{1, 2, 3}}))
+				Ω(params["since"].Items).Should(Equal(&genswagger.Items{Type: "string", Format: "date-time"}))
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with an action that only defines error responses", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							DELETE("/items/:id"),
+						)
+						Response(NotFound)
+						Response(BadRequest, ErrorMedia)
+					})
+				})
+			})
+
+			It("describes the error responses and adds a default response", func() {
+				del := swagger.Paths["/items/{id}"].(*genswagger.Path).Delete
+				Ω(del).ShouldNot(BeNil())
+				Ω(del.Responses).Should(HaveLen(3))
+				Ω(del.Responses).Should(HaveKey("400"))
+				Ω(del.Responses).Should(HaveKey("404"))
+				Ω(del.Responses).Should(HaveKeyWithValue("default", &genswagger.Response{
+					Description: genswagger.DefaultResponseDescription,
+				}))
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with an action that defines no response", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/items"),
+						)
+					})
+				})
+			})
+
+			It("adds a default response", func() {
+				get := swagger.Paths["/items"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Responses).Should(HaveLen(1))
+				Ω(get.Responses).Should(HaveKey("default"))
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with an action requiring an idempotency key", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							POST("/orders"),
+						)
+						Metadata("idempotency:key", "required")
+					})
+				})
+			})
+
+			It("describes the Idempotency-Key header", func() {
+				post := swagger.Paths["/orders"].(*genswagger.Path).Post
+				Ω(post).ShouldNot(BeNil())
+				Ω(post.Parameters).Should(HaveLen(1))
+				p := post.Parameters[0]
+				Ω(p.In).Should(Equal("header"))
+				Ω(p.Name).Should(Equal("Idempotency-Key"))
+				Ω(p.Type).Should(Equal("string"))
+				Ω(p.Required).Should(BeTrue())
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with params read from the basic auth credentials", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/session"),
+						)
+						Params(func() {
+							Param("user", String, func() {
+								Metadata("param:basic-auth", "username")
+							})
+							Param("pass", String, func() {
+								Metadata("param:basic-auth", "password")
+							})
+							Param("verbose", Boolean)
+						})
+					})
+				})
+			})
+
+			It("describes the credentials as a basic security requirement", func() {
+				get := swagger.Paths["/session"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(1))
+				Ω(get.Parameters[0].Name).Should(Equal("verbose"))
+				Ω(get.Security).Should(Equal([]map[string][]string{{"basic": {}}}))
+				Ω(swagger.SecurityDefinitions).Should(HaveKeyWithValue("basic", &genswagger.SecurityDefinition{Type: "basic"}))
+				validateSwagger(swagger)
+			})
+		})
+
+		Context("with header params", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							GET("/events"),
+						)
+						Headers(func() {
+							Header("If-Modified-Since", String, "Only list events created after", func() {
+								Format("date-time")
+							})
+							Header("X-Page-Size", Integer)
+							Required("X-Page-Size")
+						})
+					})
+				})
+			})
+
+			It("describes the headers as header parameters", func() {
+				Ω(swagger.Paths["/events"]).ShouldNot(BeNil())
+				get := swagger.Paths["/events"].(*genswagger.Path).Get
+				Ω(get).ShouldNot(BeNil())
+				Ω(get.Parameters).Should(HaveLen(2))
+				Ω(get.Parameters[0]).Should(Equal(&genswagger.Parameter{In: "header", Name: "If-Modified-Since", Type: "string",
+					Format: "date-time", Description: "Only list events created after"}))
+				Ω(get.Parameters[1]).Should(Equal(&genswagger.Parameter{In: "header", Name: "X-Page-Size", Type: "integer", Required: true}))
+			})
+		})
+
+		Context("with attributes that define default values", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Params(func() {
+							Param("count", Integer, func() {
+								Default(10)
+							})
+							Param("ratio", Number, func() {
+								Default(0.5)
+							})
+							Param("tags", ArrayOf(String), func() {
+								Default([]interface{}{"red", "white"})
+							})
+						})
+						Headers(func() {
+							Header("X-Verbose", Boolean, func() {
+								Default(false)
+							})
+						})
+						Payload(func() {
+							Member("name", String, func() {
+								Default("default")
+							})
+							Member("since", DateTime, func() {
+								Default("2017-01-01T00:00:00Z")
+							})
+						})
+					})
+				})
+			})
+
+			It("sets the parameters and schema default fields", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				put := swagger.Paths[""].(*genswagger.Path).Put
+				Ω(put).ShouldNot(BeNil())
+				defaults := make(map[string]interface{})
+				for _, p := range put.Parameters {
+					if p.In != "body" {
+						defaults[p.Name] = p.Default
+					}
+				}
+				Ω(defaults).Should(Equal(map[string]interface{}{
+					"count":     10,
+					"ratio":     0.5,
+					"tags":      []interface{}{"red", "white"},
+					"X-Verbose": false,
+				}))
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"name":"count","in":"query","required":false,"type":"integer","default":10`),
+					[]byte(`"name":{"type":"string","default":"default"`),
+					[]byte(`"since":{"type":"string","default":"2017-01-01T00:00:00Z"`),
+				})
+			})
+		})
+
+		Context("with a base64 encoded payload attribute", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Payload(func() {
+							Member("data", String, func() {
+								Format("byte")
+							})
+						})
+					})
+				})
+			})
+
+			It("sets the byte format", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`"data":{"type":"string","example":"bnR0OXI2","format":"byte"}`),
+				})
+			})
+		})
+
 		Context("with a payload of type Any", func() {
 			BeforeEach(func() {
 				Resource("res", func() {
@@ -279,6 +1030,35 @@ var _ = Describe("New", func() {
 				})
 			})
 
+			It("marks the body parameter as optional", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`{"name":"payload","in":"body","required":false,`),
+				})
+			})
+
+		})
+
+		Context("with required payload", func() {
+			BeforeEach(func() {
+				p := Type("RequiredPayload", func() {
+					Member("m1", String)
+				})
+				Resource("res", func() {
+					Action("act", func() {
+						Routing(
+							PUT("/"),
+						)
+						Payload(p)
+					})
+				})
+			})
+
+			It("marks the body parameter as required", func() {
+				validateSwaggerWithFragments(swagger, [][]byte{
+					[]byte(`{"name":"payload","in":"body","required":true,`),
+				})
+			})
+
 		})
 
 		Context("with zero value validations", func() {
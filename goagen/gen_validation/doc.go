@@ -0,0 +1,20 @@
+/*
+Package genvalidation provides a generator for the validation methods of user types and media
+types. The generator is meant for APIs that keep their domain types in a shared package rather
+than in the package generated by the app generator. It writes a single file validation.go under
+the output directory that contains only the Validate methods of the types, without any transport
+code:
+
+	// Validate validates the Bottle type instance.
+	func (ut *Bottle) Validate() (err error) {
+		if ut.Name == "" {
+			err = goa.MergeErrors(err, goa.MissingAttributeError(`response`, "name"))
+		}
+		return
+	}
+
+The types must be defined in the target package with the Go names and field names the app
+generator would use. The --types flag restricts the generation to the given comma separated list
+of user type names and media type identifiers, all the types of the API are used by default.
+*/
+package genvalidation
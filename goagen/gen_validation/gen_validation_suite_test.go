@@ -0,0 +1,13 @@
+package genvalidation_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenValidation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenValidation Suite")
+}
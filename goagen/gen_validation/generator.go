@@ -0,0 +1,196 @@
+package genvalidation
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+)
+
+// NewGenerator returns an initialized instance of a validation generator
+func NewGenerator(options ...Option) *Generator {
+	g := &Generator{validator: codegen.NewValidator()}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	return g
+}
+
+// Generator is the validation methods generator.
+type Generator struct {
+	API       *design.APIDefinition // The API definition
+	OutDir    string                // Path to output directory
+	Target    string                // Name of generated package
+	Types     []string              // Names of user types and identifiers of media types, all if empty
+	genfiles  []string              // Generated files
+	validator *codegen.Validator    // Validation code generator
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	var outDir, target, types, ver string
+
+	set := flag.NewFlagSet("validation", flag.PanicOnError)
+	set.String("design", "", "")
+	set.StringVar(&outDir, "out", "", "")
+	set.StringVar(&target, "pkg", "types", "")
+	set.StringVar(&types, "types", "", "")
+	set.StringVar(&ver, "version", "", "")
+	set.Parse(os.Args[1:])
+
+	if err := codegen.CheckVersion(ver); err != nil {
+		return nil, err
+	}
+
+	g := &Generator{
+		OutDir:    outDir,
+		Target:    codegen.Goify(target, false),
+		API:       design.Design,
+		validator: codegen.NewValidator(),
+	}
+	if types != "" {
+		g.Types = strings.Split(types, ",")
+	}
+
+	return g.Generate()
+}
+
+// Generate produces the file containing the validation methods.
+func (g *Generator) Generate() (_ []string, err error) {
+	if g.API == nil {
+		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
+	}
+	if g.validator == nil {
+		g.validator = codegen.NewValidator()
+	}
+
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	if err = os.MkdirAll(g.OutDir, 0755); err != nil {
+		return
+	}
+	file := filepath.Join(g.OutDir, "validation.go")
+	os.Remove(file)
+	w, err := codegen.SourceFileFor(file)
+	if err != nil {
+		return
+	}
+	g.genfiles = append(g.genfiles, file)
+	title := fmt.Sprintf("%s: Validation Methods", g.API.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("math"),
+		codegen.SimpleImport("time"),
+		codegen.SimpleImport("unicode/utf8"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	for _, t := range g.API.Types {
+		imports = codegen.AttributeImports(t.AttributeDefinition, imports, nil)
+	}
+	for _, mt := range g.API.MediaTypes {
+		imports = codegen.AttributeImports(mt.AttributeDefinition, imports, nil)
+	}
+	if err = w.WriteHeader(title, g.Target, imports); err != nil {
+		return
+	}
+	fn := template.FuncMap{"validationCode": g.validator.Code}
+	err = g.API.IterateUserTypes(func(t *design.UserTypeDefinition) error {
+		if !g.selected(t.TypeName) {
+			return nil
+		}
+		return w.ExecuteTemplate("validate", validateT, fn, map[string]interface{}{
+			"Type":     t,
+			"Receiver": "ut",
+		})
+	})
+	if err != nil {
+		return
+	}
+	err = g.API.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt.IsError() || !g.selected(mt.Identifier) {
+			return nil
+		}
+		if !mt.Type.IsObject() && !mt.Type.IsArray() {
+			return nil
+		}
+		var links *design.UserTypeDefinition
+		err := mt.IterateViews(func(view *design.ViewDefinition) error {
+			p, l, err := mt.Project(view.Name)
+			if err != nil {
+				return err
+			}
+			if links == nil {
+				links = l
+			}
+			return w.ExecuteTemplate("validate", validateT, fn, map[string]interface{}{
+				"Type":     p.UserTypeDefinition,
+				"Receiver": "mt",
+			})
+		})
+		if err != nil || links == nil {
+			return err
+		}
+		return w.ExecuteTemplate("validate", validateT, fn, map[string]interface{}{
+			"Type":     links,
+			"Receiver": "ut",
+		})
+	})
+	if err != nil {
+		return
+	}
+	if err = w.FormatCode(); err != nil {
+		return
+	}
+
+	return g.genfiles, nil
+}
+
+// Cleanup removes all the files generated by this generator during the last invokation of Generate.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// selected returns true if the validation method of the type with the given name or identifier
+// must be generated.
+func (g *Generator) selected(name string) bool {
+	if len(g.Types) == 0 {
+		return true
+	}
+	for _, t := range g.Types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	// validateT generates the Validate method of a type if the type has validations.
+	// template input: map[string]interface{}
+	validateT = `{{ $validation := validationCode .Type.AttributeDefinition false false false .Receiver "response" 1 false }}{{/*
+*/}}{{ if $validation }}{{ $typeName := gotypename .Type .Type.AllRequired 0 false }}// Validate validates the {{ $typeName }} type instance.
+func ({{ .Receiver }} {{ gotyperef .Type .Type.AllRequired 0 false }}) Validate() (err error) {
+{{ $validation }}
+	return
+}
+
+{{ end }}`
+)
@@ -0,0 +1,139 @@
+package genvalidation_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_validation"
+	"github.com/goadesign/goa/version"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("Generate", func() {
+	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_validation/validationtest"
+
+	var outDir string
+	var files []string
+	var genErr error
+
+	BeforeEach(func() {
+		gopath := filepath.SplitList(os.Getenv("GOPATH"))[0]
+		outDir = filepath.Join(gopath, "src", testgenPackagePath)
+		err := os.MkdirAll(outDir, 0777)
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"goagen", "--out=" + outDir, "--design=foo", "--pkg=validationtest", "--version=" + version.String()}
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = genvalidation.Generate()
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(outDir)
+	})
+
+	Context("with user types and media types", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+			API("test api", func() {})
+			Type("Bottle", func() {
+				Attribute("name", String, func() {
+					MinLength(2)
+				})
+				Attribute("vintage", Integer, func() {
+					Minimum(1900)
+				})
+				Attribute("tags", ArrayOf(String, func() {
+					Pattern("^[a-z]+$")
+				}))
+				Required("name")
+			})
+			Type("Note", func() {
+				Attribute("text", String)
+			})
+			MediaType("application/vnd.cellar+json", func() {
+				TypeName("Cellar")
+				Attributes(func() {
+					Attribute("name", String, func() {
+						MaxLength(10)
+					})
+				})
+				View("default", func() {
+					Attribute("name")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			err := ioutil.WriteFile(filepath.Join(outDir, "types.go"), []byte(typesCode), 0644)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("generates only the validation methods", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(1))
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "validation.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(bottleValidateCode))
+			Ω(string(content)).Should(ContainSubstring("func (mt *Cellar) Validate() (err error) {"))
+			Ω(string(content)).ShouldNot(ContainSubstring("Note"))
+			Ω(string(content)).ShouldNot(ContainSubstring("net/http"))
+			_, err = gexec.Build(testgenPackagePath)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		Context("restricted to some types", func() {
+			BeforeEach(func() {
+				os.Args = append(os.Args, "--types=Bottle")
+			})
+
+			It("generates the validation methods of the given types only", func() {
+				Ω(genErr).Should(BeNil())
+				content, err := ioutil.ReadFile(filepath.Join(outDir, "validation.go"))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(string(content)).Should(ContainSubstring(bottleValidateCode))
+				Ω(string(content)).ShouldNot(ContainSubstring("Cellar"))
+			})
+		})
+	})
+})
+
+const typesCode = `package validationtest
+
+type Bottle struct {
+	Name    string   ` + "`" + `json:"name"` + "`" + `
+	Tags    []string ` + "`" + `json:"tags,omitempty"` + "`" + `
+	Vintage *int     ` + "`" + `json:"vintage,omitempty"` + "`" + `
+}
+
+type Cellar struct {
+	Name *string ` + "`" + `json:"name,omitempty"` + "`" + `
+}
+`
+
+const bottleValidateCode = `// Validate validates the Bottle type instance.
+func (ut *Bottle) Validate() (err error) {
+	if ut.Name == "" {
+		err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `response` + "`" + `, "name"))
+	}
+	if utf8.RuneCountInString(ut.Name) < 2 {
+		err = goa.MergeErrors(err, goa.InvalidLengthError(` + "`" + `response.name` + "`" + `, ut.Name, utf8.RuneCountInString(ut.Name), 2, true))
+	}
+	for i, e := range ut.Tags {
+		if ok := goa.ValidatePattern(` + "`" + `^[a-z]+$` + "`" + `, e); !ok {
+			err = goa.MergeErrors(err, goa.InvalidPatternError(` + "`" + `response.tags[` + "`" + `+fmt.Sprint(i)+` + "`" + `]` + "`" + `, e, ` + "`" + `^[a-z]+$` + "`" + `))
+		}
+	}
+	if ut.Vintage != nil {
+		if *ut.Vintage < 1900 {
+			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `response.vintage` + "`" + `, *ut.Vintage, 1900, true))
+		}
+	}
+	return
+}
+`
@@ -0,0 +1,35 @@
+package genvalidation
+
+import "github.com/goadesign/goa/design"
+
+// Option a generator option definition
+type Option func(*Generator)
+
+// API The API definition
+func API(API *design.APIDefinition) Option {
+	return func(g *Generator) {
+		g.API = API
+	}
+}
+
+// OutDir Path to output directory
+func OutDir(outDir string) Option {
+	return func(g *Generator) {
+		g.OutDir = outDir
+	}
+}
+
+// Target Name of generated package
+func Target(target string) Option {
+	return func(g *Generator) {
+		g.Target = target
+	}
+}
+
+// Types Names of the user types and identifiers of the media types to generate the validation
+// methods for
+func Types(types []string) Option {
+	return func(g *Generator) {
+		g.Types = types
+	}
+}
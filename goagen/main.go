@@ -61,8 +61,8 @@ package and tool and the Swagger specification for the API.
 
 	// appCmd implements the "app" command.
 	var (
-		pkg    string
-		notest bool
+		pkg                       string
+		notest, fuzz, jsonPointer bool
 	)
 	appCmd := &cobra.Command{
 		Use:   "app",
@@ -71,6 +71,8 @@ package and tool and the Swagger specification for the API.
 	}
 	appCmd.Flags().StringVar(&pkg, "pkg", "app", "Name of generated Go package containing controllers supporting code (contexts, media types, user types etc.)")
 	appCmd.Flags().BoolVar(&notest, "notest", false, "Prevent generation of test helpers")
+	appCmd.Flags().BoolVar(&fuzz, "fuzz", false, "Generate Go 1.18 fuzz tests for the request decoders")
+	appCmd.Flags().BoolVar(&jsonPointer, "json-pointer", false, "Report the invalid fields of request and response bodies with JSON pointers in validation errors")
 	rootCmd.AddCommand(appCmd)
 
 	// mainCmd implements the "main" command.
@@ -135,6 +137,25 @@ package and tool and the Swagger specification for the API.
 	}
 	rootCmd.AddCommand(schemaCmd)
 
+	// statusCmd implements the "status" command.
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Generate endpoint HTTP status table",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genstatus", c) },
+	}
+	rootCmd.AddCommand(statusCmd)
+
+	// validationCmd implements the "validation" command.
+	var types string
+	validationCmd := &cobra.Command{
+		Use:   "validation",
+		Short: "Generate validation methods of shared types",
+		Run:   func(c *cobra.Command, _ []string) { files, err = run("genvalidation", c) },
+	}
+	validationCmd.Flags().StringVar(&pkg, "pkg", "types", "Name of the Go package that defines the types")
+	validationCmd.Flags().StringVar(&types, "types", "", "Comma separated list of user type names and media type identifiers, all types if empty")
+	rootCmd.AddCommand(validationCmd)
+
 	// genCmd implements the "gen" command.
 	var (
 		pkgPath string
@@ -0,0 +1,304 @@
+package goa
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONPatchOperation is a single RFC 6902 JSON Patch operation.
+type JSONPatchOperation struct {
+	// Op is the operation: one of "add", "remove", "replace", "move", "copy" or "test".
+	Op string `json:"op"`
+	// Path is the JSON pointer (RFC 6901) to the target location.
+	Path string `json:"path"`
+	// From is the JSON pointer to the source location of "move" and "copy" operations.
+	From *string `json:"from,omitempty"`
+	// Value is the value used by "add", "replace" and "test" operations.
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies the JSON Patch operations to the JSON document doc and returns the
+// resulting document. patch may be any value that serializes to a JSON Patch document, for
+// example the payload generated for actions whose payload is an array of
+// design.JSONPatchOperation. The operations are applied in order and the first failing
+// operation aborts the patch, doc is never modified.
+func ApplyJSONPatch(doc []byte, patch interface{}) ([]byte, error) {
+	ops, ok := patch.([]*JSONPatchOperation)
+	if !ok {
+		raw, err := json.Marshal(patch)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &ops); err != nil {
+			return nil, err
+		}
+	}
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+	for i, op := range ops {
+		if op == nil {
+			return nil, fmt.Errorf("JSON patch operation %d is null", i)
+		}
+		var err error
+		if v, err = op.apply(v); err != nil {
+			return nil, fmt.Errorf("JSON patch operation %d (%s %s) failed: %s", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// apply runs the operation against the decoded JSON document and returns the updated document.
+func (op *JSONPatchOperation) apply(doc interface{}) (interface{}, error) {
+	switch op.Op {
+	case "add":
+		val, err := jsonClone(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerSet(doc, op.Path, val, true)
+	case "remove":
+		doc, _, err := jsonPointerRemove(doc, op.Path)
+		return doc, err
+	case "replace":
+		if _, err := jsonPointerGet(doc, op.Path); err != nil {
+			return nil, err
+		}
+		val, err := jsonClone(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerSet(doc, op.Path, val, false)
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf(`missing "from"`)
+		}
+		var val interface{}
+		var err error
+		if op.Op == "move" {
+			if strings.HasPrefix(op.Path, *op.From+"/") {
+				return nil, fmt.Errorf("cannot move a value into one of its children")
+			}
+			doc, val, err = jsonPointerRemove(doc, *op.From)
+		} else {
+			val, err = jsonPointerGet(doc, *op.From)
+			if err == nil {
+				val, err = jsonClone(val)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return jsonPointerSet(doc, op.Path, val, true)
+	case "test":
+		val, err := jsonPointerGet(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := jsonClone(op.Value)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(val, expected) {
+			return nil, fmt.Errorf("test failed")
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// jsonPointerTokens splits the RFC 6901 JSON pointer into unescaped reference tokens.
+func jsonPointerTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(t, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// jsonPointerGet returns the value located at pointer in doc.
+func jsonPointerGet(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := jsonPointerTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, t := range tokens {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[t]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", pointer)
+			}
+			cur = v
+		case []interface{}:
+			idx, err := jsonArrayIndex(t, len(c), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = c[idx]
+		default:
+			return nil, fmt.Errorf("path %q not found", pointer)
+		}
+	}
+	return cur, nil
+}
+
+// jsonPointerSet sets the value at pointer in doc. insert controls whether array elements are
+// inserted ("add") or replaced ("replace").
+func jsonPointerSet(doc interface{}, pointer string, val interface{}, insert bool) (interface{}, error) {
+	tokens, err := jsonPointerTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return val, nil
+	}
+	return jsonSet(doc, tokens, val, insert, pointer)
+}
+
+func jsonSet(cur interface{}, tokens []string, val interface{}, insert bool, pointer string) (interface{}, error) {
+	t := tokens[0]
+	switch c := cur.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			c[t] = val
+			return c, nil
+		}
+		child, ok := c[t]
+		if !ok {
+			return nil, fmt.Errorf("path %q not found", pointer)
+		}
+		v, err := jsonSet(child, tokens[1:], val, insert, pointer)
+		if err != nil {
+			return nil, err
+		}
+		c[t] = v
+		return c, nil
+	case []interface{}:
+		if len(tokens) == 1 && insert {
+			idx := len(c)
+			if t != "-" {
+				var err error
+				if idx, err = jsonArrayIndex(t, len(c), true); err != nil {
+					return nil, err
+				}
+			}
+			c = append(c, nil)
+			copy(c[idx+1:], c[idx:])
+			c[idx] = val
+			return c, nil
+		}
+		idx, err := jsonArrayIndex(t, len(c), false)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 1 {
+			c[idx] = val
+			return c, nil
+		}
+		v, err := jsonSet(c[idx], tokens[1:], val, insert, pointer)
+		if err != nil {
+			return nil, err
+		}
+		c[idx] = v
+		return c, nil
+	default:
+		return nil, fmt.Errorf("path %q not found", pointer)
+	}
+}
+
+// jsonPointerRemove removes the value at pointer from doc and returns the updated document
+// together with the removed value.
+func jsonPointerRemove(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	tokens, err := jsonPointerTokens(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	parent := doc
+	if len(tokens) > 1 {
+		parentPath := "/" + strings.Join(escapeJSONPointerTokens(tokens[:len(tokens)-1]), "/")
+		if parent, err = jsonPointerGet(doc, parentPath); err != nil {
+			return nil, nil, err
+		}
+	}
+	t := tokens[len(tokens)-1]
+	var removed, updated interface{}
+	switch c := parent.(type) {
+	case map[string]interface{}:
+		v, ok := c[t]
+		if !ok {
+			return nil, nil, fmt.Errorf("path %q not found", pointer)
+		}
+		delete(c, t)
+		removed, updated = v, c
+	case []interface{}:
+		idx, err := jsonArrayIndex(t, len(c), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		removed = c[idx]
+		updated = append(c[:idx:idx], c[idx+1:]...)
+	default:
+		return nil, nil, fmt.Errorf("path %q not found", pointer)
+	}
+	if len(tokens) == 1 {
+		return updated, removed, nil
+	}
+	doc, err = jsonSet(doc, tokens[:len(tokens)-1], updated, false, pointer)
+	return doc, removed, err
+}
+
+// escapeJSONPointerTokens is the inverse of the unescaping done by jsonPointerTokens.
+func escapeJSONPointerTokens(tokens []string) []string {
+	res := make([]string, len(tokens))
+	for i, t := range tokens {
+		res[i] = EscapeJSONPointer(t)
+	}
+	return res
+}
+
+// EscapeJSONPointer escapes the characters of the given reference token that have a special
+// meaning in RFC 6901 JSON pointers so that it can be appended to a pointer after a "/".
+func EscapeJSONPointer(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// jsonArrayIndex parses the array index token t. end indicates whether the index may be equal to
+// the length of the array (i.e. designate the position after the last element).
+func jsonArrayIndex(t string, length int, end bool) (int, error) {
+	idx, err := strconv.Atoi(t)
+	if err != nil || idx < 0 || (len(t) > 1 && t[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", t)
+	}
+	if idx > length || (idx == length && !end) {
+		return 0, fmt.Errorf("array index %d out of bounds", idx)
+	}
+	return idx, nil
+}
+
+// jsonClone returns a deep copy of v in its decoded JSON form so that values can be compared
+// and shared safely.
+func jsonClone(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	err = json.Unmarshal(raw, &res)
+	return res, err
+}
@@ -0,0 +1,105 @@
+package goa_test
+
+import (
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ApplyJSONPatch", func() {
+	var doc string
+	var patch interface{}
+	var patched []byte
+	var applyErr error
+
+	BeforeEach(func() {
+		doc = `{"name":"bottle","tags":["red","dry"],"vintage":{"year":2010}}`
+	})
+
+	JustBeforeEach(func() {
+		patched, applyErr = goa.ApplyJSONPatch([]byte(doc), patch)
+	})
+
+	Context("with add, remove and replace operations", func() {
+		BeforeEach(func() {
+			patch = []*goa.JSONPatchOperation{
+				{Op: "add", Path: "/tags/1", Value: "aged"},
+				{Op: "add", Path: "/tags/-", Value: "oak"},
+				{Op: "remove", Path: "/vintage/year"},
+				{Op: "replace", Path: "/name", Value: "Napa"},
+			}
+		})
+
+		It("applies the operations in order", func() {
+			Ω(applyErr).ShouldNot(HaveOccurred())
+			Ω(patched).Should(MatchJSON(`{"name":"Napa","tags":["red","aged","dry","oak"],"vintage":{}}`))
+		})
+	})
+
+	Context("with move and copy operations", func() {
+		BeforeEach(func() {
+			from := "/name"
+			tag := "/tags/0"
+			patch = []*goa.JSONPatchOperation{
+				{Op: "copy", From: &tag, Path: "/color"},
+				{Op: "move", From: &from, Path: "/vintage/label"},
+			}
+		})
+
+		It("moves and copies the values", func() {
+			Ω(applyErr).ShouldNot(HaveOccurred())
+			Ω(patched).Should(MatchJSON(`{"color":"red","tags":["red","dry"],"vintage":{"year":2010,"label":"bottle"}}`))
+		})
+	})
+
+	Context("with a generated payload", func() {
+		type op struct {
+			Op    string      `form:"op" json:"op"`
+			Path  string      `form:"path" json:"path"`
+			From  *string     `form:"from,omitempty" json:"from,omitempty"`
+			Value interface{} `form:"value,omitempty" json:"value,omitempty"`
+		}
+
+		BeforeEach(func() {
+			patch = []*op{{Op: "test", Path: "/vintage", Value: map[string]int{"year": 2010}}, {Op: "remove", Path: "/tags"}}
+		})
+
+		It("applies the operations", func() {
+			Ω(applyErr).ShouldNot(HaveOccurred())
+			Ω(patched).Should(MatchJSON(`{"name":"bottle","vintage":{"year":2010}}`))
+		})
+	})
+
+	Context("with a failing operation", func() {
+		BeforeEach(func() {
+			patch = []*goa.JSONPatchOperation{
+				{Op: "replace", Path: "/name", Value: "Napa"},
+				{Op: "test", Path: "/vintage/year", Value: 2011},
+			}
+		})
+
+		It("returns an error", func() {
+			Ω(applyErr).Should(HaveOccurred())
+			Ω(applyErr.Error()).Should(Equal("JSON patch operation 1 (test /vintage/year) failed: test failed"))
+			Ω(patched).Should(BeNil())
+		})
+	})
+
+	Context("with a path that does not exist", func() {
+		BeforeEach(func() {
+			patch = []*goa.JSONPatchOperation{{Op: "replace", Path: "/color", Value: "red"}}
+		})
+
+		It("returns an error", func() {
+			Ω(applyErr).Should(HaveOccurred())
+			Ω(applyErr.Error()).Should(ContainSubstring(`path "/color" not found`))
+		})
+	})
+})
+
+var _ = Describe("EscapeJSONPointer", func() {
+	It("escapes the tilde and slash characters", func() {
+		Ω(goa.EscapeJSONPointer("a/b~c")).Should(Equal("a~1b~0c"))
+		Ω(goa.EscapeJSONPointer("name")).Should(Equal("name"))
+	})
+})
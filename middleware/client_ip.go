@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"context"
+
+	"github.com/goadesign/goa"
+)
+
+// ClientIP is a middleware that computes the IP address of the client that sent the request and
+// stores it in the request context, use ContextClientIP to retrieve it. trustedProxies lists the
+// IP addresses or CIDR ranges of the proxies that are trusted to set the X-Forwarded-For and
+// X-Real-IP headers, for example "10.0.0.0/8" or "192.168.1.1". ClientIP panics if one of the
+// values is not a valid IP address or CIDR range. See ClientIPFrom for how the IP is computed.
+func ClientIP(trustedProxies ...string) goa.Middleware {
+	nets, err := ParseTrustedProxies(trustedProxies...)
+	if err != nil {
+		panic(err)
+	}
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			ctx = context.WithValue(ctx, clientIPKey, ClientIPFrom(req, nets))
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// ContextClientIP extracts the client IP computed by the ClientIP middleware from the context.
+func ContextClientIP(ctx context.Context) (ip string) {
+	if i := ctx.Value(clientIPKey); i != nil {
+		ip = i.(string)
+	}
+	return
+}
+
+// ParseTrustedProxies parses the given IP addresses and CIDR ranges into networks suitable for
+// use with ClientIPFrom. IP addresses are converted into single address networks.
+func ParseTrustedProxies(proxies ...string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, len(proxies))
+	for i, p := range proxies {
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy IP address %#v", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets[i] = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+			continue
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy CIDR range %#v: %s", p, err)
+		}
+		nets[i] = n
+	}
+	return nets, nil
+}
+
+// ClientIPFrom returns the IP address of the client that sent the request. The headers set by
+// proxies are only taken into account when the direct peer of the connection belongs to one of
+// the trusted proxy networks, in which case ClientIPFrom returns the right-most address of the
+// X-Forwarded-For header that does not belong to a trusted proxy. It returns the X-Real-IP header
+// value if X-Forwarded-For is not set and the address of the direct peer otherwise.
+func ClientIPFrom(req *http.Request, trustedProxies []*net.IPNet) string {
+	peer := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	if !trusted(peer, trustedProxies) {
+		return peer
+	}
+	if fwd := req.Header.Get("X-Forwarded-For"); fwd != "" {
+		ips := strings.Split(fwd, ",")
+		for i := len(ips) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ips[i])
+			if !trusted(ip, trustedProxies) || i == 0 {
+				return ip
+			}
+		}
+	}
+	if real := strings.TrimSpace(req.Header.Get("X-Real-IP")); real != "" {
+		return real
+	}
+	return peer
+}
+
+// trusted returns true if the given IP address belongs to one of the given networks.
+func trusted(addr string, nets []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
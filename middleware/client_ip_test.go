@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"net/http"
+
+	"context"
+
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientIP", func() {
+	var trusted []string
+	var remoteAddr string
+	var headers map[string]string
+	var clientIP string
+
+	BeforeEach(func() {
+		trusted = []string{"10.0.0.0/8", "192.168.1.1"}
+		remoteAddr = "10.1.2.3:4242"
+		headers = nil
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequest("GET", "/", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		req.RemoteAddr = remoteAddr
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			clientIP = middleware.ContextClientIP(ctx)
+			return nil
+		}
+		Ω(middleware.ClientIP(trusted...)(h)(context.Background(), nil, req)).ShouldNot(HaveOccurred())
+	})
+
+	It("uses the peer address when no proxy header is set", func() {
+		Ω(clientIP).Should(Equal("10.1.2.3"))
+	})
+
+	Context("with a X-Forwarded-For header set by trusted proxies", func() {
+		BeforeEach(func() {
+			headers = map[string]string{"X-Forwarded-For": "1.1.1.1, 2.2.2.2, 192.168.1.1"}
+		})
+
+		It("uses the right-most untrusted address", func() {
+			Ω(clientIP).Should(Equal("2.2.2.2"))
+		})
+	})
+
+	Context("with a X-Real-IP header", func() {
+		BeforeEach(func() {
+			headers = map[string]string{"X-Real-IP": "3.3.3.3"}
+		})
+
+		It("uses the header value", func() {
+			Ω(clientIP).Should(Equal("3.3.3.3"))
+		})
+	})
+
+	Context("with an untrusted peer", func() {
+		BeforeEach(func() {
+			remoteAddr = "4.4.4.4:4242"
+			headers = map[string]string{"X-Forwarded-For": "1.1.1.1", "X-Real-IP": "3.3.3.3"}
+		})
+
+		It("ignores the proxy headers", func() {
+			Ω(clientIP).Should(Equal("4.4.4.4"))
+		})
+	})
+
+	Context("with an invalid trusted proxy", func() {
+		It("panics", func() {
+			Ω(func() { middleware.ClientIP("foo") }).Should(Panic())
+		})
+	})
+})
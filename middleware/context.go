@@ -12,4 +12,11 @@ const (
 	traceKey
 	spanKey
 	parentSpanKey
+
+	// langKey is the context key used by the Language middleware to store the negotiated
+	// language.
+	langKey
+
+	// clientIPKey is the context key used by the ClientIP middleware to store the client IP.
+	clientIPKey
 )
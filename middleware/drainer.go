@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/goadesign/goa"
+
+	"context"
+)
+
+// Drainer keeps track of the requests being handled so that the server can wait for them to
+// complete when it shuts down. Requests received once draining has started are rejected with a
+// 503 Service Unavailable response. Create a Drainer with the service, mount its middleware first
+// and call Wait on shutdown:
+//
+//	drainer := middleware.NewDrainer(service)
+//	service.Use(drainer.Middleware())
+//	...
+//	drainer.Wait(ctx)
+//	server.Shutdown(ctx)
+//
+type Drainer struct {
+	service  *goa.Service
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+}
+
+// NewDrainer creates a Drainer that sends the responses of the rejected requests using the given
+// service.
+func NewDrainer(service *goa.Service) *Drainer {
+	return &Drainer{service: service}
+}
+
+// Middleware returns a middleware that tracks the requests handled by the service and rejects new
+// requests once Wait has been called.
+func (d *Drainer) Middleware() goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			d.mu.Lock()
+			if d.draining {
+				d.mu.Unlock()
+				rw.Header().Set("Connection", "close")
+				return d.service.Send(ctx, http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+			}
+			d.wg.Add(1)
+			d.mu.Unlock()
+			defer d.wg.Done()
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// Wait starts draining and blocks until the requests being handled complete or ctx is done, in
+// which case it returns the context error.
+func (d *Drainer) Wait(ctx context.Context) error {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Draining returns true once Wait has been called.
+func (d *Drainer) Draining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
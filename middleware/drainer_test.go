@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"net/http"
+	"time"
+
+	"context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drainer", func() {
+	var service *goa.Service
+	var drainer *middleware.Drainer
+	var release chan struct{}
+	var started chan struct{}
+	var h goa.Handler
+
+	BeforeEach(func() {
+		service = newService(nil)
+		drainer = middleware.NewDrainer(service)
+		release = make(chan struct{})
+		started = make(chan struct{})
+		h = drainer.Middleware()(func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			close(started)
+			<-release
+			return service.Send(ctx, http.StatusOK, "ok")
+		})
+	})
+
+	serve := func() (*testResponseWriter, chan error) {
+		req, err := http.NewRequest("GET", "/foo", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		rw := newTestResponseWriter()
+		ctx := newContext(service, rw, req, nil)
+		errc := make(chan error, 1)
+		go func() { errc <- h(ctx, rw, req) }()
+		return rw, errc
+	}
+
+	It("waits for the requests in flight and rejects new requests", func() {
+		rw, errc := serve()
+		Eventually(started).Should(BeClosed())
+
+		waitc := make(chan error, 1)
+		go func() { waitc <- drainer.Wait(context.Background()) }()
+		Eventually(drainer.Draining).Should(BeTrue())
+		Consistently(waitc, 50*time.Millisecond).ShouldNot(Receive())
+
+		rejected, rerrc := serve()
+		Eventually(rerrc).Should(Receive(BeNil()))
+		Ω(rejected.Status).Should(Equal(http.StatusServiceUnavailable))
+		Ω(rejected.Header().Get("Connection")).Should(Equal("close"))
+
+		close(release)
+		Eventually(errc).Should(Receive(BeNil()))
+		Ω(rw.Status).Should(Equal(http.StatusOK))
+		Eventually(waitc).Should(Receive(BeNil()))
+	})
+
+	It("returns the context error when the requests do not complete in time", func() {
+		_, errc := serve()
+		Eventually(started).Should(BeClosed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		Ω(drainer.Wait(ctx)).Should(Equal(context.DeadlineExceeded))
+
+		close(release)
+		Eventually(errc).Should(Receive(BeNil()))
+	})
+
+	It("returns immediately when no request is in flight", func() {
+		Ω(drainer.Wait(context.Background())).ShouldNot(HaveOccurred())
+	})
+})
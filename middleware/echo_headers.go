@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/goadesign/goa"
+
+	"context"
+)
+
+// CorrelationHeaders lists the standard observability headers echoed by EchoCorrelationHeaders.
+var CorrelationHeaders = []string{RequestIDHeader, "X-Correlation-Id", "traceparent", "tracestate"}
+
+// EchoHeaders returns a middleware that copies the values of the given request headers onto the
+// response. Headers missing from the request are not set on the response, and the handler may
+// still override the echoed values. Unlike RequestID the middleware never generates values, it
+// only echoes whatever the client or proxy sent. Mount it on the service to echo the headers of
+// all the responses:
+//
+//	service.Use(middleware.EchoHeaders("X-Correlation-Id", "X-B3-TraceId"))
+//
+func EchoHeaders(headers ...string) goa.Middleware {
+	keys := make([]string, len(headers))
+	for i, h := range headers {
+		keys[i] = http.CanonicalHeaderKey(h)
+	}
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			for _, k := range keys {
+				if vals, ok := req.Header[k]; ok {
+					rw.Header()[k] = append([]string(nil), vals...)
+				}
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// EchoCorrelationHeaders is a middleware that echoes the headers listed in CorrelationHeaders.
+func EchoCorrelationHeaders() goa.Middleware {
+	return EchoHeaders(CorrelationHeaders...)
+}
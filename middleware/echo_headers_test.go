@@ -0,0 +1,64 @@
+package middleware_test
+
+import (
+	"net/http"
+
+	"context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EchoHeaders", func() {
+	var ctx context.Context
+	var req *http.Request
+	var rw *testResponseWriter
+	var service *goa.Service
+
+	BeforeEach(func() {
+		var err error
+		service = newService(nil)
+		req, err = http.NewRequest("GET", "/foo", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		rw = newTestResponseWriter()
+		ctx = newContext(service, rw, req, nil)
+	})
+
+	It("copies the request headers onto the response", func() {
+		req.Header.Set("X-Correlation-Id", "corr")
+		req.Header.Add("Traceparent", "00-a-b-01")
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			return service.Send(ctx, http.StatusOK, "ok")
+		}
+		err := middleware.EchoCorrelationHeaders()(h)(ctx, rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("X-Correlation-Id")).Should(Equal("corr"))
+		Ω(rw.Header().Get("Traceparent")).Should(Equal("00-a-b-01"))
+		Ω(rw.Header()).ShouldNot(HaveKey("X-Request-Id"))
+	})
+
+	It("echoes only the configured headers", func() {
+		req.Header.Set("X-Correlation-Id", "corr")
+		req.Header.Set("X-Other", "other")
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			return service.Send(ctx, http.StatusOK, "ok")
+		}
+		err := middleware.EchoHeaders("x-correlation-id")(h)(ctx, rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("X-Correlation-Id")).Should(Equal("corr"))
+		Ω(rw.Header()).ShouldNot(HaveKey("X-Other"))
+	})
+
+	It("lets the handler override the echoed values", func() {
+		req.Header.Set("X-Request-Id", "client")
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			rw.Header().Set("X-Request-Id", "server")
+			return service.Send(ctx, http.StatusOK, "ok")
+		}
+		err := middleware.EchoHeaders(middleware.RequestIDHeader)(h)(ctx, rw, req)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("X-Request-Id")).Should(Equal("server"))
+	})
+})
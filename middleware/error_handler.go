@@ -16,6 +16,14 @@ import (
 // If verbose is false the details of internal errors is not included in HTTP responses.
 // If you use github.com/pkg/errors then wrapping the error will allow a trace to be printed to the logs
 func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
+	return ErrorMappingHandler(service, verbose, nil)
+}
+
+// ErrorMappingHandler behaves like ErrorHandler but uses mapper to compute the status code and body
+// of the responses corresponding to instances of goa.ServiceError. mapper is typically the
+// MapServiceError function generated in the app package which maps the errors to the error
+// responses declared in the design. A nil mapper uses the status code of the errors.
+func ErrorMappingHandler(service *goa.Service, verbose bool, mapper func(error) (int, interface{})) goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
 			e := h(ctx, rw, req)
@@ -28,10 +36,16 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 			if err, ok := cause.(goa.ServiceError); ok {
 				status = err.ResponseStatus()
 				respBody = err
+				if mapper != nil {
+					status, respBody = mapper(err)
+				}
+			} else {
+				respBody = e.Error()
+			}
+			if err, ok := respBody.(goa.ServiceError); ok {
 				goa.ContextResponse(ctx).ErrorCode = err.Token()
 				rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
 			} else {
-				respBody = e.Error()
 				rw.Header().Set("Content-Type", "text/plain")
 			}
 			if status == http.StatusInternalServerError {
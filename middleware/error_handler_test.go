@@ -45,6 +45,7 @@ var _ = Describe("ErrorHandler", func() {
 	var service *goa.Service
 	var h goa.Handler
 	var verbose bool
+	var mapper func(error) (int, interface{})
 
 	var rw *testResponseWriter
 
@@ -52,12 +53,16 @@ var _ = Describe("ErrorHandler", func() {
 		service = nil
 		h = nil
 		verbose = true
+		mapper = nil
 		rw = nil
 	})
 
 	JustBeforeEach(func() {
 		rw = newTestResponseWriter()
 		eh := middleware.ErrorHandler(service, verbose)(h)
+		if mapper != nil {
+			eh = middleware.ErrorMappingHandler(service, verbose, mapper)(h)
+		}
 		req, err := http.NewRequest("GET", "/foo", nil)
 		Ω(err).ShouldNot(HaveOccurred())
 		ctx := newContext(service, rw, req, nil)
@@ -163,6 +168,52 @@ var _ = Describe("ErrorHandler", func() {
 		})
 	})
 
+	Context("with an error mapper", func() {
+		BeforeEach(func() {
+			service = newService(nil)
+			mapper = func(err error) (int, interface{}) {
+				if err.(goa.ServiceError).ResponseStatus() == http.StatusNotFound {
+					return http.StatusNotFound, err
+				}
+				return http.StatusBadRequest, goa.ErrBadRequest("mapped")
+			}
+		})
+
+		Context("and a handler returning a goa error mapped to itself", func() {
+			BeforeEach(func() {
+				h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					return goa.ErrNotFound("not here")
+				}
+			})
+
+			It("uses the status code returned by the mapper", func() {
+				var decoded errorResponse
+				Ω(rw.Status).Should(Equal(http.StatusNotFound))
+				err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded.Detail).Should(Equal("not here"))
+			})
+		})
+
+		Context("and a handler returning a goa error mapped to another error", func() {
+			BeforeEach(func() {
+				h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					return goa.NewErrorClass("code", 418)("teapot")
+				}
+			})
+
+			It("uses the response returned by the mapper", func() {
+				var decoded errorResponse
+				Ω(rw.Status).Should(Equal(http.StatusBadRequest))
+				Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ErrorMediaIdentifier}))
+				err := service.Decoder.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded.Code).Should(Equal("bad_request"))
+				Ω(decoded.Detail).Should(Equal("mapped"))
+			})
+		})
+	})
+
 	Context("with a handler returning a pkg errors wrapped error", func() {
 		var wrappedError error
 		var logger *testLogger
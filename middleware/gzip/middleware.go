@@ -49,6 +49,8 @@ type handler struct {
 // Middleware encodes the response using Gzip encoding and sets all the appropriate
 // headers. If the Content-Type is not set, it will be set by calling
 // http.DetectContentType on the data being written.
+// Request bodies compressed with gzip need no middleware: the generated payload unmarshalers use
+// goa.Service.DecodeRequest which decompresses bodies whose Content-Encoding header is "gzip".
 func Middleware(level int) goa.Middleware {
 	gzipPool := sync.Pool{
 		New: func() interface{} {
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"context"
+
+	"github.com/goadesign/goa"
+)
+
+// Language is a middleware that negotiates the language of the response using the request
+// Accept-Language header and the given list of supported languages. The negotiated language is
+// stored in the request context, use ContextLanguage to retrieve it. The middleware also sets the
+// response Content-Language header. The first supported language is used when the request does
+// not specify an acceptable language.
+func Language(supported ...string) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			lang := negotiateLanguage(req.Header.Get("Accept-Language"), supported)
+			if lang != "" {
+				ctx = context.WithValue(ctx, langKey, lang)
+				rw.Header().Set("Content-Language", lang)
+			}
+			rw.Header().Add("Vary", "Accept-Language")
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// ContextLanguage extracts the language negotiated by the Language middleware from the context.
+func ContextLanguage(ctx context.Context) (lang string) {
+	if l := ctx.Value(langKey); l != nil {
+		lang = l.(string)
+	}
+	return
+}
+
+// acceptedLanguage is a language range listed in an Accept-Language header.
+type acceptedLanguage struct {
+	tag string
+	q   float64
+}
+
+// byQuality sorts accepted languages by decreasing quality.
+type byQuality []acceptedLanguage
+
+func (a byQuality) Len() int           { return len(a) }
+func (a byQuality) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byQuality) Less(i, j int) bool { return a[i].q > a[j].q }
+
+// negotiateLanguage returns the supported language that best matches the given Accept-Language
+// header value. A language range matches a supported language if they are equal or if the range
+// is a prefix of the language, for example "en" matches "en-US". A supported language also matches
+// a range it is a prefix of, for example "en" matches "en-GB".
+func negotiateLanguage(header string, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+	var accepted []acceptedLanguage
+	for _, part := range strings.Split(header, ",") {
+		elems := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(elems[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range elems[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			accepted = append(accepted, acceptedLanguage{tag: tag, q: q})
+		}
+	}
+	sort.Stable(byQuality(accepted))
+	for _, a := range accepted {
+		if a.tag == "*" {
+			return supported[0]
+		}
+		for _, s := range supported {
+			if strings.ToLower(s) == a.tag {
+				return s
+			}
+		}
+		for _, s := range supported {
+			l := strings.ToLower(s)
+			if strings.HasPrefix(l, a.tag+"-") || strings.HasPrefix(a.tag, l+"-") {
+				return s
+			}
+		}
+	}
+	return supported[0]
+}
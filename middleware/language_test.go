@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"net/http"
+
+	"context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Language", func() {
+	var ctx context.Context
+	var rw *testResponseWriter
+	var req *http.Request
+	var service *goa.Service
+	var lang string
+
+	BeforeEach(func() {
+		service = newService(nil)
+
+		var err error
+		req, err = http.NewRequest("GET", "/goo", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		rw = newTestResponseWriter()
+		ctx = newContext(service, rw, req, nil)
+		lang = ""
+	})
+
+	run := func(header string) {
+		if header != "" {
+			req.Header.Set("Accept-Language", header)
+		}
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			lang = middleware.ContextLanguage(ctx)
+			return nil
+		}
+		Ω(middleware.Language("en", "fr", "pt-BR")(h)(ctx, rw, req)).ShouldNot(HaveOccurred())
+	}
+
+	It("defaults to the first supported language", func() {
+		run("")
+		Ω(lang).Should(Equal("en"))
+		Ω(rw.ParentHeader.Get("Content-Language")).Should(Equal("en"))
+		Ω(rw.ParentHeader.Get("Vary")).Should(Equal("Accept-Language"))
+	})
+
+	It("picks the preferred supported language", func() {
+		run("de;q=0.9, fr;q=0.8, en;q=0.5")
+		Ω(lang).Should(Equal("fr"))
+		Ω(rw.ParentHeader.Get("Content-Language")).Should(Equal("fr"))
+	})
+
+	It("matches language prefixes", func() {
+		run("fr-CA, en;q=0.5")
+		Ω(lang).Should(Equal("fr"))
+	})
+
+	It("matches regional variants", func() {
+		run("PT")
+		Ω(lang).Should(Equal("pt-BR"))
+	})
+
+	It("ignores languages with a zero quality", func() {
+		run("fr;q=0, en-US;q=0.2")
+		Ω(lang).Should(Equal("en"))
+	})
+})
@@ -29,7 +29,7 @@ func LogRequest(verbose bool) goa.Middleware {
 			ctx = goa.WithLogContext(ctx, "req_id", reqID)
 			startedAt := time.Now()
 			r := goa.ContextRequest(ctx)
-			goa.LogInfo(ctx, "started", r.Method, r.URL.String(), "from", from(req),
+			goa.LogInfo(ctx, "started", r.Method, r.URL.String(), "from", from(ctx, req),
 				"ctrl", goa.ContextController(ctx), "action", goa.ContextAction(ctx))
 			if verbose {
 				if len(r.Header) > 0 {
@@ -96,8 +96,12 @@ func shortID() string {
 	return base64.StdEncoding.EncodeToString(b)
 }
 
-// from makes a best effort to compute the request client IP.
-func from(req *http.Request) string {
+// from makes a best effort to compute the request client IP. It uses the IP computed by the
+// ClientIP middleware if registered before.
+func from(ctx context.Context, req *http.Request) string {
+	if ip := ContextClientIP(ctx); ip != "" {
+		return ip
+	}
 	if f := req.Header.Get("X-Forwarded-For"); f != "" {
 		return f
 	}
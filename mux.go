@@ -1,8 +1,10 @@
 package goa
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/dimfeld/httptreemux"
 )
@@ -84,3 +86,59 @@ func (m *mux) Lookup(method, path string) MuxHandler {
 func (m *mux) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	m.router.ServeHTTP(rw, req)
 }
+
+// ParseMatrixParams extracts the matrix parameters from the value of the path parameter with the
+// given name. Matrix parameters are semicolon separated key value pairs appended to a path segment,
+// for example "shoes;color=red;size=large". ParseMatrixParams replaces the value of the path
+// parameter with the segment stripped of the matrix parameters and returns the matrix parameters.
+func ParseMatrixParams(params url.Values, name string) url.Values {
+	matrix := make(url.Values)
+	vals, ok := params[name]
+	if !ok || len(vals) == 0 {
+		return matrix
+	}
+	elems := strings.Split(vals[0], ";")
+	params.Set(name, elems[0])
+	for _, elem := range elems[1:] {
+		if elem == "" {
+			continue
+		}
+		kv := strings.SplitN(elem, "=", 2)
+		var val string
+		if len(kv) == 2 {
+			val = kv[1]
+		}
+		matrix.Add(kv[0], val)
+	}
+	return matrix
+}
+
+// SplitArrayParam splits the raw value of an array param into its elements. A single value that
+// starts with "[" is decoded as a JSON array, for example "[1,2,3]" or `["a","b"]`, any other
+// single value is split on commas. String elements of JSON arrays are unquoted while the other
+// elements are returned as written so that the caller may coerce them to the element type.
+// Params that occur multiple times in the request are returned unchanged.
+func SplitArrayParam(vals []string) ([]string, error) {
+	if len(vals) != 1 {
+		return vals, nil
+	}
+	val := strings.TrimSpace(vals[0])
+	if !strings.HasPrefix(val, "[") {
+		return strings.Split(vals[0], ","), nil
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal([]byte(val), &raws); err != nil {
+		return nil, err
+	}
+	elems := make([]string, len(raws))
+	for i, raw := range raws {
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &elems[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		elems[i] = string(raw)
+	}
+	return elems, nil
+}
@@ -66,4 +66,117 @@ var _ = Describe("Mux", func() {
 		})
 	})
 
+	Context("with the same handler mounted under different prefixes", func() {
+		var hits []string
+		var tenant string
+
+		BeforeEach(func() {
+			hits = nil
+			tenant = ""
+			handle := func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+				hits = append(hits, req.URL.Path)
+				tenant = vals.Get("tenant")
+			}
+			// Generated mount functions prepend the prefix to each route path.
+			for _, p := range []string{"/v1", "/t/:tenant"} {
+				mux.Handle("GET", p+"/foo/:id", handle)
+			}
+			var err error
+			req, err = http.NewRequest("GET", "/t/acme/foo/1", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("dispatches requests under each prefix", func() {
+			Ω(hits).Should(Equal([]string{"/t/acme/foo/1"}))
+			Ω(tenant).Should(Equal("acme"))
+			req, _ = http.NewRequest("GET", "/v1/foo/1", nil)
+			mux.ServeHTTP(rw, req)
+			Ω(hits).Should(Equal([]string{"/t/acme/foo/1", "/v1/foo/1"}))
+		})
+	})
+
+})
+
+var _ = Describe("ParseMatrixParams", func() {
+	var params url.Values
+	var matrix url.Values
+
+	JustBeforeEach(func() {
+		matrix = goa.ParseMatrixParams(params, "item")
+	})
+
+	Context("with matrix params", func() {
+		BeforeEach(func() {
+			params = url.Values{"item": {"shoes;color=red;size=large;size=small"}}
+		})
+
+		It("extracts the matrix params", func() {
+			Ω(params.Get("item")).Should(Equal("shoes"))
+			Ω(matrix).Should(Equal(url.Values{"color": {"red"}, "size": {"large", "small"}}))
+		})
+	})
+
+	Context("without matrix params", func() {
+		BeforeEach(func() {
+			params = url.Values{"item": {"shoes"}}
+		})
+
+		It("leaves the path param unchanged", func() {
+			Ω(params.Get("item")).Should(Equal("shoes"))
+			Ω(matrix).Should(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("SplitArrayParam", func() {
+	var vals []string
+	var elems []string
+	var err error
+
+	JustBeforeEach(func() {
+		elems, err = goa.SplitArrayParam(vals)
+	})
+
+	Context("with a JSON array", func() {
+		BeforeEach(func() {
+			vals = []string{`[1, 2, "a,b"]`}
+		})
+
+		It("decodes the elements", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(elems).Should(Equal([]string{"1", "2", "a,b"}))
+		})
+	})
+
+	Context("with comma separated values", func() {
+		BeforeEach(func() {
+			vals = []string{"1,2,a"}
+		})
+
+		It("splits the value", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(elems).Should(Equal([]string{"1", "2", "a"}))
+		})
+	})
+
+	Context("with an invalid JSON array", func() {
+		BeforeEach(func() {
+			vals = []string{"[1,2"}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("with multiple values", func() {
+		BeforeEach(func() {
+			vals = []string{"1", "2,3"}
+		})
+
+		It("returns the values unchanged", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(elems).Should(Equal(vals))
+		})
+	})
 })
@@ -1,6 +1,10 @@
 package goa
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -35,11 +39,29 @@ type (
 		Decoder *HTTPDecoder
 		// Response body encoder
 		Encoder *HTTPEncoder
+		// MountFilter, if not nil, is called by the generated Mount functions with the name
+		// of each action endpoint, e.g. "bottle.create". The actions for which it returns false
+		// are not mounted so that requests made to their routes are not found. This makes it
+		// possible to enable a subset of the actions at runtime.
+		MountFilter func(endpointName string) bool
+		// ParamValidators lists the validators referenced by the "validation:func" metadata
+		// of the design params indexed by name. The generated contexts call the validator
+		// with the param value after coercing it so that the value may be checked against
+		// data only known at runtime such as a configurable allowlist.
+		ParamValidators map[string]ParamValidator
+		// MaxDecompressedBodyLength is the maximum length of gzip encoded request bodies once
+		// decompressed. Longer bodies are rejected with ErrRequestBodyTooLarge. Set to 0 to
+		// remove the limit altogether. Defaults to 1GB.
+		MaxDecompressedBodyLength int64
 
 		middleware []Middleware       // Middleware chain
 		cancel     context.CancelFunc // Service context cancel signal trigger
 	}
 
+	// ParamValidator validates the value of a request parameter. The value has the Go type of
+	// the parameter, for example string or []int.
+	ParamValidator func(v interface{}) error
+
 	// Controller defines the common fields and behavior of generated controllers.
 	Controller struct {
 		// Controller resource name
@@ -51,8 +73,18 @@ type (
 		// MaxRequestBodyLength is the maximum length read from request bodies.
 		// Set to 0 to remove the limit altogether. Defaults to 1GB.
 		MaxRequestBodyLength int64
-
-		middleware []Middleware // Controller specific middleware if any
+		// MaxQueryParams is the maximum number of query string parameters accepted by the
+		// controller actions. Requests with more parameters are rejected with a bad request
+		// error before their body is read and their params coerced into the action context.
+		// Note that the default mux decodes the query string prior to the check. Defaults to 0
+		// which removes the limit.
+		MaxQueryParams int
+		// ContinueHandler if not nil is called prior to reading the body of requests that
+		// include the "Expect: 100-continue" header.
+		ContinueHandler ContinueHandler
+
+		middleware       []Middleware            // Controller specific middleware if any
+		actionMiddleware map[string][]Middleware // Action specific middleware if any
 	}
 
 	// FileServer is the interface implemented by controllers that can serve static files.
@@ -64,6 +96,14 @@ type (
 	// Handler defines the request handler signatures.
 	Handler func(context.Context, http.ResponseWriter, *http.Request) error
 
+	// ContinueHandler inspects the headers of requests sent with the "Expect: 100-continue"
+	// header before their body is read. The net/http server sends the "100 Continue" interim
+	// response the first time the body is read, so returning nil lets the client upload the
+	// body while returning an error - for example ErrRequestBodyTooLarge or ErrUnauthorized -
+	// rejects the request before the body is sent: the error is recorded in the request context
+	// in place of the payload decoding error and the action handler returns it.
+	ContinueHandler func(context.Context, *http.Request) error
+
 	// Unmarshaler defines the request payload unmarshaler signatures.
 	Unmarshaler func(context.Context, *Service, *http.Request) error
 
@@ -85,6 +125,8 @@ func New(name string) *Service {
 			Decoder: NewHTTPDecoder(),
 			Encoder: NewHTTPEncoder(),
 
+			MaxDecompressedBodyLength: 1073741824, // 1 GB
+
 			cancel: cancel,
 		}
 		notFoundHandler Handler
@@ -123,6 +165,30 @@ func (service *Service) CancelAll() {
 	service.cancel()
 }
 
+// Mounts returns true if the action with the given endpoint name must be mounted, that is if the
+// service MountFilter is nil or returns true.
+func (service *Service) Mounts(endpointName string) bool {
+	return service.MountFilter == nil || service.MountFilter(endpointName)
+}
+
+// ValidateParam runs the param validator registered under the given name with the value v of the
+// param with the given name. The error returned by the validator is sent back as a bad request
+// error. ValidateParam returns an internal error if no validator is registered under the name.
+func (service *Service) ValidateParam(validator, param string, v interface{}) error {
+	fn, ok := service.ParamValidators[validator]
+	if !ok {
+		return ErrInternal(fmt.Sprintf("no param validator registered under %q", validator))
+	}
+	if err := fn(v); err != nil {
+		if _, ok := err.(*ErrorResponse); ok {
+			return err
+		}
+		msg := fmt.Sprintf("invalid value %#v for parameter %#v: %s", v, param, err)
+		return ErrInvalidRequest(msg, "param", param, "value", v)
+	}
+	return nil
+}
+
 // Use adds a middleware to the service wide middleware chain.
 // goa comes with a set of commonly used middleware, see the middleware package.
 // Controller specific middleware should be mounted using the Controller struct Use method instead.
@@ -194,18 +260,89 @@ func (service *Service) ServeFiles(path, filename string) error {
 }
 
 // DecodeRequest uses the HTTP decoder to unmarshal the request body into the provided value based
-// on the request Content-Type header.
+// on the request Content-Type header. Request bodies whose Content-Encoding header is "gzip" are
+// decompressed prior to being decoded, corrupt streams cause DecodeRequest to return an error and
+// decompressed bodies longer than MaxDecompressedBodyLength an ErrRequestBodyTooLarge error.
 func (service *Service) DecodeRequest(req *http.Request, v interface{}) error {
+	_, err := service.decodeRequest(req, v, false)
+	return err
+}
+
+// DecodeNullableRequest behaves like DecodeRequest but returns true without decoding the request
+// body if it consists of the JSON null literal. The generated code uses it to handle a null body
+// as a missing payload.
+func (service *Service) DecodeNullableRequest(req *http.Request, v interface{}) (bool, error) {
+	return service.decodeRequest(req, v, true)
+}
+
+// decodeRequest implements DecodeRequest and DecodeNullableRequest.
+func (service *Service) decodeRequest(req *http.Request, v interface{}, nullable bool) (bool, error) {
 	body, contentType := req.Body, req.Header.Get("Content-Type")
 	defer body.Close()
 
-	if err := service.Decoder.Decode(v, body, contentType); err != nil {
-		return fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
+	var r io.Reader = body
+	var lr *limitedReader
+	if strings.EqualFold(strings.TrimSpace(req.Header.Get("Content-Encoding")), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return false, fmt.Errorf("failed to decompress gzip request body: %s", err)
+		}
+		defer gz.Close()
+		r = gz
+		if service.MaxDecompressedBodyLength > 0 {
+			lr = &limitedReader{r: gz, n: service.MaxDecompressedBodyLength}
+			r = lr
+		}
 	}
 
-	return nil
+	if nullable {
+		// Peek at the beginning of the body so that the decoders still read all of it.
+		br := bufio.NewReaderSize(r, nullPeekSize)
+		if b, err := br.Peek(nullPeekSize); err != nil && bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+			return true, nil
+		}
+		r = br
+	}
+
+	if err := service.Decoder.Decode(v, r, contentType); err != nil {
+		if lr != nil && lr.n < 0 {
+			msg := fmt.Sprintf("decompressed request body length exceeds %d bytes", service.MaxDecompressedBodyLength)
+			return false, ErrRequestBodyTooLarge(msg)
+		}
+		return false, fmt.Errorf("failed to decode request body with content type %#v: %s", contentType, err)
+	}
+
+	return false, nil
 }
 
+// nullPeekSize is the number of bytes DecodeNullableRequest peeks at to detect a null body.
+const nullPeekSize = 512
+
+// limitedReader reads from r until more than n bytes are read, it then fails and n is negative.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader.
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errBodyTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n - 1, errBodyTooLarge
+	}
+	return n, err
+}
+
+// errBodyTooLarge is the error returned by limitedReader once the limit is exceeded.
+var errBodyTooLarge = errors.New("request body too large")
+
 // EncodeResponse uses the HTTP encoder to marshal and write the response body based on the request
 // Accept header.
 func (service *Service) EncodeResponse(ctx context.Context, v interface{}) error {
@@ -236,9 +373,22 @@ func (ctrl *Controller) Use(m Middleware) {
 	ctrl.middleware = append(ctrl.middleware, m)
 }
 
+// UseAction adds a middleware to the controller action with the given name (the name used in the
+// design). Action middleware runs after the service and controller middleware in the order it
+// was added.
+func (ctrl *Controller) UseAction(name string, m Middleware) {
+	if ctrl.actionMiddleware == nil {
+		ctrl.actionMiddleware = make(map[string][]Middleware)
+	}
+	ctrl.actionMiddleware[name] = append(ctrl.actionMiddleware[name], m)
+}
+
 // MuxHandler wraps a request handler into a MuxHandler. The MuxHandler initializes the request
 // context by loading the request state, invokes the handler and in case of error invokes the
 // controller (if there is one) or Service error handler.
+// The middleware chain consists of the service middleware followed by the controller middleware
+// and the action middleware. The chain runs after the request payload has been loaded and wraps
+// the handler which builds the action context and invokes the controller action.
 // This function is intended for the controller generated code. User code should not need to call
 // it directly.
 func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) MuxHandler {
@@ -255,7 +405,10 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 				}
 				return nil
 			}
-			chain := append(ctrl.Service.middleware, ctrl.middleware...)
+			var chain []Middleware
+			chain = append(chain, ctrl.Service.middleware...)
+			chain = append(chain, ctrl.middleware...)
+			chain = append(chain, ctrl.actionMiddleware[name]...)
 			ml := len(chain)
 			for i := range chain {
 				handler = chain[ml-i-1](handler)
@@ -270,15 +423,43 @@ func (ctrl *Controller) MuxHandler(name string, hdlr Handler, unm Unmarshaler) M
 			req.Body = http.MaxBytesReader(rw, req.Body, ctrl.MaxRequestBodyLength)
 		}
 
-		// Load body if any
-		if req.ContentLength > 0 && unm != nil {
-			if err := unm(ctx, ctrl.Service, req); err != nil {
-				if err.Error() == "http: request body too large" {
-					msg := fmt.Sprintf("request body length exceeds %d bytes", ctrl.MaxRequestBodyLength)
-					err = ErrRequestBodyTooLarge(msg)
-				} else {
-					err = ErrBadRequest(err)
+		// Reject requests with too many query string parameters
+		if ctrl.MaxQueryParams > 0 {
+			if n := queryParamCount(req.URL.RawQuery); n > ctrl.MaxQueryParams {
+				msg := fmt.Sprintf("request has %d query string parameters, maximum is %d", n, ctrl.MaxQueryParams)
+				ctx = WithError(ctx, ErrBadRequest(msg))
+			}
+		}
+
+		// Load body if any, the length of chunked bodies is unknown (-1)
+		if req.ContentLength != 0 && unm != nil && ContextError(ctx) == nil {
+			var err error
+			if ctrl.ContinueHandler != nil && strings.EqualFold(req.Header.Get("Expect"), "100-continue") {
+				// Give a chance to reject the request before the client sends the body
+				err = ctrl.ContinueHandler(ctx, req)
+			}
+			if err == nil {
+				hasBody := true
+				if req.ContentLength < 0 {
+					// Only a chunked body that turns out to be empty is handled as a
+					// missing payload
+					hasBody, err = peekBody(req)
+				}
+				if err == nil && hasBody {
+					err = unm(ctx, ctrl.Service, req)
 				}
+				if err != nil {
+					if err.Error() == "http: request body too large" {
+						msg := fmt.Sprintf("request body length exceeds %d bytes", ctrl.MaxRequestBodyLength)
+						err = ErrRequestBodyTooLarge(msg)
+					} else if _, ok := err.(ServiceError); !ok {
+						// Keep goa errors such as validation errors intact so that their
+						// attribute level details are reported to the client.
+						err = ErrBadRequest(err)
+					}
+				}
+			}
+			if err != nil {
 				ctx = WithError(ctx, err)
 			}
 		}
@@ -398,3 +579,36 @@ type byName []os.FileInfo
 func (s byName) Len() int           { return len(s) }
 func (s byName) Less(i, j int) bool { return s[i].Name() < s[j].Name() }
 func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// peekBody returns true if the request body is not empty. The body is left intact so that it can
+// be decoded afterwards.
+func peekBody(req *http.Request) (bool, error) {
+	var b [1]byte
+	n, err := req.Body.Read(b[:])
+	for n == 0 && err == nil {
+		n, err = req.Body.Read(b[:])
+	}
+	if n == 0 {
+		if err == io.EOF {
+			return false, nil
+		}
+		return false, err
+	}
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b[:n]), req.Body), req.Body}
+	return true, nil
+}
+
+// queryParamCount returns the number of parameters in the given raw query string without decoding
+// it. Empty pairs such as the ones produced by "a=1&&b=2&" are not counted.
+func queryParamCount(rawQuery string) int {
+	count := 0
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair != "" {
+			count++
+		}
+	}
+	return count
+}
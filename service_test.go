@@ -2,6 +2,7 @@ package goa_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -83,6 +84,55 @@ var _ = Describe("Service", func() {
 		})
 	})
 
+	Describe("Mounts", func() {
+		It("mounts all actions by default", func() {
+			Ω(s.Mounts("bottle.show")).Should(BeTrue())
+		})
+
+		Context("with a mount filter", func() {
+			BeforeEach(func() {
+				s.MountFilter = func(endpoint string) bool {
+					return endpoint != "bottle.delete"
+				}
+			})
+
+			It("mounts only the actions accepted by the filter", func() {
+				Ω(s.Mounts("bottle.show")).Should(BeTrue())
+				Ω(s.Mounts("bottle.delete")).Should(BeFalse())
+			})
+		})
+	})
+
+	Describe("ValidateParam", func() {
+		BeforeEach(func() {
+			s.ParamValidators = map[string]goa.ParamValidator{
+				"region": func(v interface{}) error {
+					if v.(string) != "us-east" {
+						return fmt.Errorf("unknown region")
+					}
+					return nil
+				},
+			}
+		})
+
+		It("accepts values accepted by the validator", func() {
+			Ω(s.ValidateParam("region", "reg", "us-east")).ShouldNot(HaveOccurred())
+		})
+
+		It("returns a bad request error for values rejected by the validator", func() {
+			err := s.ValidateParam("region", "reg", "mars")
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(400))
+			Ω(err.Error()).Should(ContainSubstring("unknown region"))
+		})
+
+		It("returns an internal error if the validator is not registered", func() {
+			err := s.ValidateParam("zone", "reg", "mars")
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(goa.ServiceError).ResponseStatus()).Should(Equal(500))
+		})
+	})
+
 	Describe("MaxRequestBodyLength", func() {
 		var rw *TestResponseWriter
 		var req *http.Request
@@ -115,6 +165,255 @@ var _ = Describe("Service", func() {
 		})
 	})
 
+	Describe("MaxQueryParams", func() {
+		var rawQuery string
+		var rw *TestResponseWriter
+		var unmarshaled bool
+		var ctxErr error
+
+		BeforeEach(func() {
+			rawQuery = "a=1&b=2&a=3"
+		})
+
+		JustBeforeEach(func() {
+			unmarshaled = false
+			ctxErr = nil
+			rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+			req, _ := http.NewRequest("POST", "/foo?"+rawQuery, bytes.NewBufferString(`"234"`))
+			ctrl := s.NewController("test")
+			ctrl.MaxQueryParams = 2
+			unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+				unmarshaled = true
+				return nil
+			}
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				ctxErr = goa.ContextError(ctx)
+				return nil
+			}
+			ctrl.MuxHandler("testQuery", handler, unmarshaler)(rw, req, nil)
+		})
+
+		It("rejects requests with too many query params before reading the body", func() {
+			Ω(unmarshaled).Should(BeFalse())
+			Ω(ctxErr).Should(HaveOccurred())
+			Ω(ctxErr.(goa.ServiceError).ResponseStatus()).Should(Equal(400))
+			Ω(ctxErr.Error()).Should(ContainSubstring("request has 3 query string parameters, maximum is 2"))
+		})
+
+		Context("with empty pairs in the query string", func() {
+			BeforeEach(func() {
+				rawQuery = "&a=1&&b=2&"
+			})
+
+			It("does not count the empty pairs", func() {
+				Ω(unmarshaled).Should(BeTrue())
+				Ω(ctxErr).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("with an unmarshaler rejecting the request body length", func() {
+		var rw *TestResponseWriter
+
+		BeforeEach(func() {
+			rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+			req, _ := http.NewRequest("POST", "/foo", bytes.NewBufferString(`"234"`))
+			ctrl := s.NewController("test")
+			unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+				return goa.ErrRequestBodyTooLarge("request body length exceeds 2 bytes")
+			}
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				rw.WriteHeader(400)
+				rw.Write([]byte(goa.ContextError(ctx).Error()))
+				return nil
+			}
+			ctrl.MuxHandler("testLength", handler, unmarshaler)(rw, req, nil)
+		})
+
+		It("keeps the request too large error", func() {
+			Ω(string(rw.Body)).Should(MatchRegexp(`\[.*\] 413 request_too_large: request body length exceeds 2 bytes`))
+		})
+	})
+
+	Describe("with an unmarshaler returning a validation error", func() {
+		var ctxErr error
+
+		BeforeEach(func() {
+			rw := &TestResponseWriter{ParentHeader: make(http.Header)}
+			req, _ := http.NewRequest("POST", "/foo", bytes.NewBufferString(`{}`))
+			ctrl := s.NewController("test")
+			unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+				return goa.MissingAttributeError(`raw`, "name")
+			}
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				ctxErr = goa.ContextError(ctx)
+				return nil
+			}
+			ctrl.MuxHandler("testValidation", handler, unmarshaler)(rw, req, nil)
+		})
+
+		It("keeps the attribute level details of the error", func() {
+			Ω(ctxErr).Should(BeAssignableToTypeOf(&goa.ErrorResponse{}))
+			resp := ctxErr.(*goa.ErrorResponse)
+			Ω(resp.Code).Should(Equal("invalid_request"))
+			Ω(resp.Meta).Should(HaveKeyWithValue("attribute", "name"))
+		})
+	})
+
+	Describe("with a payload whose hand-written Validate returns an error", func() {
+		var ctxErr error
+
+		BeforeEach(func() {
+			rw := &TestResponseWriter{ParentHeader: make(http.Header)}
+			req, _ := http.NewRequest("POST", "/foo", bytes.NewBufferString(`{"start":2,"end":1}`))
+			ctrl := s.NewController("test")
+			unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+				pub := &rangePayload{Start: 2, End: 1}
+				goa.ContextRequest(ctx).Payload = pub
+				if v, ok := interface{}(pub).(interface {
+					Validate() error
+				}); ok {
+					return v.Validate()
+				}
+				return nil
+			}
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				ctxErr = goa.ContextError(ctx)
+				return nil
+			}
+			ctrl.MuxHandler("testHook", handler, unmarshaler)(rw, req, nil)
+		})
+
+		It("responds with a bad request error", func() {
+			Ω(ctxErr).Should(BeAssignableToTypeOf(&goa.ErrorResponse{}))
+			resp := ctxErr.(*goa.ErrorResponse)
+			Ω(resp.Status).Should(Equal(http.StatusBadRequest))
+			Ω(resp.Detail).Should(Equal("end must be greater than start"))
+		})
+	})
+
+	Describe("with a middleware reading the payload", func() {
+		var payload interface{}
+
+		BeforeEach(func() {
+			payload = nil
+			rw := &TestResponseWriter{ParentHeader: make(http.Header)}
+			req, _ := http.NewRequest("POST", "/foo", bytes.NewBufferString(`"234"`))
+			ctrl := s.NewController("test")
+			ctrl.Use(func(h goa.Handler) goa.Handler {
+				return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+					payload = goa.ContextRequest(ctx).Payload
+					return h(ctx, rw, req)
+				}
+			})
+			unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+				goa.ContextRequest(ctx).Payload = "234"
+				return nil
+			}
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return nil
+			}
+			ctrl.MuxHandler("testPayload", handler, unmarshaler)(rw, req, nil)
+		})
+
+		It("sees the decoded payload", func() {
+			Ω(payload).Should(Equal("234"))
+		})
+	})
+
+	Describe("ContinueHandler", func() {
+		var rw *TestResponseWriter
+		var req *http.Request
+		var unmarshaled bool
+
+		BeforeEach(func() {
+			unmarshaled = false
+			rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+			req, _ = http.NewRequest("PUT", "/foo", bytes.NewBufferString(`"234"`))
+			req.Header.Set("Expect", "100-continue")
+		})
+
+		JustBeforeEach(func() {
+			ctrl := s.NewController("test")
+			ctrl.ContinueHandler = func(ctx context.Context, req *http.Request) error {
+				if req.Header.Get("Authorization") == "" {
+					return goa.ErrUnauthorized("missing credentials")
+				}
+				return nil
+			}
+			unmarshaler := func(ctx context.Context, service *goa.Service, req *http.Request) error {
+				unmarshaled = true
+				return nil
+			}
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				if err := goa.ContextError(ctx); err != nil {
+					rw.WriteHeader(err.(goa.ServiceError).ResponseStatus())
+					return nil
+				}
+				rw.WriteHeader(200)
+				return nil
+			}
+			ctrl.MuxHandler("upload", handler, unmarshaler)(rw, req, nil)
+		})
+
+		It("rejects the request before reading the body", func() {
+			Ω(unmarshaled).Should(BeFalse())
+			Ω(rw.Status).Should(Equal(401))
+		})
+
+		Context("when the handler accepts the request", func() {
+			BeforeEach(func() {
+				req.Header.Set("Authorization", "Bearer token")
+			})
+
+			It("reads the body", func() {
+				Ω(unmarshaled).Should(BeTrue())
+				Ω(rw.Status).Should(Equal(200))
+			})
+		})
+
+		Context("when the request does not expect 100-continue", func() {
+			BeforeEach(func() {
+				req.Header.Del("Expect")
+			})
+
+			It("does not call the handler", func() {
+				Ω(unmarshaled).Should(BeTrue())
+				Ω(rw.Status).Should(Equal(200))
+			})
+		})
+	})
+
+	Describe("UseAction", func() {
+		var calls []string
+		var rw *TestResponseWriter
+
+		BeforeEach(func() {
+			calls = nil
+			rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+		})
+
+		JustBeforeEach(func() {
+			ctrl := s.NewController("test")
+			s.Use(RecordMiddleware("service", &calls))
+			ctrl.Use(RecordMiddleware("controller", &calls))
+			ctrl.UseAction("act", RecordMiddleware("action1", &calls))
+			ctrl.UseAction("act", RecordMiddleware("action2", &calls))
+			ctrl.UseAction("other", RecordMiddleware("other", &calls))
+			handler := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				calls = append(calls, "handler")
+				return nil
+			}
+			req, err := http.NewRequest("GET", "/foo", nil)
+			Ω(err).ShouldNot(HaveOccurred())
+			ctrl.MuxHandler("act", handler, nil)(rw, req, nil)
+		})
+
+		It("runs the action middleware last in the order it was added", func() {
+			Ω(calls).Should(Equal([]string{"service", "controller", "action1", "action2", "handler"}))
+		})
+	})
+
 	Describe("MuxHandler", func() {
 		var handler goa.Handler
 		var unmarshaler goa.Unmarshaler
@@ -258,6 +557,70 @@ var _ = Describe("Service", func() {
 				})
 			})
 
+			Context("with a chunked body", func() {
+				BeforeEach(func() {
+					r.Header.Set("Content-Type", "application/json")
+					r.Header.Set("Transfer-Encoding", "chunked")
+					r.Body = ioutil.NopCloser(bytes.NewReader([]byte(`{"hello": "world"}`)))
+					r.ContentLength = -1
+				})
+
+				It("decodes the body", func() {
+					Ω(rw.(*TestResponseWriter).Status).Should(Equal(respStatus))
+					Ω(goa.ContextRequest(ctx).Payload).Should(Equal(map[string]interface{}{"hello": "world"}))
+				})
+
+				Context("that is empty", func() {
+					BeforeEach(func() {
+						r.Body = ioutil.NopCloser(bytes.NewReader(nil))
+					})
+
+					It("leaves the payload unset", func() {
+						Ω(rw.(*TestResponseWriter).Status).Should(Equal(respStatus))
+						Ω(goa.ContextRequest(ctx).Payload).Should(BeNil())
+					})
+				})
+			})
+
+			Context("with a null JSON body", func() {
+				type payload struct {
+					Hello *string `json:"hello"`
+				}
+
+				BeforeEach(func() {
+					r.Header.Set("Content-Type", "application/json")
+					r.Body = ioutil.NopCloser(bytes.NewReader([]byte("null")))
+					r.ContentLength = 4
+					// Same decoding as the generated payload unmarshal functions
+					unmarshaler = func(c context.Context, service *goa.Service, req *http.Request) error {
+						p := &payload{}
+						if null, err := service.DecodeNullableRequest(req, p); err != nil || null {
+							return err
+						}
+						goa.ContextRequest(c).Payload = p
+						return nil
+					}
+				})
+
+				It("leaves the payload unset", func() {
+					Ω(rw.(*TestResponseWriter).Status).Should(Equal(respStatus))
+					Ω(goa.ContextRequest(ctx).Payload).Should(BeNil())
+				})
+
+				Context("with a body that is not null", func() {
+					BeforeEach(func() {
+						r.Body = ioutil.NopCloser(bytes.NewReader([]byte(` {"hello": "null"}`)))
+						r.ContentLength = 18
+					})
+
+					It("decodes the body into the given value", func() {
+						Ω(rw.(*TestResponseWriter).Status).Should(Equal(respStatus))
+						hello := "null"
+						Ω(goa.ContextRequest(ctx).Payload).Should(Equal(&payload{Hello: &hello}))
+					})
+				})
+			})
+
 			Context("with different payload types", func() {
 				content := []byte(`{"hello": "world"}`)
 				decodedContent := map[string]interface{}{"hello": "world"}
@@ -272,6 +635,51 @@ var _ = Describe("Service", func() {
 					Ω(goa.ContextRequest(ctx).Payload).Should(Equal(decodedContent))
 				})
 
+				Context("with a gzip Content-Encoding", func() {
+					BeforeEach(func() {
+						var buf bytes.Buffer
+						gz := gzip.NewWriter(&buf)
+						gz.Write(content)
+						gz.Close()
+						r.Header.Set("Content-Encoding", "gzip")
+						r.Body = ioutil.NopCloser(&buf)
+						r.ContentLength = int64(buf.Len())
+					})
+
+					It("decompresses the body prior to decoding it", func() {
+						Ω(rw.(*TestResponseWriter).Status).Should(Equal(respStatus))
+						Ω(goa.ContextRequest(ctx).Payload).Should(Equal(decodedContent))
+					})
+				})
+
+				Context("with a gzip Content-Encoding and a body exceeding the decompressed limit", func() {
+					BeforeEach(func() {
+						var buf bytes.Buffer
+						gz := gzip.NewWriter(&buf)
+						gz.Write(content)
+						gz.Close()
+						r.Header.Set("Content-Encoding", "gzip")
+						r.Body = ioutil.NopCloser(&buf)
+						r.ContentLength = int64(buf.Len())
+						s.MaxDecompressedBodyLength = int64(len(content) - 1)
+					})
+
+					It("records a request too large error", func() {
+						Ω(string(rw.(*TestResponseWriter).Body)).Should(ContainSubstring("413 request_too_large: decompressed request body length exceeds 17 bytes"))
+					})
+				})
+
+				Context("with a gzip Content-Encoding and a corrupt body", func() {
+					BeforeEach(func() {
+						r.Header.Set("Content-Encoding", "gzip")
+					})
+
+					It("triggers the error handler", func() {
+						Ω(rw.(*TestResponseWriter).Status).Should(Equal(400))
+						Ω(string(rw.(*TestResponseWriter).Body)).Should(ContainSubstring("failed to decompress"))
+					})
+				})
+
 				Context("with an empty Content-Type", func() {
 					BeforeEach(func() {
 						delete(r.Header, "Content-Type")
@@ -339,6 +747,15 @@ func CMiddleware(witness *int) goa.Middleware {
 	}
 }
 
+func RecordMiddleware(name string, calls *[]string) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			*calls = append(*calls, name)
+			return h(ctx, rw, req)
+		}
+	}
+}
+
 func SecondMiddleware(witness1, witness2 *bool) goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
 		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
@@ -351,6 +768,18 @@ func SecondMiddleware(witness1, witness2 *bool) goa.Middleware {
 	}
 }
 
+type rangePayload struct {
+	Start int
+	End   int
+}
+
+func (p *rangePayload) Validate() error {
+	if p.End <= p.Start {
+		return fmt.Errorf("end must be greater than start")
+	}
+	return nil
+}
+
 type TestResponseWriter struct {
 	ParentHeader http.Header
 	Body         []byte
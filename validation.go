@@ -1,6 +1,7 @@
 package goa
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net"
 	"net/mail"
@@ -8,6 +9,7 @@ import (
 	"regexp"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/goadesign/goa/uuid"
 )
@@ -19,6 +21,9 @@ const (
 	// FormatDateTime defines RFC3339 date time values.
 	FormatDateTime Format = "date-time"
 
+	// FormatDate defines RFC3339 full-date values.
+	FormatDate Format = "date"
+
 	// FormatUUID defines RFC4122 uuid values.
 	FormatUUID Format = "uuid"
 
@@ -51,6 +56,32 @@ const (
 
 	// FormatRFC1123 defines RFC1123 date time values.
 	FormatRFC1123 = "rfc1123"
+
+	// FormatByte defines RFC4648 standard base64 encoded binary values.
+	FormatByte = "byte"
+)
+
+// Charset defines a named class of characters used to validate the runes of string values.
+type Charset string
+
+const (
+	// CharsetAlpha defines the ASCII letters.
+	CharsetAlpha Charset = "alpha"
+
+	// CharsetNumeric defines the ASCII digits.
+	CharsetNumeric Charset = "numeric"
+
+	// CharsetAlphanumeric defines the ASCII letters and digits.
+	CharsetAlphanumeric Charset = "alphanumeric"
+
+	// CharsetHex defines the hexadecimal digits, both lower and upper case.
+	CharsetHex Charset = "hex"
+
+	// CharsetASCII defines the ASCII characters.
+	CharsetASCII Charset = "ascii"
+
+	// CharsetPrintable defines the printable Unicode characters including the ASCII space.
+	CharsetPrintable Charset = "printable"
 )
 
 var (
@@ -68,6 +99,7 @@ var (
 // Supported formats are:
 //
 //     - "date-time": RFC3339 date time value
+//     - "date": RFC3339 full-date value
 //     - "email": RFC5322 email address
 //     - "hostname": RFC1035 Internet host name
 //     - "ipv4", "ipv6", "ip": RFC2673 and RFC2373 IP address values
@@ -76,11 +108,14 @@ var (
 //     - "cidr": RFC4632 and RFC4291 CIDR notation IP address value
 //     - "regexp": Regular expression syntax accepted by RE2
 //     - "rfc1123": RFC1123 date time value
+//     - "byte": RFC4648 standard base64 encoded value
 func ValidateFormat(f Format, val string) error {
 	var err error
 	switch f {
 	case FormatDateTime:
 		_, err = time.Parse(time.RFC3339, val)
+	case FormatDate:
+		_, err = time.Parse("2006-01-02", val)
 	case FormatUUID:
 		_, err = uuid.FromString(val)
 	case FormatEmail:
@@ -115,6 +150,8 @@ func ValidateFormat(f Format, val string) error {
 		_, err = regexp.Compile(val)
 	case FormatRFC1123:
 		_, err = time.Parse(time.RFC1123, val)
+	case FormatByte:
+		_, err = base64.StdEncoding.DecodeString(val)
 	default:
 		return fmt.Errorf("unknown format %#v", f)
 	}
@@ -133,6 +170,28 @@ var knownPatterns = make(map[string]*regexp.Regexp)
 // knownPatternsLock is the mutex used to access knownPatterns
 var knownPatternsLock = &sync.RWMutex{}
 
+// InCharset returns true if r belongs to the given character class. It panics if the character
+// class is unknown (DSL validation makes sure the class is valid).
+func InCharset(c Charset, r rune) bool {
+	isAlpha := ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z')
+	isDigit := '0' <= r && r <= '9'
+	switch c {
+	case CharsetAlpha:
+		return isAlpha
+	case CharsetNumeric:
+		return isDigit
+	case CharsetAlphanumeric:
+		return isAlpha || isDigit
+	case CharsetHex:
+		return isDigit || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F')
+	case CharsetASCII:
+		return r <= unicode.MaxASCII
+	case CharsetPrintable:
+		return unicode.IsPrint(r)
+	}
+	panic(fmt.Sprintf("unknown character class %#v", c))
+}
+
 // ValidatePattern returns an error if val does not match the regular expression p.
 // It makes an effort to minimize the number of times the regular expression needs to be compiled.
 func ValidatePattern(p string, val string) bool {
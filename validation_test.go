@@ -45,6 +45,32 @@ var _ = Describe("ValidateFormat", func() {
 		})
 	})
 
+	Context("Date", func() {
+		BeforeEach(func() {
+			f = goa.FormatDate
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				val = "2015-10-26T08:31:23Z"
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a valid value", func() {
+			BeforeEach(func() {
+				val = "2015-10-26"
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
 	Context("UUID", func() {
 		BeforeEach(func() {
 			f = goa.FormatUUID
@@ -343,6 +369,32 @@ var _ = Describe("ValidateFormat", func() {
 
 	})
 
+	Context("Byte", func() {
+		BeforeEach(func() {
+			f = goa.FormatByte
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				val = "Z29h!"
+			})
+
+			It("does not validate", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a valid value", func() {
+			BeforeEach(func() {
+				val = "Z29hIGRlc2lnbg=="
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
 	Context("RFC1123", func() {
 		BeforeEach(func() {
 			f = goa.FormatRFC1123
@@ -370,3 +422,22 @@ var _ = Describe("ValidateFormat", func() {
 		})
 	})
 })
+
+var _ = Describe("InCharset", func() {
+	It("accepts the characters of the class", func() {
+		for _, r := range "azAZ09" {
+			Ω(goa.InCharset(goa.CharsetAlphanumeric, r)).Should(BeTrue())
+		}
+		Ω(goa.InCharset(goa.CharsetHex, 'F')).Should(BeTrue())
+		Ω(goa.InCharset(goa.CharsetPrintable, 'é')).Should(BeTrue())
+	})
+
+	It("rejects the characters outside of the class", func() {
+		for _, r := range "-_ é" {
+			Ω(goa.InCharset(goa.CharsetAlphanumeric, r)).Should(BeFalse())
+		}
+		Ω(goa.InCharset(goa.CharsetNumeric, 'a')).Should(BeFalse())
+		Ω(goa.InCharset(goa.CharsetASCII, 'é')).Should(BeFalse())
+		Ω(goa.InCharset(goa.CharsetPrintable, '\n')).Should(BeFalse())
+	})
+})